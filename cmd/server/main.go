@@ -4,6 +4,14 @@ import (
 	"stock-management/initialize"
 )
 
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description JWT access token from /api/auth/login, as "Bearer <token>"
+
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
 func main() {
 	initialize.Run()
 }
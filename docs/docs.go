@@ -15,9 +15,17 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/product-category/create": {
+        "/api/api-key/create": {
             "post": {
-                "description": "Creates a new product category and returns the created product category details",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates an API key for machine clients of the current user. The key is only returned once.",
                 "consumes": [
                     "application/json"
                 ],
@@ -25,33 +33,41 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ProductCategory"
+                    "Auth"
                 ],
-                "summary": "Create product category",
+                "summary": "Create API key",
                 "parameters": [
                     {
-                        "description": "ProductCategory creation details",
+                        "description": "API key details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductCategoryCreateReq"
+                            "$ref": "#/definitions/models.ApiKeyCreateReq"
                         }
                     }
                 ],
                 "responses": {
-                    "201": {
-                        "description": "Created",
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProductCategory"
+                            "$ref": "#/definitions/models.ApiKeyCreateRp"
                         }
                     }
                 }
             }
         },
-        "/api/product-category/list": {
+        "/api/api-key/list": {
             "post": {
-                "description": "Returns a list of product categorys matching the search request",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the API keys of the current user without their secret",
                 "consumes": [
                     "application/json"
                 ],
@@ -59,17 +75,93 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "ProductCategory"
+                    "Auth"
                 ],
-                "summary": "Retrieve product category list",
+                "summary": "Retrieve API key list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ApiKey"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/api-key/revoke": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Revokes an API key of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Revoke API key",
                 "parameters": [
                     {
-                        "description": "ProductCategory search details",
+                        "description": "API key ID",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductCategorySearchReq"
+                            "$ref": "#/definitions/models.ApiKeyRevokeReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/audit/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns audit log entries of product, supplier and category writes, newest first, filtered by entity, actor and date range",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Audit"
+                ],
+                "summary": "Retrieve audit log",
+                "parameters": [
+                    {
+                        "description": "Search parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AuditSearchReq"
                         }
                     }
                 ],
@@ -83,9 +175,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/product/create": {
+        "/api/auth/login": {
             "post": {
-                "description": "Adds a new product to the inventory",
+                "description": "Exchanges a username and password for a signed JWT access token and a refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -93,17 +185,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Auth"
                 ],
-                "summary": "Create new product",
+                "summary": "Login",
                 "parameters": [
                     {
-                        "description": "Product creation details",
+                        "description": "Credentials",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductCreateReq"
+                            "$ref": "#/definitions/models.LoginReq"
                         }
                     }
                 ],
@@ -111,15 +203,15 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/models.TokenRp"
                         }
                     }
                 }
             }
         },
-        "/api/product/distance": {
+        "/api/auth/logout": {
             "post": {
-                "description": "Returns the distance of a product specified by its ID",
+                "description": "Revokes the given refresh token",
                 "consumes": [
                     "application/json"
                 ],
@@ -127,17 +219,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Auth"
                 ],
-                "summary": "Calculate the distance",
+                "summary": "Logout",
                 "parameters": [
                     {
-                        "description": "Product distance request details",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductDistanceByIdReq"
+                            "$ref": "#/definitions/models.RefreshTokenReq"
                         }
                     }
                 ],
@@ -145,33 +237,64 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProductDistanceRp"
+                            "$ref": "#/definitions/response.ResponseData"
                         }
                     }
                 }
             }
         },
-        "/api/product/export": {
+        "/api/auth/me": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the user authenticated by the access token or API key",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Current user",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AuthUser"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/auth/refresh": {
             "post": {
-                "description": "Generates and returns a PDF file containing the list of products",
+                "description": "Exchanges a refresh token for a new access token and refresh token. Each refresh token can be used once.",
                 "consumes": [
                     "application/json"
                 ],
                 "produces": [
-                    "application/pdf"
+                    "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Auth"
                 ],
-                "summary": "Export product list to PDF",
+                "summary": "Refresh tokens",
                 "parameters": [
                     {
-                        "description": "Product search details",
+                        "description": "Refresh token",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductSearchReq"
+                            "$ref": "#/definitions/models.RefreshTokenReq"
                         }
                     }
                 ],
@@ -179,15 +302,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "$ref": "#/definitions/models.TokenRp"
                         }
                     }
                 }
             }
         },
-        "/api/product/get": {
+        "/api/event/group/create": {
             "post": {
-                "description": "Returns details of a product specified by its ID",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a consumer group of the event stream. Its consumers read with XREADGROUP. from is $ (default) for new events only, 0 for every retained event, a stream ID or an RFC 3339 time.",
                 "consumes": [
                     "application/json"
                 ],
@@ -195,17 +326,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Event"
                 ],
-                "summary": "Retrieve product by ID",
+                "summary": "Create event consumer group",
                 "parameters": [
                     {
-                        "description": "Product ID details",
+                        "description": "Group name and start position",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductByIdReq"
+                            "$ref": "#/definitions/models.EventGroupCreateReq"
                         }
                     }
                 ],
@@ -213,15 +344,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/response.ResponseData"
                         }
                     }
                 }
             }
         },
-        "/api/product/list": {
+        "/api/event/group/list": {
             "post": {
-                "description": "Returns a list of products matching the search request",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the consumer groups of the event stream with their consumers, pending events, last delivered ID and lag",
                 "consumes": [
                     "application/json"
                 ],
@@ -229,17 +368,51 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Event"
                 ],
-                "summary": "dynamic filtering and incremental search",
+                "summary": "Retrieve event consumer groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.EventGroup"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/event/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Reads the domain event stream from a position, oldest first. Pass next as from to read the following page; it is empty at the end of the stream.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Event"
+                ],
+                "summary": "Retrieve domain events",
                 "parameters": [
                     {
-                        "description": "Product search details",
+                        "description": "Position, event types and limit",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductSearchReq"
+                            "$ref": "#/definitions/models.EventSearchReq"
                         }
                     }
                 ],
@@ -247,15 +420,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.ProductSearchRp"
+                            "$ref": "#/definitions/models.EventListRp"
                         }
                     }
                 }
             }
         },
-        "/api/product/update": {
+        "/api/event/replay": {
             "post": {
-                "description": "Modifies the details of an existing product",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Moves a consumer group to a position so that its consumers read the events from there again: a stream ID or an RFC 3339 time, 0 for every retained event",
                 "consumes": [
                     "application/json"
                 ],
@@ -263,17 +444,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Product"
+                    "Event"
                 ],
-                "summary": "Update product details",
+                "summary": "Replay events to a consumer group",
                 "parameters": [
                     {
-                        "description": "Product update details",
+                        "description": "Group name and position",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.ProductUpdateReq"
+                            "$ref": "#/definitions/models.EventReplayReq"
                         }
                     }
                 ],
@@ -281,15 +462,23 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Product"
+                            "$ref": "#/definitions/response.ResponseData"
                         }
                     }
                 }
             }
         },
-        "/api/statistics/products-per-category": {
-            "get": {
-                "description": "Get percentage of products per category",
+        "/api/planning/convert": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates one draft purchase order per supplier for the given products and quantities",
                 "consumes": [
                     "application/json"
                 ],
@@ -297,25 +486,50 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Planning"
+                ],
+                "summary": "Convert suggestions into draft purchase orders",
+                "parameters": [
+                    {
+                        "description": "Products and quantities to order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PlanningConvertReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
                 ],
-                "summary": "Get percentage of products per category",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "number"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.PurchaseOrder"
                             }
                         }
                     }
                 }
             }
         },
-        "/api/statistics/products-per-supplier": {
+        "/api/planning/suggestions": {
             "get": {
-                "description": "Get percentage of products per supplier",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Forecasts daily demand from issue movements and suggests purchase quantities covering supplier lead time, review period and safety stock",
                 "consumes": [
                     "application/json"
                 ],
@@ -323,25 +537,78 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Statistics"
+                    "Planning"
+                ],
+                "summary": "Get suggested purchase quantities",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "moving_average | exponential_smoothing (default moving_average)",
+                        "name": "method",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of issue history used for the forecast (default 90)",
+                        "name": "history_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Moving average window in days (default 30)",
+                        "name": "window",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Exponential smoothing factor between 0 and 1 (default 0.3)",
+                        "name": "alpha",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days until the next planning review (default 7)",
+                        "name": "review_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Days of forecast demand kept as safety stock (default 7)",
+                        "name": "safety_days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Restrict the suggestions to these suppliers",
+                        "name": "supplier_ids",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get percentage of products per supplier",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "number"
-                            }
+                            "$ref": "#/definitions/models.PlanningSuggestionRp"
                         }
                     }
                 }
             }
         },
-        "/api/supplier/create": {
+        "/api/product-category/create": {
             "post": {
-                "description": "Creates a new supplier and returns the created supplier details",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new product category and returns the created product category details",
                 "consumes": [
                     "application/json"
                 ],
@@ -349,33 +616,47 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Supplier"
+                    "ProductCategory"
                 ],
-                "summary": "Create supplier",
+                "summary": "Create product category",
                 "parameters": [
                     {
-                        "description": "Supplier creation details",
+                        "description": "ProductCategory creation details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SupplierCreateReq"
+                            "$ref": "#/definitions/models.ProductCategoryCreateReq"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "201": {
                         "description": "Created",
                         "schema": {
-                            "$ref": "#/definitions/models.Supplier"
+                            "$ref": "#/definitions/models.ProductCategory"
                         }
                     }
                 }
             }
         },
-        "/api/supplier/list": {
+        "/api/product-category/list": {
             "post": {
-                "description": "Returns a list of suppliers matching the search request",
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of product categorys matching the search request",
                 "consumes": [
                     "application/json"
                 ],
@@ -383,17 +664,17 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Supplier"
+                    "ProductCategory"
                 ],
-                "summary": "Retrieve supplier list",
+                "summary": "Retrieve product category list",
                 "parameters": [
                     {
-                        "description": "Supplier search details",
+                        "description": "ProductCategory search details",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.SupplierSearchReq"
+                            "$ref": "#/definitions/models.ProductCategorySearchReq"
                         }
                     }
                 ],
@@ -406,90 +687,4912 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "models.Product": {
-            "type": "object",
-            "properties": {
+        },
+        "/api/product/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Adds a new product to the inventory",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Create new product",
+                "parameters": [
+                    {
+                        "description": "Product creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/distance": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the distance of a product specified by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Calculate the distance",
+                "parameters": [
+                    {
+                        "description": "Product distance request details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductDistanceByIdReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductDistanceRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/export": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generates and returns a PDF file containing the list of products",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Export product list to PDF",
+                "parameters": [
+                    {
+                        "description": "Product search details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/get": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns details of a product specified by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Retrieve product by ID",
+                "parameters": [
+                    {
+                        "description": "Product ID details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductByIdReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/labels": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Generates a PDF sheet of labels with name, price, a barcode (EAN-13 when the product has one, Code128 of the reference otherwise) and a QR code of the reference. Sizes come from the label config.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/pdf"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Print product labels",
+                "parameters": [
+                    {
+                        "description": "Products and label options",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductLabelReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of products matching the search request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "dynamic filtering and incremental search",
+                "parameters": [
+                    {
+                        "description": "Product search details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the product whose reference or barcode (EAN-13/UPC-A/EAN-8) equals the code, for scanners. UPC-A codes also match their EAN-13 form.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Lookup product by code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product reference or barcode",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/product/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Modifies the details of an existing product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Update product details",
+                "parameters": [
+                    {
+                        "description": "Product update details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductUpdateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product being updated, used when version is not in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates only the fields present in the body (JSON Merge Patch). product_id and version (or If-Match) are required; null values are rejected.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Product"
+                ],
+                "summary": "Patch product",
+                "parameters": [
+                    {
+                        "description": "Fields to change",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPatchReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product being updated, used when version is not in the body",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/purchase-order/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the purchase orders matching the search request with their lines",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Planning"
+                ],
+                "summary": "Retrieve purchase order list",
+                "parameters": [
+                    {
+                        "description": "Purchase order search details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.PurchaseOrderSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report-schedule/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Runs a saved search of the current user on a cron expression (5 fields or @daily, @weekly...) and writes the export to the report directory or emails it",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Create report schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportScheduleCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportSchedule"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report-schedule/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a report schedule of the current user with its run history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Delete report schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportScheduleIdReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report-schedule/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the report schedules of the current user, by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Retrieve report schedule list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ReportSchedule"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report-schedule/run": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Runs a report schedule of the current user now, even when disabled, and returns the run. A failed export or delivery has status failed and its error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Run report schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportScheduleIdReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportRun"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report-schedule/runs": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the runs of a report schedule of the current user, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Retrieve report run history",
+                "parameters": [
+                    {
+                        "description": "Schedule ID and paging",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportRunSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/report-schedule/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces a report schedule of the current user. It takes effect on the next run.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Update report schedule",
+                "parameters": [
+                    {
+                        "description": "Schedule ID and details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportScheduleUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ReportSchedule"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/role/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a role granting the given permissions",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Create role",
+                "parameters": [
+                    {
+                        "description": "Role details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/role/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a role that is not assigned to any user. The admin role cannot be deleted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Delete role",
+                "parameters": [
+                    {
+                        "description": "Role ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleByIdReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/role/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every role with the permissions it grants",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Retrieve role list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Role"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/role/permissions": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns every permission that can be granted to a role",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Retrieve permission list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/role/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the description and permissions of a role. The admin role cannot be changed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Update role",
+                "parameters": [
+                    {
+                        "description": "Role details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.RoleUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Role"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/saved-search/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Saves a product search under a name unique to the current user. Paging fields are dropped: reports include all the matching products.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Create saved search",
+                "parameters": [
+                    {
+                        "description": "Name and search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearchCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/saved-search/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a saved search of the current user with the report schedules running it and their history",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Delete saved search",
+                "parameters": [
+                    {
+                        "description": "Saved search ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearchDeleteReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/saved-search/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the saved product searches of the current user, by name",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Retrieve saved search list",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SavedSearch"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/saved-search/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the name and search of a saved search of the current user",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Report"
+                ],
+                "summary": "Update saved search",
+                "parameters": [
+                    {
+                        "description": "Saved search ID, name and search",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearchUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SavedSearch"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/abc-analysis": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Classifies products into A/B/C by consumption value over the period and stores the class on each product",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Run ABC analysis",
+                "parameters": [
+                    {
+                        "description": "ABC analysis period and cumulative percentage thresholds",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.AbcAnalysisReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.AbcAnalysisRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/cache": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Hits, misses and hit rate of the product and product list caches since the server started, with the number of invalidations and of Redis errors answered from the database",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get product cache statistics",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.CacheStatsRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/days-of-cover": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get how many days the stock on hand of every product lasts at its average daily issue rate",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get days of cover",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of past days used for the average daily issue rate (default 30)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DaysOfCoverRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/dead-stock": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get products in stock without any movement in the last N days, with totals per category and supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get dead stock",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Number of days without movement (default 90)",
+                        "name": "days",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeadStockRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/products-per-category": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get product count, total quantity or total stock value per category with percentages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get products statistics per category",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "count | quantity | value (default count)",
+                        "name": "metric",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StatisticsRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/products-per-supplier": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get product count, total quantity or total stock value per supplier with percentages",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get products statistics per supplier",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "count | quantity | value (default count)",
+                        "name": "metric",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StatisticsRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/timeseries": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get quantity and value series per product, category or supplier from the daily inventory snapshots",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get inventory time series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "day | week | month (default day)",
+                        "name": "bucket",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product | category | supplier (default category)",
+                        "name": "group_by",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Restrict the series to these product, category or supplier IDs",
+                        "name": "ids",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TimeseriesRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/statistics/turnover": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Get the inventory turnover ratio per product, category or supplier: value issued over the period divided by the average inventory value",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Statistics"
+                ],
+                "summary": "Get inventory turnover",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Start date (YYYY-MM-DD, default 30 days before date_to)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "End date (YYYY-MM-DD, default today)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "product | category | supplier (default category)",
+                        "name": "group_by",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TurnoverRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-movement/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records a receipt, issue or adjustment and updates the product quantity",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "StockMovement"
+                ],
+                "summary": "Create stock movement",
+                "parameters": [
+                    {
+                        "description": "Stock movement details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockMovementCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockMovement"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/stock-movement/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the stock movements matching the search request, newest first",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "StockMovement"
+                ],
+                "summary": "Retrieve stock movement list",
+                "parameters": [
+                    {
+                        "description": "Stock movement search details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockMovementSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/supplier/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new supplier and returns the created supplier details",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Create supplier",
+                "parameters": [
+                    {
+                        "description": "Supplier creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/supplier/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns a list of suppliers matching the search request",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Supplier"
+                ],
+                "summary": "Retrieve supplier list",
+                "parameters": [
+                    {
+                        "description": "Supplier search details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/assign-roles": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the roles of a user with the given roles. The admin role cannot be taken from the last user holding it.\nReplaces the roles of a user with the given roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "parameters": [
+                    {
+                        "description": "User and role IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserAssignRolesReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserWithRoles"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a new active user that can log in",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Create user",
+                "parameters": [
+                    {
+                        "description": "User creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/user/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns users matching the filters with the names of their roles",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Role"
+                ],
+                "summary": "Retrieve user list",
+                "parameters": [
+                    {
+                        "description": "Search parameters",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/categories": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the product categories matching the query filters",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductCategoryV2"
+                ],
+                "summary": "List product categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Category name",
+                        "name": "product_category_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a product category",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductCategoryV2"
+                ],
+                "summary": "Create product category",
+                "parameters": [
+                    {
+                        "description": "Product category creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductCategoryCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductCategory"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/products": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the products matching the query filters. Repeat a parameter to pass several values.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "List products",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Free-text search of name, reference, category and supplier names, ordered by relevance",
+                        "name": "query",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter expression, e.g. quantity \u003c 10 AND (status = 'On Order' OR supplier.name ~ 'ACME')",
+                        "name": "filter",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Product references",
+                        "name": "product_references",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Product names",
+                        "name": "product_names",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Statuses",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Category IDs",
+                        "name": "product_category_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Supplier IDs",
+                        "name": "supplier_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Minimum price",
+                        "name": "price_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum price",
+                        "name": "price_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Stock locations",
+                        "name": "stock_locations",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "ABC classes",
+                        "name": "abc_classes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created from (YYYY-MM-DD)",
+                        "name": "date_created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Created to (YYYY-MM-DD)",
+                        "name": "date_created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated name, price, quantity, date; prefix - for descending (default -date, relevance with query)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page, replaces offset",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Also count the products per status, category, supplier, stock location and price bucket",
+                        "name": "facets",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductSearchRp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a product and returns it with its Location and ETag",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "Create product",
+                "parameters": [
+                    {
+                        "description": "Product creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/products/lookup": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the product whose reference or barcode (EAN-13/UPC-A/EAN-8) equals the code.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "Lookup product by code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product reference or barcode",
+                        "name": "code",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/products/{id}": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the product with its ETag. A matching If-None-Match gets 304.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "Get product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the product fields. version is required in the body or as If-Match.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "Update product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Product update details, product_id is taken from the path",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductUpdateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product being updated",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes the product. With If-Match the product is only deleted if its version matches.",
+                "produces": [
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "Delete product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product being deleted",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Updates only the fields present in the body (JSON Merge Patch). version is required in the body or as If-Match; null values are rejected.",
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "ProductV2"
+                ],
+                "summary": "Patch product",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Product ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change, product_id is taken from the path",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.ProductPatchReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the product being updated",
+                        "name": "If-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Product"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "412": {
+                        "description": "Precondition Failed",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/stock-movements": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the stock movements matching the query filters, newest first. Repeat a parameter to pass several values.",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "StockMovementV2"
+                ],
+                "summary": "List stock movements",
+                "parameters": [
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Product IDs",
+                        "name": "product_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "array",
+                        "items": {
+                            "type": "string"
+                        },
+                        "collectionFormat": "multi",
+                        "description": "Movement types",
+                        "name": "movement_types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "From (YYYY-MM-DD)",
+                        "name": "date_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "To (YYYY-MM-DD)",
+                        "name": "date_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Records a receipt, issue or adjustment and updates the product quantity. Issues larger than the stock on hand get 422.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "StockMovementV2"
+                ],
+                "summary": "Create stock movement",
+                "parameters": [
+                    {
+                        "description": "Stock movement details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.StockMovementCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.StockMovement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/v2/suppliers": {
+            "get": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the suppliers matching the query filters",
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "SupplierV2"
+                ],
+                "summary": "List suppliers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Supplier name",
+                        "name": "supplier_name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 20)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Offset",
+                        "name": "offset",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Creates a supplier",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "application/problem+json"
+                ],
+                "tags": [
+                    "SupplierV2"
+                ],
+                "summary": "Create supplier",
+                "parameters": [
+                    {
+                        "description": "Supplier creation details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SupplierCreateReq"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key and body return the original response",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Supplier"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    },
+                    "422": {
+                        "description": "Unprocessable Entity",
+                        "schema": {
+                            "$ref": "#/definitions/response.ProblemDetails"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/create": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Registers an http or https URL to receive the domain events of the given types as POST requests signed with the secret (at least 16 characters). See the README for the headers and how to verify the signature.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create webhook",
+                "parameters": [
+                    {
+                        "description": "URL, secret and event types",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookCreateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/dead-letter/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the deliveries that failed every attempt, newest first. They are kept until redelivered or their webhook is deleted. status is ignored.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Retrieve webhook dead letters",
+                "parameters": [
+                    {
+                        "description": "Webhook ID and paging",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDeliverySearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/dead-letter/redeliver": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Queues dead deliveries to be sent again with their attempts reset. Deliveries that are not dead are skipped; the response has the number queued.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Redeliver dead webhook deliveries",
+                "parameters": [
+                    {
+                        "description": "Delivery IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRedeliverReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookRedeliverRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/delete": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Deletes a webhook with its delivery log, including pending and dead deliveries",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook ID",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookIdReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/response.ResponseData"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/delivery/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the webhook deliveries, newest first, with their status, attempts and the outcome of the last attempt. Filter by webhook and by status: pending, delivered or dead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Retrieve webhook delivery log",
+                "parameters": [
+                    {
+                        "description": "Webhook ID, status and paging",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookDeliverySearchReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SearchRp"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/list": {
+            "post": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Returns the registered webhooks. Secrets are never returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Retrieve webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Webhook"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/webhook/update": {
+            "put": {
+                "security": [
+                    {
+                        "BearerAuth": []
+                    },
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Replaces the URL, event types and enabled flag of a webhook, and its secret unless empty. Deliveries of a disabled webhook wait until it is enabled again.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook ID and details",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.WebhookUpdateReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Webhook"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "models.AbcAnalysisReq": {
+            "type": "object",
+            "properties": {
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "threshold_a": {
+                    "type": "number"
+                },
+                "threshold_b": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.AbcAnalysisRp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AbcItem"
+                    }
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.AbcClassSummary"
+                    }
+                },
+                "threshold_a": {
+                    "type": "number"
+                },
+                "threshold_b": {
+                    "type": "number"
+                },
+                "total_value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AbcClass": {
+            "type": "string",
+            "enum": [
+                "A",
+                "B",
+                "C"
+            ],
+            "x-enum-varnames": [
+                "AbcClassA",
+                "AbcClassB",
+                "AbcClassC"
+            ]
+        },
+        "models.AbcClassSummary": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "$ref": "#/definitions/models.AbcClass"
+                },
+                "consumption_value": {
+                    "type": "integer"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "products": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AbcItem": {
+            "type": "object",
+            "properties": {
+                "class": {
+                    "$ref": "#/definitions/models.AbcClass"
+                },
+                "consumption_value": {
+                    "type": "integer"
+                },
+                "cumulative_percentage": {
+                    "type": "number"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApiKey": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApiKeyCreateReq": {
+            "type": "object",
+            "properties": {
+                "expires_in_days": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApiKeyCreateRp": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ApiKeyRevokeReq": {
+            "type": "object",
+            "properties": {
+                "api_key_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.AuditEntity": {
+            "type": "string",
+            "enum": [
+                "product",
+                "supplier",
+                "product_category"
+            ],
+            "x-enum-varnames": [
+                "AuditEntityProduct",
+                "AuditEntitySupplier",
+                "AuditEntityCategory"
+            ]
+        },
+        "models.AuditSearchReq": {
+            "type": "object",
+            "properties": {
+                "actor_id": {
+                    "type": "string"
+                },
+                "actor_name": {
+                    "type": "string"
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
+                    "type": "string"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "$ref": "#/definitions/models.AuditEntity"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.AuthUser": {
+            "type": "object",
+            "properties": {
+                "auth_method": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.CacheCounter": {
+            "type": "object",
+            "properties": {
+                "hit_rate": {
+                    "type": "number"
+                },
+                "hits": {
+                    "type": "integer"
+                },
+                "misses": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.CacheStatsRp": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "integer"
+                },
+                "invalidations": {
+                    "type": "integer"
+                },
+                "product": {
+                    "$ref": "#/definitions/models.CacheCounter"
+                },
+                "product_list": {
+                    "$ref": "#/definitions/models.CacheCounter"
+                }
+            }
+        },
+        "models.DaysOfCoverItem": {
+            "type": "object",
+            "properties": {
+                "average_daily_issue": {
+                    "type": "number"
+                },
+                "days_of_cover": {
+                    "type": "number"
+                },
+                "issued_quantity": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DaysOfCoverRp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DaysOfCoverItem"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeadStockGroup": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "products": {
+                    "type": "integer"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeadStockItem": {
+            "type": "object",
+            "properties": {
+                "last_movement_at": {
+                    "type": "string"
+                },
+                "product_category_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DeadStockRp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeadStockItem"
+                    }
+                },
+                "days": {
+                    "type": "integer"
+                },
+                "per_category": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeadStockGroup"
+                    }
+                },
+                "per_supplier": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DeadStockGroup"
+                    }
+                }
+            }
+        },
+        "models.DeliveryStatus": {
+            "type": "string",
+            "enum": [
+                "pending",
+                "delivered",
+                "dead"
+            ],
+            "x-enum-varnames": [
+                "DeliveryPending",
+                "DeliveryDelivered",
+                "DeliveryDead"
+            ]
+        },
+        "models.DomainEvent": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/models.DomainEventData"
+                },
+                "entity_id": {
+                    "type": "string"
+                },
+                "entity_type": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "description": "EventID is unique and the same when an event is published again: consumers deduplicate on it.",
+                    "type": "string"
+                },
+                "occurred_at": {
+                    "type": "string"
+                },
+                "type": {
+                    "$ref": "#/definitions/models.DomainEventType"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainEventData": {
+            "type": "object",
+            "properties": {
+                "after": {
+                    "type": "object"
+                },
+                "before": {
+                    "type": "object"
+                },
+                "delta": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.DomainEventType": {
+            "type": "string",
+            "enum": [
+                "product.created",
+                "product.updated",
+                "product.deleted",
+                "product.quantity_changed",
+                "product.status_changed",
+                "stock.movement_created",
+                "supplier.created",
+                "supplier.updated",
+                "supplier.status_changed",
+                "category.created",
+                "category.updated",
+                "category.status_changed"
+            ],
+            "x-enum-varnames": [
+                "EventProductCreated",
+                "EventProductUpdated",
+                "EventProductDeleted",
+                "EventProductQuantityChanged",
+                "EventProductStatusChanged",
+                "EventStockMovementCreated",
+                "EventSupplierCreated",
+                "EventSupplierUpdated",
+                "EventSupplierStatusChanged",
+                "EventCategoryCreated",
+                "EventCategoryUpdated",
+                "EventCategoryStatusChanged"
+            ]
+        },
+        "models.EventGroup": {
+            "type": "object",
+            "properties": {
+                "consumers": {
+                    "type": "integer"
+                },
+                "lag": {
+                    "type": "integer"
+                },
+                "last_delivered_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.EventGroupCreateReq": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "from": {
+                    "description": "From is where the group starts: \"$\" (default) for new events only, \"0\" for every retained\nevent, a stream ID or an RFC 3339 time.",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventListRp": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StreamEvent"
+                    }
+                },
+                "next": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventReplayReq": {
+            "type": "object",
+            "required": [
+                "from",
+                "group"
+            ],
+            "properties": {
+                "from": {
+                    "type": "string"
+                },
+                "group": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.EventSearchReq": {
+            "type": "object",
+            "properties": {
+                "from": {
+                    "description": "From is the position to read from, included: a stream ID or an RFC 3339 time. Empty\nreads from the oldest retained event.",
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainEventType"
+                    }
+                }
+            }
+        },
+        "models.FacetCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "label": {
+                    "description": "Label is the category or supplier name.",
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ForecastMethod": {
+            "type": "string",
+            "enum": [
+                "moving_average",
+                "exponential_smoothing"
+            ],
+            "x-enum-varnames": [
+                "ForecastMovingAverage",
+                "ForecastExponentialSmoothing"
+            ]
+        },
+        "models.LabelSymbology": {
+            "type": "string",
+            "enum": [
+                "auto",
+                "code128",
+                "ean13"
+            ],
+            "x-enum-varnames": [
+                "LabelSymbologyAuto",
+                "LabelSymbologyCode128",
+                "LabelSymbologyEAN13"
+            ]
+        },
+        "models.LoginReq": {
+            "type": "object",
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlanningConvertItem": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlanningConvertReq": {
+            "type": "object",
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanningConvertItem"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PlanningSuggestion": {
+            "type": "object",
+            "properties": {
+                "demand_over_lead_time": {
+                    "type": "number"
+                },
+                "forecast_daily_demand": {
+                    "type": "number"
+                },
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "on_hand": {
+                    "type": "integer"
+                },
+                "on_order": {
+                    "type": "integer"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "safety_stock": {
+                    "type": "number"
+                },
+                "suggested_quantity": {
+                    "type": "integer"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "supplier_name": {
+                    "type": "string"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PlanningSuggestionRp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PlanningSuggestion"
+                    }
+                },
+                "history_days": {
+                    "type": "integer"
+                },
+                "method": {
+                    "$ref": "#/definitions/models.ForecastMethod"
+                }
+            }
+        },
+        "models.PriceBucketCount": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.Product": {
+            "type": "object",
+            "properties": {
+                "abc_class": {
+                    "$ref": "#/definitions/models.AbcClass"
+                },
+                "barcode": {
+                    "type": "string"
+                },
                 "date_created": {
                     "type": "string"
                 },
-                "price": {
+                "price": {
+                    "type": "integer"
+                },
+                "product_category": {
+                    "$ref": "#/definitions/models.ProductCategory"
+                },
+                "product_category_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProductStatus"
+                },
+                "stock_location": {
+                    "type": "string"
+                },
+                "supplier": {
+                    "$ref": "#/definitions/models.Supplier"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductByIdReq": {
+            "type": "object",
+            "properties": {
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductCategory": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "product_category_id": {
+                    "type": "string"
+                },
+                "product_category_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProductCategoryStatus"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductCategoryCreateReq": {
+            "type": "object",
+            "properties": {
+                "product_category_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProductCategoryStatus"
+                }
+            }
+        },
+        "models.ProductCategorySearchReq": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.SupplierStatus"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductCategoryStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "in active"
+            ],
+            "x-enum-varnames": [
+                "CategoryActive",
+                "CategoryInActive"
+            ]
+        },
+        "models.ProductCreateReq": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_category_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProductStatus"
+                },
+                "stock_location": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductDistanceByIdReq": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductDistanceRp": {
+            "type": "object",
+            "properties": {
+                "distance": {
+                    "type": "string"
+                },
+                "ip_current_city": {
+                    "type": "string"
+                },
+                "stock_location_city": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ProductFacets": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "price": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PriceBucketCount"
+                    }
+                },
+                "status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "stock_location": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                },
+                "supplier": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.FacetCount"
+                    }
+                }
+            }
+        },
+        "models.ProductLabelReq": {
+            "type": "object",
+            "properties": {
+                "copies": {
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "size": {
+                    "type": "string"
+                },
+                "symbology": {
+                    "$ref": "#/definitions/models.LabelSymbology"
+                }
+            }
+        },
+        "models.ProductPatchReq": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_category_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProductStatus"
+                },
+                "stock_location": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductSearchReq": {
+            "type": "object",
+            "properties": {
+                "abc_classes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "cursor": {
+                    "description": "Cursor is the next_cursor of the previous page, used instead of offset. It is only\nvalid with the same sort.",
+                    "type": "string"
+                },
+                "date_created_from": {
+                    "type": "string"
+                },
+                "date_created_to": {
+                    "type": "string"
+                },
+                "facets": {
+                    "description": "Facets adds the counts per status, category, supplier, stock location and price bucket.",
+                    "type": "boolean"
+                },
+                "filter": {
+                    "description": "Filter is a filter expression ANDed with the other filters, see ParseProductFilter.",
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "price_from": {
+                    "type": "integer"
+                },
+                "price_to": {
+                    "type": "integer"
+                },
+                "product_category_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "product_names": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "product_references": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "query": {
+                    "description": "Query is a free-text search of the name, reference, category and supplier names,\ntolerant to typos. Results are ordered by relevance.",
+                    "type": "string"
+                },
+                "sort": {
+                    "description": "Sort is a comma-separated list of name, price, quantity and date, each optionally\nprefixed with \"-\" for descending order. Defaults to -date, or relevance with Query.",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "stock_locations": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "supplier_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ProductSearchRp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Product"
+                    }
+                },
+                "facets": {
+                    "$ref": "#/definitions/models.ProductFacets"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "description": "NextCursor is empty on the last page and when ordering by relevance.",
+                    "type": "string"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "total": {
+                    "description": "Total is the number of products matching the filters, on all pages.",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ProductStatus": {
+            "type": "string",
+            "enum": [
+                "Available",
+                "On Order",
+                "Out of Stock"
+            ],
+            "x-enum-varnames": [
+                "ProductStatusAvailable",
+                "ProductStatusOnOrder",
+                "ProductStatusOutOfStock"
+            ]
+        },
+        "models.ProductUpdateReq": {
+            "type": "object",
+            "properties": {
+                "barcode": {
+                    "type": "string"
+                },
+                "price": {
+                    "type": "integer"
+                },
+                "product_category_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "product_reference": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ProductStatus"
+                },
+                "stock_location": {
+                    "type": "string"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchaseOrder": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "lines": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderLine"
+                    }
+                },
+                "note": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.PurchaseOrderStatus"
+                },
+                "supplier": {
+                    "$ref": "#/definitions/models.Supplier"
+                },
+                "supplier_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PurchaseOrderLine": {
+            "type": "object",
+            "properties": {
+                "line_id": {
+                    "type": "string"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "purchase_order_id": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "unit_price": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.PurchaseOrderSearchReq": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.PurchaseOrderStatus"
+                    }
+                },
+                "supplier_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.PurchaseOrderStatus": {
+            "type": "string",
+            "enum": [
+                "draft",
+                "ordered",
+                "received",
+                "cancelled"
+            ],
+            "x-enum-varnames": [
+                "PurchaseOrderDraft",
+                "PurchaseOrderOrdered",
+                "PurchaseOrderReceived",
+                "PurchaseOrderCancelled"
+            ]
+        },
+        "models.RefreshTokenReq": {
+            "type": "object",
+            "properties": {
+                "refresh_token": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportDelivery": {
+            "type": "string",
+            "enum": [
+                "directory",
+                "email"
+            ],
+            "x-enum-varnames": [
+                "ReportDeliveryDirectory",
+                "ReportDeliveryEmail"
+            ]
+        },
+        "models.ReportFormat": {
+            "type": "string",
+            "enum": [
+                "pdf",
+                "csv"
+            ],
+            "x-enum-varnames": [
+                "ReportFormatPDF",
+                "ReportFormatCSV"
+            ]
+        },
+        "models.ReportRun": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "output": {
+                    "description": "Output is the written file, or the recipients of the email.",
+                    "type": "string"
+                },
+                "products": {
+                    "description": "Products is the number of products in the export.",
+                    "type": "integer"
+                },
+                "run_id": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.ReportRunStatus"
+                }
+            }
+        },
+        "models.ReportRunSearchReq": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "schedule_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportRunStatus": {
+            "type": "string",
+            "enum": [
+                "running",
+                "success",
+                "failed"
+            ],
+            "x-enum-varnames": [
+                "ReportRunRunning",
+                "ReportRunSuccess",
+                "ReportRunFailed"
+            ]
+        },
+        "models.ReportSchedule": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "cron": {
+                    "type": "string"
+                },
+                "delivery": {
+                    "$ref": "#/definitions/models.ReportDelivery"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "format": {
+                    "$ref": "#/definitions/models.ReportFormat"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "description": "Recipients are the email addresses of ReportDeliveryEmail.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "saved_search_id": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportScheduleCreateReq": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string"
+                },
+                "delivery": {
+                    "$ref": "#/definitions/models.ReportDelivery"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "format": {
+                    "$ref": "#/definitions/models.ReportFormat"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "saved_search_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportScheduleIdReq": {
+            "type": "object",
+            "properties": {
+                "schedule_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ReportScheduleUpdateReq": {
+            "type": "object",
+            "properties": {
+                "cron": {
+                    "type": "string"
+                },
+                "delivery": {
+                    "$ref": "#/definitions/models.ReportDelivery"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "format": {
+                    "$ref": "#/definitions/models.ReportFormat"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "saved_search_id": {
+                    "type": "string"
+                },
+                "schedule_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Role": {
+            "type": "object",
+            "properties": {
+                "built_in": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoleByIdReq": {
+            "type": "object",
+            "properties": {
+                "role_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.RoleCreateReq": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.RoleUpdateReq": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "permissions": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "role_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SavedSearch": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "saved_search_id": {
+                    "type": "string"
+                },
+                "search": {
+                    "$ref": "#/definitions/models.ProductSearchReq"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SavedSearchCreateReq": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "search": {
+                    "$ref": "#/definitions/models.ProductSearchReq"
+                }
+            }
+        },
+        "models.SavedSearchDeleteReq": {
+            "type": "object",
+            "properties": {
+                "saved_search_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SavedSearchUpdateReq": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "saved_search_id": {
+                    "type": "string"
+                },
+                "search": {
+                    "$ref": "#/definitions/models.ProductSearchReq"
+                }
+            }
+        },
+        "models.SearchRp": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "limit": {
                     "type": "integer"
                 },
-                "product_category": {
-                    "$ref": "#/definitions/models.ProductCategory"
+                "offset": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StatisticsGroupBy": {
+            "type": "string",
+            "enum": [
+                "product",
+                "category",
+                "supplier"
+            ],
+            "x-enum-varnames": [
+                "StatisticsGroupByProduct",
+                "StatisticsGroupByCategory",
+                "StatisticsGroupBySupplier"
+            ]
+        },
+        "models.StatisticsItem": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
                 },
-                "product_category_id": {
+                "name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.StatisticsMetric": {
+            "type": "string",
+            "enum": [
+                "count",
+                "quantity",
+                "value"
+            ],
+            "x-enum-varnames": [
+                "StatisticsMetricCount",
+                "StatisticsMetricQuantity",
+                "StatisticsMetricValue"
+            ]
+        },
+        "models.StatisticsRp": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatisticsItem"
+                    }
+                },
+                "metric": {
+                    "$ref": "#/definitions/models.StatisticsMetric"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.StockMovement": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "movement_id": {
+                    "type": "string"
+                },
+                "movement_type": {
+                    "$ref": "#/definitions/models.StockMovementType"
+                },
+                "note": {
                     "type": "string"
                 },
                 "product_id": {
                     "type": "string"
                 },
-                "product_name": {
+                "quantity": {
+                    "type": "integer"
+                },
+                "quantity_after": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StockMovementCreateReq": {
+            "type": "object",
+            "properties": {
+                "movement_type": {
+                    "$ref": "#/definitions/models.StockMovementType"
+                },
+                "note": {
                     "type": "string"
                 },
-                "product_reference": {
+                "product_id": {
                     "type": "string"
                 },
                 "quantity": {
                     "type": "integer"
+                }
+            }
+        },
+        "models.StockMovementSearchReq": {
+            "type": "object",
+            "properties": {
+                "date_from": {
+                    "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.ProductStatus"
+                "date_to": {
+                    "type": "string"
                 },
-                "stock_location": {
+                "limit": {
+                    "type": "integer"
+                },
+                "movement_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StockMovementType"
+                    }
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "product_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.StockMovementType": {
+            "type": "string",
+            "enum": [
+                "receipt",
+                "issue",
+                "adjustment"
+            ],
+            "x-enum-varnames": [
+                "MovementReceipt",
+                "MovementIssue",
+                "MovementAdjustment"
+            ]
+        },
+        "models.StreamEvent": {
+            "type": "object",
+            "properties": {
+                "event": {
+                    "$ref": "#/definitions/models.DomainEvent"
+                },
+                "stream_id": {
                     "type": "string"
+                }
+            }
+        },
+        "models.Supplier": {
+            "type": "object",
+            "properties": {
+                "lead_time_days": {
+                    "type": "integer"
                 },
-                "supplier": {
-                    "$ref": "#/definitions/models.Supplier"
+                "status": {
+                    "$ref": "#/definitions/models.SupplierStatus"
                 },
                 "supplier_id": {
                     "type": "string"
+                },
+                "supplier_name": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SupplierCreateReq": {
+            "type": "object",
+            "properties": {
+                "lead_time_days": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.SupplierStatus"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SupplierSearchReq": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "offset": {
+                    "type": "integer"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.SupplierStatus"
+                },
+                "supplier_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SupplierStatus": {
+            "type": "string",
+            "enum": [
+                "active",
+                "in active"
+            ],
+            "x-enum-varnames": [
+                "SupplierActive",
+                "SupplierInActive"
+            ]
+        },
+        "models.TimeseriesBucket": {
+            "type": "string",
+            "enum": [
+                "day",
+                "week",
+                "month"
+            ],
+            "x-enum-varnames": [
+                "TimeseriesBucketDay",
+                "TimeseriesBucketWeek",
+                "TimeseriesBucketMonth"
+            ]
+        },
+        "models.TimeseriesPoint": {
+            "type": "object",
+            "properties": {
+                "date": {
+                    "type": "string"
+                },
+                "quantity": {
+                    "type": "integer"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.TimeseriesRp": {
+            "type": "object",
+            "properties": {
+                "bucket": {
+                    "$ref": "#/definitions/models.TimeseriesBucket"
+                },
+                "group_by": {
+                    "$ref": "#/definitions/models.StatisticsGroupBy"
+                },
+                "series": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeseriesSeries"
+                    }
+                }
+            }
+        },
+        "models.TimeseriesSeries": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TimeseriesPoint"
+                    }
+                }
+            }
+        },
+        "models.TokenRp": {
+            "type": "object",
+            "properties": {
+                "access_token": {
+                    "type": "string"
+                },
+                "expires_in": {
+                    "type": "integer"
+                },
+                "refresh_token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TurnoverItem": {
+            "type": "object",
+            "properties": {
+                "average_days_to_sell_stock": {
+                    "type": "number"
+                },
+                "average_inventory_value": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "issued_quantity": {
+                    "type": "integer"
+                },
+                "issued_value": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "turnover_ratio": {
+                    "type": "number"
                 }
             }
         },
-        "models.ProductByIdReq": {
+        "models.TurnoverRp": {
             "type": "object",
             "properties": {
-                "product_id": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.TurnoverItem"
+                    }
+                },
+                "date_from": {
+                    "type": "string"
+                },
+                "date_to": {
                     "type": "string"
+                },
+                "group_by": {
+                    "$ref": "#/definitions/models.StatisticsGroupBy"
                 }
             }
         },
-        "models.ProductCategory": {
+        "models.User": {
             "type": "object",
             "properties": {
                 "created_at": {
                     "type": "string"
                 },
-                "product_category_id": {
-                    "type": "string"
+                "status": {
+                    "$ref": "#/definitions/models.UserStatus"
                 },
-                "product_category_name": {
+                "user_id": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.ProductCategoryStatus"
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.UserAssignRolesReq": {
+            "type": "object",
+            "properties": {
+                "role_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "updated_at": {
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "models.ProductCategoryCreateReq": {
+        "models.UserCreateReq": {
             "type": "object",
             "properties": {
-                "product_category_name": {
+                "password": {
                     "type": "string"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.ProductCategoryStatus"
+                "username": {
+                    "type": "string"
                 }
             }
         },
-        "models.ProductCategorySearchReq": {
+        "models.UserSearchReq": {
             "type": "object",
             "properties": {
                 "limit": {
@@ -499,143 +5602,122 @@ const docTemplate = `{
                     "type": "integer"
                 },
                 "status": {
-                    "$ref": "#/definitions/models.SupplierStatus"
+                    "$ref": "#/definitions/models.UserStatus"
                 },
-                "supplier_name": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "models.ProductCategoryStatus": {
+        "models.UserStatus": {
             "type": "string",
             "enum": [
                 "active",
                 "in active"
             ],
             "x-enum-varnames": [
-                "CategoryActive",
-                "CategoryInActive"
+                "UserActive",
+                "UserInActive"
             ]
         },
-        "models.ProductCreateReq": {
+        "models.UserWithRoles": {
             "type": "object",
             "properties": {
-                "price": {
-                    "type": "integer"
-                },
-                "product_category_id": {
-                    "type": "string"
-                },
-                "product_name": {
-                    "type": "string"
-                },
-                "product_reference": {
+                "created_at": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
+                "roles": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
                 "status": {
-                    "$ref": "#/definitions/models.ProductStatus"
+                    "$ref": "#/definitions/models.UserStatus"
                 },
-                "stock_location": {
+                "user_id": {
                     "type": "string"
                 },
-                "supplier_id": {
+                "username": {
                     "type": "string"
                 }
             }
         },
-        "models.ProductDistanceByIdReq": {
+        "models.Webhook": {
             "type": "object",
             "properties": {
-                "ip": {
+                "created_at": {
                     "type": "string"
                 },
-                "product_id": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainEventType"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "models.ProductDistanceRp": {
+        "models.WebhookCreateReq": {
             "type": "object",
             "properties": {
-                "distance": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "ip_current_city": {
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainEventType"
+                    }
+                },
+                "secret": {
                     "type": "string"
                 },
-                "stock_location_city": {
+                "url": {
                     "type": "string"
                 }
             }
         },
-        "models.ProductSearchReq": {
+        "models.WebhookDeliverySearchReq": {
             "type": "object",
             "properties": {
-                "date_created_from": {
-                    "type": "string"
-                },
-                "date_created_to": {
-                    "type": "string"
-                },
                 "limit": {
                     "type": "integer"
                 },
                 "offset": {
                     "type": "integer"
                 },
-                "price_from": {
-                    "type": "integer"
-                },
-                "price_to": {
-                    "type": "integer"
-                },
-                "productCategoryUUIDs": {
-                    "description": "convert",
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "product_category_ids": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "product_names": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "product_references": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
                 "status": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "stock_locations": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
-                },
-                "supplierUUIDs": {
-                    "type": "array",
-                    "items": {
-                        "type": "string"
-                    }
+                    "$ref": "#/definitions/models.DeliveryStatus"
                 },
-                "supplier_ids": {
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookIdReq": {
+            "type": "object",
+            "properties": {
+                "webhook_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.WebhookRedeliverReq": {
+            "type": "object",
+            "properties": {
+                "delivery_ids": {
                     "type": "array",
                     "items": {
                         "type": "string"
@@ -643,132 +5725,245 @@ const docTemplate = `{
                 }
             }
         },
-        "models.ProductSearchRp": {
+        "models.WebhookRedeliverRp": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/models.Product"
-                    }
-                },
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
+                "redelivered": {
                     "type": "integer"
                 }
             }
         },
-        "models.ProductStatus": {
-            "type": "string",
-            "enum": [
-                "Available",
-                "On Order",
-                "Out of Stock"
-            ],
-            "x-enum-varnames": [
-                "ProductStatusAvailable",
-                "ProductStatusOnOrder",
-                "ProductStatusOutOfStock"
-            ]
-        },
-        "models.ProductUpdateReq": {
+        "models.WebhookUpdateReq": {
             "type": "object",
             "properties": {
-                "price": {
-                    "type": "integer"
-                },
-                "product_category_id": {
-                    "type": "string"
-                },
-                "product_id": {
-                    "type": "string"
+                "enabled": {
+                    "type": "boolean"
                 },
-                "product_name": {
-                    "type": "string"
+                "event_types": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.DomainEventType"
+                    }
                 },
-                "product_reference": {
+                "secret": {
                     "type": "string"
                 },
-                "quantity": {
-                    "type": "integer"
-                },
-                "status": {
-                    "$ref": "#/definitions/models.ProductStatus"
-                },
-                "stock_location": {
+                "url": {
                     "type": "string"
                 },
-                "supplier_id": {
+                "webhook_id": {
                     "type": "string"
                 }
             }
         },
-        "models.SearchRp": {
+        "response.FieldError": {
             "type": "object",
             "properties": {
-                "data": {},
-                "limit": {
-                    "type": "integer"
+                "code": {
+                    "$ref": "#/definitions/response.RespCode"
                 },
-                "offset": {
-                    "type": "integer"
+                "field": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
                 }
             }
         },
-        "models.Supplier": {
+        "response.ProblemDetails": {
             "type": "object",
             "properties": {
-                "status": {
-                    "$ref": "#/definitions/models.SupplierStatus"
+                "code": {
+                    "$ref": "#/definitions/response.RespCode"
                 },
-                "supplier_id": {
+                "detail": {
                     "type": "string"
                 },
-                "supplier_name": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
+                },
+                "instance": {
                     "type": "string"
-                }
-            }
-        },
-        "models.SupplierCreateReq": {
-            "type": "object",
-            "properties": {
+                },
                 "status": {
-                    "$ref": "#/definitions/models.SupplierStatus"
+                    "type": "integer"
                 },
-                "supplier_name": {
+                "title": {
+                    "type": "string"
+                },
+                "trace_id": {
+                    "type": "string"
+                },
+                "type": {
                     "type": "string"
                 }
             }
         },
-        "models.SupplierSearchReq": {
+        "response.RespCode": {
+            "type": "integer",
+            "enum": [
+                200,
+                500,
+                400,
+                401,
+                403,
+                409,
+                3000,
+                3001,
+                3002,
+                3003,
+                3004,
+                3005,
+                3006,
+                3007,
+                3008,
+                3009,
+                3010,
+                3011,
+                3012,
+                3013,
+                3014,
+                3015,
+                3016,
+                3017,
+                3018,
+                3019,
+                3020,
+                3021,
+                3022,
+                3023,
+                3024,
+                3025,
+                3026,
+                3027,
+                3028,
+                3029,
+                3030,
+                3031,
+                3032,
+                3033,
+                3034,
+                3035,
+                3036,
+                3037,
+                3038,
+                3039,
+                3040,
+                3041,
+                3042,
+                3043,
+                3044,
+                3045,
+                3046,
+                3047,
+                3048,
+                3049,
+                3050,
+                3051,
+                3052,
+                3053,
+                3054,
+                2008
+            ],
+            "x-enum-varnames": [
+                "OkCode",
+                "ErrInternal",
+                "ErrBadRequest",
+                "ErrUnauthorized",
+                "ErrForbidden",
+                "ErrVersionConflict",
+                "Err",
+                "ErrInvalidName",
+                "ErrInvalidCategory",
+                "ErrInvalidSupplier",
+                "ErrInvalidProduct",
+                "ErrInvalidStatus",
+                "ErrInvalidStockLocation",
+                "ErrInvalidReference",
+                "ErrInvalidMetric",
+                "ErrInvalidBucket",
+                "ErrInvalidGroupBy",
+                "ErrInvalidMovementType",
+                "ErrInvalidQuantity",
+                "ErrInvalidDays",
+                "ErrInvalidAbcClass",
+                "ErrInvalidThreshold",
+                "ErrInvalidForecastMethod",
+                "ErrInvalidForecastParams",
+                "ErrInvalidLeadTime",
+                "ErrInvalidCredentials",
+                "ErrInvalidToken",
+                "ErrInvalidPassword",
+                "ErrInvalidApiKey",
+                "ErrInvalidRole",
+                "ErrInvalidPermission",
+                "ErrInvalidUser",
+                "ErrInvalidEntity",
+                "ErrInvalidVersion",
+                "ErrInvalidPrice",
+                "ErrInvalidIdempotencyKey",
+                "ErrIdempotencyInProgress",
+                "ErrIdempotencyKeyReused",
+                "ErrDuplicateReference",
+                "ErrInvalidBarcode",
+                "ErrDuplicateBarcode",
+                "ErrInvalidLabelSize",
+                "ErrInvalidSymbology",
+                "ErrTooLong",
+                "ErrInvalidSort",
+                "ErrInvalidCursor",
+                "ErrInvalidSavedSearch",
+                "ErrDuplicateName",
+                "ErrInvalidCron",
+                "ErrInvalidReportFormat",
+                "ErrInvalidDelivery",
+                "ErrInvalidRecipient",
+                "ErrInvalidSchedule",
+                "ErrInvalidFilter",
+                "ErrInvalidEventPosition",
+                "ErrInvalidEventType",
+                "ErrInvalidEventGroup",
+                "ErrInvalidWebhook",
+                "ErrInvalidWebhookUrl",
+                "ErrInvalidWebhookSecret",
+                "ErrInvalidDeliveryID",
+                "ErrInvalidDate"
+            ]
+        },
+        "response.ResponseData": {
             "type": "object",
             "properties": {
-                "limit": {
-                    "type": "integer"
-                },
-                "offset": {
-                    "type": "integer"
+                "code": {
+                    "$ref": "#/definitions/response.RespCode"
                 },
-                "status": {
-                    "$ref": "#/definitions/models.SupplierStatus"
+                "data": {},
+                "errors": {
+                    "description": "Errors lists every invalid field when the request failed validation.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/response.FieldError"
+                    }
                 },
-                "supplier_name": {
+                "message": {
                     "type": "string"
                 }
             }
+        }
+    },
+    "securityDefinitions": {
+        "ApiKeyAuth": {
+            "type": "apiKey",
+            "name": "X-API-Key",
+            "in": "header"
         },
-        "models.SupplierStatus": {
-            "type": "string",
-            "enum": [
-                "active",
-                "in active"
-            ],
-            "x-enum-varnames": [
-                "SupplierActive",
-                "SupplierInActive"
-            ]
+        "BearerAuth": {
+            "description": "JWT access token from /api/auth/login, as \"Bearer \u003ctoken\u003e\"",
+            "type": "apiKey",
+            "name": "Authorization",
+            "in": "header"
         }
     }
 }`
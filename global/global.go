@@ -5,6 +5,7 @@ import (
 	"stock-management/pkgs/setting"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
 	"gorm.io/gorm"
 )
 
@@ -13,4 +14,5 @@ var (
 	Logger *logger.LoggerZap
 	Pdb    *gorm.DB
 	Rdb    *redis.Client
+	Cron   *cron.Cron
 )
@@ -3,15 +3,27 @@ package initialize
 import (
 	"fmt"
 	"stock-management/global"
+	"stock-management/pkgs/setting"
 
 	"github.com/spf13/viper"
 )
 
+// Sample credentials shipped in ./config/local.yaml, refused where they would let anyone in.
+const (
+	sampleJwtSecret     = "change-me-in-production"
+	sampleAdminPassword = "admin1234"
+	// minJwtSecretLength is the shortest HS256 secret accepted, as many bytes as the hash.
+	minJwtSecretLength = 32
+)
+
 func InitLoadConfig() {
 	viper := viper.New()
 	viper.AddConfigPath("./config/")
 	viper.SetConfigName("local")
 	viper.SetConfigType("yaml")
+	// Secrets can be kept out of the file.
+	viper.BindEnv("auth.jwtSecret", "AUTH_JWT_SECRET")
+	viper.BindEnv("auth.adminPassword", "AUTH_ADMIN_PASSWORD")
 
 	err := viper.ReadInConfig()
 	if err != nil {
@@ -20,4 +32,41 @@ func InitLoadConfig() {
 	if err := viper.Unmarshal(&global.Config); err != nil {
 		fmt.Printf("unable decode config %v \n", err)
 	}
+	if err := checkAuthConfig(global.Config.Auth, global.Config.Server.Mode); err != nil {
+		panic(fmt.Errorf("invalid config: %v", err))
+	}
+	if err := checkWebhookConfig(global.Config.Webhook); err != nil {
+		panic(fmt.Errorf("invalid config: %v", err))
+	}
+}
+
+// checkAuthConfig refuses a JWT secret that would make access tokens forgeable and, outside
+// dev mode, the sample admin password.
+func checkAuthConfig(auth setting.AuthSetting, mode string) error {
+	switch {
+	case auth.JwtSecret == "":
+		return fmt.Errorf("auth.jwtSecret is empty")
+	case auth.JwtSecret == sampleJwtSecret:
+		return fmt.Errorf("auth.jwtSecret is the sample value, set a random secret")
+	case len(auth.JwtSecret) < minJwtSecretLength:
+		return fmt.Errorf("auth.jwtSecret must be at least %d bytes", minJwtSecretLength)
+	}
+	if mode != "dev" && auth.AdminPassword == sampleAdminPassword {
+		return fmt.Errorf("auth.adminPassword is the sample value, which is only allowed in dev mode")
+	}
+	return nil
+}
+
+// checkWebhookConfig refuses settings that would retry a failing endpoint on every dispatch or
+// let a hung request outlive the lease of its delivery, which is then sent twice.
+func checkWebhookConfig(webhook setting.WebhookSetting) error {
+	switch {
+	case webhook.TimeoutSeconds <= 0:
+		return fmt.Errorf("webhook.timeoutSeconds must be positive")
+	case webhook.RetryBaseSeconds <= 0:
+		return fmt.Errorf("webhook.retryBaseSeconds must be positive")
+	case webhook.RetryMaxSeconds < webhook.RetryBaseSeconds:
+		return fmt.Errorf("webhook.retryMaxSeconds must be at least webhook.retryBaseSeconds")
+	}
+	return nil
 }
@@ -0,0 +1,33 @@
+package initialize
+
+import (
+	"stock-management/pkgs/setting"
+	"testing"
+)
+
+func TestCheckWebhookConfig(t *testing.T) {
+	valid := setting.WebhookSetting{TimeoutSeconds: 10, MaxAttempts: 10, RetryBaseSeconds: 30, RetryMaxSeconds: 3600}
+	tests := []struct {
+		name    string
+		change  func(*setting.WebhookSetting)
+		wantErr bool
+	}{
+		{"valid", func(*setting.WebhookSetting) {}, false},
+		{"base equal to the cap", func(s *setting.WebhookSetting) { s.RetryMaxSeconds = s.RetryBaseSeconds }, false},
+		{"no timeout", func(s *setting.WebhookSetting) { s.TimeoutSeconds = 0 }, true},
+		{"negative timeout", func(s *setting.WebhookSetting) { s.TimeoutSeconds = -1 }, true},
+		{"no retry base", func(s *setting.WebhookSetting) { s.RetryBaseSeconds = 0 }, true},
+		{"negative retry base", func(s *setting.WebhookSetting) { s.RetryBaseSeconds = -30 }, true},
+		{"no retry cap", func(s *setting.WebhookSetting) { s.RetryMaxSeconds = 0 }, true},
+		{"cap below the base", func(s *setting.WebhookSetting) { s.RetryMaxSeconds = 10 }, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			webhook := valid
+			tt.change(&webhook)
+			if err := checkWebhookConfig(webhook); (err != nil) != tt.wantErr {
+				t.Errorf("checkWebhookConfig(%+v) error = %v, want error %v", webhook, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package initialize
+
+import (
+	"context"
+	"stock-management/global"
+	"stock-management/internal/services"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+func InitJob() {
+	c := cron.New()
+
+	_, err := c.AddFunc(global.Config.Job.InventorySnapshotCron, takeInventorySnapshot)
+	checkErrorPannic(err, "Schedule inventory snapshot job error")
+
+	_, err = c.AddJob(global.Config.Job.OutboxRelayCron, cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(relayOutbox)))
+	checkErrorPannic(err, "Schedule outbox relay job error")
+
+	_, err = c.AddFunc("@daily", deleteOutboxEvents)
+	checkErrorPannic(err, "Schedule outbox cleanup job error")
+
+	_, err = c.AddJob(global.Config.Job.WebhookDispatchCron, cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(dispatchWebhooks)))
+	checkErrorPannic(err, "Schedule webhook dispatch job error")
+
+	_, err = c.AddFunc("@daily", deleteWebhookDeliveries)
+	checkErrorPannic(err, "Schedule webhook delivery cleanup job error")
+
+	if err := services.Service.ReportService.StartSchedules(context.Background(), c); err != nil {
+		global.Logger.Error("Schedule reports error", zap.Error(err))
+	}
+	_, err = c.AddFunc(global.Config.Job.ReportScheduleSyncCron, syncReportSchedules)
+	checkErrorPannic(err, "Schedule report schedule sync job error")
+
+	c.Start()
+	global.Cron = c
+	global.Logger.Info("Init job success")
+}
+
+func takeInventorySnapshot() {
+	count, err := services.Service.InventorySnapshotService.TakeDailySnapshot(context.Background())
+	if err != nil {
+		global.Logger.Error("Take inventory snapshot error", zap.Error(err))
+		return
+	}
+	global.Logger.Info("Take inventory snapshot success", zap.Int64("products", count))
+}
+
+func syncReportSchedules() {
+	if err := services.Service.ReportService.SyncSchedules(context.Background()); err != nil {
+		global.Logger.Error("Sync report schedules error", zap.Error(err))
+	}
+}
+
+func relayOutbox() {
+	count, err := services.Service.OutboxService.Relay(context.Background())
+	if err != nil {
+		global.Logger.Error("Relay outbox events error", zap.Int("published", count), zap.Error(err))
+	}
+}
+
+func deleteOutboxEvents() {
+	retention := time.Duration(global.Config.Job.OutboxRetentionDays) * 24 * time.Hour
+	count, err := services.Service.OutboxService.DeletePublished(context.Background(), retention)
+	if err != nil {
+		global.Logger.Error("Delete outbox events error", zap.Error(err))
+		return
+	}
+	global.Logger.Info("Delete outbox events success", zap.Int64("events", count))
+}
+
+func dispatchWebhooks() {
+	count, err := services.Service.WebhookService.Dispatch(context.Background())
+	if err != nil {
+		global.Logger.Error("Dispatch webhook deliveries error", zap.Int("delivered", count), zap.Error(err))
+	}
+}
+
+func deleteWebhookDeliveries() {
+	retention := time.Duration(global.Config.Webhook.RetentionDays) * 24 * time.Hour
+	count, err := services.Service.WebhookService.DeleteDelivered(context.Background(), retention)
+	if err != nil {
+		global.Logger.Error("Delete webhook deliveries error", zap.Error(err))
+		return
+	}
+	global.Logger.Info("Delete webhook deliveries success", zap.Int64("deliveries", count))
+}
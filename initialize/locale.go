@@ -0,0 +1,19 @@
+package initialize
+
+import (
+	"stock-management/global"
+	rs "stock-management/pkgs/response"
+
+	"go.uber.org/zap"
+)
+
+// InitLocale loads the message catalogs. Without them every message is in English.
+func InitLocale() {
+	cfg := global.Config.Locale
+	if cfg.Path == "" {
+		return
+	}
+	if err := rs.LoadCatalogs(cfg.Path, cfg.Default); err != nil {
+		global.Logger.Error("Load message catalogs error", zap.Error(err))
+	}
+}
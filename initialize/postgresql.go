@@ -29,25 +29,27 @@ func InitPostgreSQL() {
 	global.Logger.Info("Init PostgreSQL success")
 	global.Pdb = db
 
-	err = installUUIDExtension()
+	err = installExtension("uuid-ossp")
 	checkErrorPannic(err, "Install uuid-ossp extension error")
+	err = installExtension("pg_trgm")
+	checkErrorPannic(err, "Install pg_trgm extension error")
 
 	setPostgrePool()
 	// migratePostgreTables()
 }
 
-func installUUIDExtension() error {
+func installExtension(name string) error {
 	sqlDb, err := global.Pdb.DB()
 	if err != nil {
 		return fmt.Errorf("error getting SQL DB: %v", err)
 	}
 
-	_, err = sqlDb.Exec("CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";")
+	_, err = sqlDb.Exec(fmt.Sprintf("CREATE EXTENSION IF NOT EXISTS %q;", name))
 	if err != nil {
-		return fmt.Errorf("failed to create uuid-ossp extension: %v", err)
+		return fmt.Errorf("failed to create %s extension: %v", name, err)
 	}
 
-	global.Logger.Info("uuid-ossp extension installed successfully")
+	global.Logger.Info(name + " extension installed successfully")
 	return nil
 }
 
@@ -70,10 +72,37 @@ func migratePostgreTables() {
 		&models.Product{},
 		&models.Supplier{},
 		&models.ProductCategory{},
+		&models.InventorySnapshot{},
+		&models.StockMovement{},
+		&models.PurchaseOrder{},
+		&models.PurchaseOrderLine{},
+		&models.User{},
+		&models.RefreshToken{},
+		&models.ApiKey{},
+		&models.Role{},
+		&models.RolePermission{},
+		&models.UserRole{},
+		&models.AuditLog{},
+		&models.SavedSearch{},
+		&models.ReportSchedule{},
+		&models.ReportRun{},
+		&models.OutboxEvent{},
+		&models.Webhook{},
+		&models.WebhookDelivery{},
 	)
 	if err != nil {
 		fmt.Printf("PostgreSQL migration error: %s", err)
 		return
 	}
+	if err := global.Pdb.Exec("CREATE SEQUENCE IF NOT EXISTS " + models.ProductReferenceSeq).Error; err != nil {
+		fmt.Printf("PostgreSQL migration error: %s", err)
+		return
+	}
+	for _, index := range models.ProductSearchIndexes {
+		if err := global.Pdb.Exec(index).Error; err != nil {
+			fmt.Printf("PostgreSQL migration error: %s", err)
+			return
+		}
+	}
 	fmt.Print("PostgreSQL migration success")
 }
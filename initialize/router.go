@@ -24,5 +24,6 @@ func InitRouter() *gin.Engine {
 	managerRouter := routers.RouterGroupApp.Manager
 	mainGroup := r.Group("api")
 	managerRouter.InitAdminRouter(mainGroup)
+	managerRouter.InitApiV2Router(r.Group("api/v2"))
 	return r
 }
@@ -15,9 +15,11 @@ func Run() {
 	InitLoadConfig()
 	InitLogger()
 	global.Logger.Info("Logger initialized", zap.String("status", "success"))
+	InitLocale()
 	InitPostgreSQL()
 	InitRedis()
 	InitService()
+	InitJob()
 	r := InitRouter()
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	port := strconv.Itoa(global.Config.Server.Port)
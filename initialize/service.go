@@ -1,7 +1,20 @@
 package initialize
 
-import "stock-management/internal/services"
+import (
+	"context"
+	"stock-management/global"
+	"stock-management/internal/services"
+
+	"go.uber.org/zap"
+)
 
 func InitService() {
 	services.InitService()
+
+	if err := services.Service.AuthService.EnsureAdminUser(context.Background()); err != nil {
+		global.Logger.Error("Create admin user error", zap.Error(err))
+	}
+	if err := services.Service.RbacService.EnsureDefaultRoles(context.Background()); err != nil {
+		global.Logger.Error("Create default roles error", zap.Error(err))
+	}
 }
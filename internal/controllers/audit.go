@@ -0,0 +1,44 @@
+package controller
+
+import (
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var Audit = new(AuditController)
+
+type AuditController struct{}
+
+// GetAuditList lists audit log entries
+// @Summary Retrieve audit log
+// @Description Returns audit log entries of product, supplier and category writes, newest first, filtered by entity, actor and date range
+// @Tags Audit
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.AuditSearchReq true "Search parameters"
+// @Success 200 {object} models.SearchRp
+// @Router /api/audit/list [post]
+func (ac *AuditController) GetAuditList(c *gin.Context) {
+	var req models.AuditSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	logs, err := services.Service.AuditService.GetAuditList(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, logs)
+}
@@ -0,0 +1,249 @@
+package controller
+
+import (
+	"errors"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var Auth = new(AuthController)
+
+type AuthController struct{}
+
+// Login issues an access token and a refresh token
+// @Summary Login
+// @Description Exchanges a username and password for a signed JWT access token and a refresh token
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.LoginReq true "Credentials"
+// @Success 200 {object} models.TokenRp
+// @Router /api/auth/login [post]
+func (ac *AuthController) Login(c *gin.Context) {
+	var req models.LoginReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.UnauthorizedResponse(c, code)
+		return
+	}
+
+	tokens, err := services.Service.AuthService.Login(c, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			rs.UnauthorizedResponse(c, rs.ErrInvalidCredentials)
+			return
+		}
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, tokens)
+}
+
+// Refresh exchanges a refresh token for a new token pair
+// @Summary Refresh tokens
+// @Description Exchanges a refresh token for a new access token and refresh token. Each refresh token can be used once.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.RefreshTokenReq true "Refresh token"
+// @Success 200 {object} models.TokenRp
+// @Router /api/auth/refresh [post]
+func (ac *AuthController) Refresh(c *gin.Context) {
+	var req models.RefreshTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.UnauthorizedResponse(c, code)
+		return
+	}
+
+	tokens, err := services.Service.AuthService.Refresh(c, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			rs.UnauthorizedResponse(c, rs.ErrInvalidToken)
+			return
+		}
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, tokens)
+}
+
+// Logout revokes a refresh token
+// @Summary Logout
+// @Description Revokes the given refresh token
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Param request body models.RefreshTokenReq true "Refresh token"
+// @Success 200 {object} response.ResponseData
+// @Router /api/auth/logout [post]
+func (ac *AuthController) Logout(c *gin.Context) {
+	var req models.RefreshTokenReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	if err := services.Service.AuthService.Logout(c, req); err != nil {
+		if errors.Is(err, services.ErrInvalidToken) {
+			rs.FailResponseWithCode(c, rs.ErrInvalidToken)
+			return
+		}
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+// Me returns the authenticated caller
+// @Summary Current user
+// @Description Returns the user authenticated by the access token or API key
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {object} models.AuthUser
+// @Router /api/auth/me [get]
+func (ac *AuthController) Me(c *gin.Context) {
+	rs.SuccessResponse(c, getAuthUser(c))
+}
+
+// CreateUser creates a new user
+// @Summary Create user
+// @Description Creates a new active user that can log in
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.UserCreateReq true "User creation details"
+// @Success 200 {object} models.User
+// @Router /api/user/create [post]
+func (ac *AuthController) CreateUser(c *gin.Context) {
+	var req models.UserCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	user, err := services.Service.AuthService.CreateUser(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, user)
+}
+
+// CreateApiKey creates an API key for the current user
+// @Summary Create API key
+// @Description Creates an API key for machine clients of the current user. The key is only returned once.
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ApiKeyCreateReq true "API key details"
+// @Success 200 {object} models.ApiKeyCreateRp
+// @Router /api/api-key/create [post]
+func (ac *AuthController) CreateApiKey(c *gin.Context) {
+	var req models.ApiKeyCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	key, err := services.Service.AuthService.CreateApiKey(c, getAuthUser(c).UserID, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, key)
+}
+
+// GetApiKeyList lists the API keys of the current user
+// @Summary Retrieve API key list
+// @Description Returns the API keys of the current user without their secret
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} models.ApiKey
+// @Router /api/api-key/list [post]
+func (ac *AuthController) GetApiKeyList(c *gin.Context) {
+	keys, err := services.Service.AuthService.GetApiKeyList(c, getAuthUser(c).UserID)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, keys)
+}
+
+// RevokeApiKey revokes an API key of the current user
+// @Summary Revoke API key
+// @Description Revokes an API key of the current user
+// @Tags Auth
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ApiKeyRevokeReq true "API key ID"
+// @Success 200 {object} response.ResponseData
+// @Router /api/api-key/revoke [post]
+func (ac *AuthController) RevokeApiKey(c *gin.Context) {
+	var req models.ApiKeyRevokeReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	id, err := uuid.Parse(req.ApiKeyID)
+	if err != nil {
+		rs.FailResponseWithCode(c, rs.ErrInvalidApiKey)
+		return
+	}
+
+	if err := services.Service.AuthService.RevokeApiKey(c, getAuthUser(c).UserID, id); err != nil {
+		if errors.Is(err, services.ErrInvalidApiKey) {
+			rs.FailResponseWithCode(c, rs.ErrInvalidApiKey)
+			return
+		}
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+func getAuthUser(c *gin.Context) *models.AuthUser {
+	if user, ok := c.Get(models.AuthUserKey); ok {
+		return user.(*models.AuthUser)
+	}
+	return &models.AuthUser{}
+}
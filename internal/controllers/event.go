@@ -0,0 +1,135 @@
+package controller
+
+import (
+	"errors"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var Event = new(EventController)
+
+type EventController struct{}
+
+// GetEventList reads the event stream
+// @Summary Retrieve domain events
+// @Description Reads the domain event stream from a position, oldest first. Pass next as from to read the following page; it is empty at the end of the stream.
+// @Tags Event
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.EventSearchReq true "Position, event types and limit"
+// @Success 200 {object} models.EventListRp
+// @Router /api/event/list [post]
+func (ec *EventController) GetEventList(c *gin.Context) {
+	var req models.EventSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	events, err := services.Service.EventService.GetEvents(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, events)
+}
+
+// GetGroupList lists the consumer groups of the event stream
+// @Summary Retrieve event consumer groups
+// @Description Returns the consumer groups of the event stream with their consumers, pending events, last delivered ID and lag
+// @Tags Event
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} models.EventGroup
+// @Router /api/event/group/list [post]
+func (ec *EventController) GetGroupList(c *gin.Context) {
+	groups, err := services.Service.EventService.GetGroups(c)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, groups)
+}
+
+// CreateGroup creates a consumer group
+// @Summary Create event consumer group
+// @Description Creates a consumer group of the event stream. Its consumers read with XREADGROUP. from is $ (default) for new events only, 0 for every retained event, a stream ID or an RFC 3339 time.
+// @Tags Event
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.EventGroupCreateReq true "Group name and start position"
+// @Success 200 {object} response.ResponseData
+// @Router /api/event/group/create [post]
+func (ec *EventController) CreateGroup(c *gin.Context) {
+	var req models.EventGroupCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	if err := services.Service.EventService.CreateGroup(c, req); err != nil {
+		failEvent(c, err)
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+// Replay moves a consumer group back in the stream
+// @Summary Replay events to a consumer group
+// @Description Moves a consumer group to a position so that its consumers read the events from there again: a stream ID or an RFC 3339 time, 0 for every retained event
+// @Tags Event
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.EventReplayReq true "Group name and position"
+// @Success 200 {object} response.ResponseData
+// @Router /api/event/replay [post]
+func (ec *EventController) Replay(c *gin.Context) {
+	var req models.EventReplayReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	if err := services.Service.EventService.Replay(c, req); err != nil {
+		failEvent(c, err)
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+func failEvent(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrEventGroupExists):
+		rs.FailResponseWithCode(c, rs.ErrDuplicateName)
+	case errors.Is(err, models.ErrEventGroupNotFound):
+		rs.FailResponseWithCode(c, rs.ErrInvalidEventGroup)
+	default:
+		rs.FailResponseWithMessage(c, err.Error())
+	}
+}
@@ -0,0 +1,113 @@
+package controller
+
+import (
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var Planning = new(PlanningController)
+
+type PlanningController struct{}
+
+// GetSuggestions returns suggested purchase quantities
+// @Summary Get suggested purchase quantities
+// @Description Forecasts daily demand from issue movements and suggests purchase quantities covering supplier lead time, review period and safety stock
+// @Tags Planning
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param method query string false "moving_average | exponential_smoothing (default moving_average)"
+// @Param history_days query int false "Days of issue history used for the forecast (default 90)"
+// @Param window query int false "Moving average window in days (default 30)"
+// @Param alpha query number false "Exponential smoothing factor between 0 and 1 (default 0.3)"
+// @Param review_days query int false "Days until the next planning review (default 7)"
+// @Param safety_days query int false "Days of forecast demand kept as safety stock (default 7)"
+// @Param supplier_ids query []string false "Restrict the suggestions to these suppliers" collectionFormat(multi)
+// @Success 200 {object} models.PlanningSuggestionRp
+// @Router /api/planning/suggestions [get]
+func (pc *PlanningController) GetSuggestions(c *gin.Context) {
+	var req models.PlanningSuggestionReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	suggestions, err := services.Service.PlanningService.GetSuggestions(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, suggestions)
+}
+
+// ConvertToPurchaseOrders creates draft purchase orders
+// @Summary Convert suggestions into draft purchase orders
+// @Description Creates one draft purchase order per supplier for the given products and quantities
+// @Tags Planning
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.PlanningConvertReq true "Products and quantities to order"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
+// @Success 200 {array} models.PurchaseOrder
+// @Router /api/planning/convert [post]
+func (pc *PlanningController) ConvertToPurchaseOrders(c *gin.Context) {
+	var req models.PlanningConvertReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	orders, err := services.Service.PlanningService.ConvertToPurchaseOrders(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, orders)
+}
+
+// GetPurchaseOrderList retrieves purchase orders based on search criteria
+// @Summary Retrieve purchase order list
+// @Description Returns the purchase orders matching the search request with their lines
+// @Tags Planning
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.PurchaseOrderSearchReq true "Purchase order search details"
+// @Success 200 {object} models.SearchRp
+// @Router /api/purchase-order/list [post]
+func (pc *PlanningController) GetPurchaseOrderList(c *gin.Context) {
+	var req models.PurchaseOrderSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	orders, err := services.Service.PlanningService.GetPurchaseOrderList(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, orders)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"stock-management/internal/models"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// badRequestProblem responds 400 to a body or query that can't be decoded.
+func badRequestProblem(c *gin.Context, err error) {
+	rs.ProblemResponse(c, http.StatusBadRequest, rs.ErrBadRequest, err.Error())
+}
+
+// validationProblem responds 422 to a well-formed request that failed validation.
+func validationProblem(c *gin.Context, code rs.RespCode) {
+	rs.ProblemResponse(c, http.StatusUnprocessableEntity, code, "")
+}
+
+// errorProblem maps a service error to its HTTP status. Unknown errors are logged and
+// answered with 500 without their message.
+func errorProblem(c *gin.Context, err error) {
+	var validationErr *models.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		rs.ValidationProblemResponse(c, validationErr.Fields)
+	case errors.Is(err, models.ErrProductNotFound):
+		rs.ProblemResponse(c, http.StatusNotFound, rs.ErrInvalidProduct, "")
+	case errors.Is(err, models.ErrSupplierNotFound):
+		validationProblem(c, rs.ErrInvalidSupplier)
+	case errors.Is(err, models.ErrCategoryNotFound):
+		validationProblem(c, rs.ErrInvalidCategory)
+	case errors.Is(err, models.ErrInsufficientStock):
+		rs.ProblemResponse(c, http.StatusUnprocessableEntity, rs.ErrInvalidQuantity, err.Error())
+	case errors.Is(err, models.ErrDuplicateReference):
+		rs.ProblemResponse(c, http.StatusConflict, rs.ErrDuplicateReference, "")
+	case errors.Is(err, models.ErrDuplicateBarcode):
+		rs.ProblemResponse(c, http.StatusConflict, rs.ErrDuplicateBarcode, "")
+	case errors.Is(err, models.ErrVersionConflict):
+		rs.ProblemResponse(c, http.StatusConflict, rs.ErrVersionConflict, "")
+	default:
+		getLogger(c).Error("Request error", zap.Error(err))
+		rs.ProblemResponse(c, http.StatusInternalServerError, rs.ErrInternal, "")
+	}
+}
+
+// versionedProblem is errorProblem for updates: a stale version is 412 when the client
+// sent If-Match.
+func versionedProblem(c *gin.Context, err error, hasIfMatch bool) {
+	if hasIfMatch && errors.Is(err, models.ErrVersionConflict) {
+		rs.PreconditionFailedResponse(c, rs.ErrVersionConflict)
+		return
+	}
+	errorProblem(c, err)
+}
+
+func getLogger(c *gin.Context) *zap.Logger {
+	if logger, ok := c.Get("Logger"); ok {
+		return logger.(*zap.Logger)
+	}
+	return zap.NewNop()
+}
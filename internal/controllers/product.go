@@ -1,15 +1,18 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"stock-management/internal/models"
 	"stock-management/internal/services"
 	rs "stock-management/pkgs/response"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
-	"github.com/jung-kurt/gofpdf"
 )
 
 var Product = new(ProductController)
@@ -22,6 +25,8 @@ type ProductController struct{}
 // @Tags Product
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductSearchReq true "Product search details"
 // @Success 200 {object} models.ProductSearchRp
 // @Router /api/product/list [post]
@@ -55,7 +60,10 @@ func (pc *ProductController) GetProductList(c *gin.Context) {
 // @Tags Product
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductCreateReq true "Product creation details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
 // @Success 200 {object} models.Product
 // @Router /api/product/create [post]
 func (pc *ProductController) CreateProduct(c *gin.Context) {
@@ -65,17 +73,17 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
 	}
-	code := req.Validate()
-	if code != rs.OkCode {
-		rs.FailResponseWithCode(c, code)
+	if errs := req.Validate(); len(errs) > 0 {
+		rs.ValidationFailResponse(c, errs)
 		return
 	}
 
 	product, err := services.Service.ProductService.CreateProduct(c, req)
 	if err != nil {
-		rs.FailResponseWithMessage(c, err.Error())
+		failProduct(c, err)
 		return
 	}
+	setETag(c, product.Version)
 	rs.SuccessResponse(c, product)
 }
 
@@ -85,9 +93,13 @@ func (pc *ProductController) CreateProduct(c *gin.Context) {
 // @Tags Product
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductUpdateReq true "Product update details"
+// @Param If-Match header string false "ETag of the product being updated, used when version is not in the body"
 // @Success 200 {object} models.Product
-// @Router /api/product/update [post]
+// @Failure 412 {object} response.ResponseData
+// @Router /api/product/update [put]
 func (pc *ProductController) UpdateProduct(c *gin.Context) {
 	var req models.ProductUpdateReq
 
@@ -95,17 +107,64 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
 	}
-	code := req.Validate()
-	if code != rs.OkCode {
-		rs.FailResponseWithCode(c, code)
+
+	hasIfMatch, ok := applyIfMatch(c, &req.Version)
+	if !ok {
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		rs.ValidationFailResponse(c, errs)
 		return
 	}
 
 	product, err := services.Service.ProductService.UpdateProduct(c, req)
 	if err != nil {
+		failVersionedUpdate(c, err, hasIfMatch)
+		return
+	}
+	setETag(c, product.Version)
+	rs.SuccessResponse(c, product)
+}
+
+// PatchProduct partially updates an existing product
+// @Summary Patch product
+// @Description Updates only the fields present in the body (JSON Merge Patch). product_id and version (or If-Match) are required; null values are rejected.
+// @Tags Product
+// @Accept  json
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ProductPatchReq true "Fields to change"
+// @Param If-Match header string false "ETag of the product being updated, used when version is not in the body"
+// @Success 200 {object} models.Product
+// @Failure 412 {object} response.ResponseData
+// @Router /api/product/update [patch]
+func (pc *ProductController) PatchProduct(c *gin.Context) {
+	var req models.ProductPatchReq
+
+	if err := c.ShouldBindJSON(&req); err != nil {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
 	}
+
+	hasIfMatch, ok := applyIfMatch(c, &req.Version)
+	if !ok {
+		return
+	}
+
+	if errs := req.Validate(); len(errs) > 0 {
+		rs.ValidationFailResponse(c, errs)
+		return
+	}
+
+	product, err := services.Service.ProductService.PatchProduct(c, req)
+	if err != nil {
+		failVersionedUpdate(c, err, hasIfMatch)
+		return
+	}
+	setETag(c, product.Version)
 	rs.SuccessResponse(c, product)
 }
 
@@ -115,6 +174,8 @@ func (pc *ProductController) UpdateProduct(c *gin.Context) {
 // @Tags Product
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductByIdReq true "Product ID details"
 // @Success 200 {object} models.Product
 // @Router /api/product/get [post]
@@ -136,6 +197,49 @@ func (pc *ProductController) GetProduct(c *gin.Context) {
 		return
 	}
 
+	if product != nil {
+		etag := setETag(c, product.Version)
+		if c.GetHeader("If-None-Match") == etag {
+			c.Status(http.StatusNotModified)
+			return
+		}
+	}
+	rs.SuccessResponse(c, product)
+}
+
+// LookupProduct finds a product by reference or barcode
+// @Summary Lookup product by code
+// @Description Returns the product whose reference or barcode (EAN-13/UPC-A/EAN-8) equals the code, for scanners. UPC-A codes also match their EAN-13 form.
+// @Tags Product
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param code query string true "Product reference or barcode"
+// @Success 200 {object} models.Product
+// @Router /api/product/lookup [get]
+func (pc *ProductController) LookupProduct(c *gin.Context) {
+	var req models.ProductLookupReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	product, err := services.Service.ProductService.GetProductByCode(c, req.Code)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	if product == nil {
+		rs.FailResponseWithCode(c, rs.ErrInvalidProduct)
+		return
+	}
+	setETag(c, product.Version)
 	rs.SuccessResponse(c, product)
 }
 
@@ -145,6 +249,8 @@ func (pc *ProductController) GetProduct(c *gin.Context) {
 // @Tags Product
 // @Accept  json
 // @Produce  application/pdf
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductSearchReq true "Product search details"
 // @Success 200 {file} application/pdf "Product List PDF"
 // @Router /api/product/export [post]
@@ -162,45 +268,55 @@ func (pc *ProductController) ExportProductsToPDF(c *gin.Context) {
 	req.Offset = 0
 	req.Limit = 0
 
-	products, err := services.Service.ProductService.GetProductList(c, req)
+	data, err := services.Service.ProductService.ExportProducts(c, req, models.ReportFormatPDF)
 	if err != nil {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
 	}
+	fileName := fmt.Sprintf("products_%s.pdf", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", data)
+}
 
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-	pdf.SetFont("Arial", "B", 12)
-	pdf.Cell(40, 10, "Product List")
-	pdf.Ln(10)
-	pdf.SetFont("Arial", "B", 10)
-	pdf.Cell(30, 10, "Product Name")
-	pdf.Cell(20, 10, "Price")
-	pdf.Cell(20, 10, "Quantity")
-	pdf.Cell(30, 10, "Stock Location")
-	pdf.Cell(30, 10, "Supplier")
-	pdf.Cell(30, 10, "Category")
-	pdf.Ln(10)
-
-	pdf.SetFont("Arial", "", 10)
-	for _, product := range products.Data {
-		pdf.Cell(30, 10, product.ProductName)
-		pdf.Cell(20, 10, fmt.Sprintf("%d", product.Price))
-		pdf.Cell(20, 10, fmt.Sprintf("%d", product.Quantity))
-		pdf.Cell(30, 10, product.StockLocation)
-		pdf.Cell(30, 10, product.Supplier.SupplierName)
-		pdf.Cell(30, 10, product.ProductCategory.ProductCategoryName)
-		pdf.Ln(10)
-	}
-	today := time.Now().Format("20060102")
-	fileName := fmt.Sprintf("products_%s.pdf", today)
-
-	err = pdf.OutputFileAndClose(fileName)
-	if err != nil {
+// PrintLabels renders product labels to a PDF sheet
+// @Summary Print product labels
+// @Description Generates a PDF sheet of labels with name, price, a barcode (EAN-13 when the product has one, Code128 of the reference otherwise) and a QR code of the reference. Sizes come from the label config.
+// @Tags Product
+// @Accept  json
+// @Produce  application/pdf
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ProductLabelReq true "Products and label options"
+// @Success 200 {file} application/pdf "Product labels PDF"
+// @Router /api/product/labels [post]
+func (pc *ProductController) PrintLabels(c *gin.Context) {
+	var req models.ProductLabelReq
+	if err := c.ShouldBindJSON(&req); err != nil {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
 	}
-	c.File(fileName)
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	data, err := services.Service.LabelService.RenderProductLabels(c, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLabelSize):
+			rs.FailResponseWithCode(c, rs.ErrInvalidLabelSize)
+		case errors.Is(err, services.ErrLabelProduct):
+			rs.FailResponseWithCode(c, rs.ErrInvalidProduct)
+		default:
+			rs.FailResponseWithMessage(c, err.Error())
+		}
+		return
+	}
+
+	fileName := fmt.Sprintf("labels_%s.pdf", time.Now().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Data(http.StatusOK, "application/pdf", data)
 }
 
 // GetProductDistance Calculate the distance
@@ -209,6 +325,8 @@ func (pc *ProductController) ExportProductsToPDF(c *gin.Context) {
 // @Tags Product
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductDistanceByIdReq true "Product distance request details"
 // @Success 200 {object} models.ProductDistanceRp
 // @Router /api/product/distance [post]
@@ -238,3 +356,66 @@ func (pc *ProductController) GetProductDistance(c *gin.Context) {
 	}
 	rs.SuccessResponse(c, product)
 }
+
+// setETag sets the ETag header to the entity version and returns it.
+func setETag(c *gin.Context, version int) string {
+	etag := strconv.Quote(strconv.Itoa(version))
+	c.Header("ETag", etag)
+	return etag
+}
+
+// applyIfMatch takes the version from the If-Match header when the body has none. It reports
+// whether the header was used, and responds 412 and returns false when it can't match the body.
+func applyIfMatch(c *gin.Context, version *int) (bool, bool) {
+	value := strings.TrimPrefix(strings.TrimSpace(c.GetHeader("If-Match")), "W/")
+	if value == "" || value == "*" {
+		return false, true
+	}
+	ifMatch, err := strconv.Atoi(strings.Trim(value, `"`))
+	// An ETag we never issued can't match any version.
+	if err != nil || ifMatch <= 0 || (*version != 0 && *version != ifMatch) {
+		rs.PreconditionFailedResponse(c, rs.ErrVersionConflict)
+		return true, false
+	}
+	*version = ifMatch
+	return true, true
+}
+
+// failVersionedUpdate responds to an update error. A stale version is 412 when the client sent
+// If-Match and the ErrVersionConflict code otherwise.
+func failVersionedUpdate(c *gin.Context, err error, hasIfMatch bool) {
+	if errors.Is(err, models.ErrVersionConflict) {
+		if hasIfMatch {
+			rs.PreconditionFailedResponse(c, rs.ErrVersionConflict)
+			return
+		}
+		rs.FailResponseWithCode(c, rs.ErrVersionConflict)
+		return
+	}
+	failProduct(c, err)
+}
+
+// failProduct responds to a product write error, with the invalid fields for a
+// *models.ValidationError.
+func failProduct(c *gin.Context, err error) {
+	var validationErr *models.ValidationError
+	if errors.As(err, &validationErr) {
+		rs.ValidationFailResponse(c, validationErr.Fields)
+		return
+	}
+	if code, ok := productErrorCode(err); ok {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+	rs.FailResponseWithMessage(c, err.Error())
+}
+
+func productErrorCode(err error) (rs.RespCode, bool) {
+	switch {
+	case errors.Is(err, models.ErrDuplicateReference):
+		return rs.ErrDuplicateReference, true
+	case errors.Is(err, models.ErrDuplicateBarcode):
+		return rs.ErrDuplicateBarcode, true
+	}
+	return 0, false
+}
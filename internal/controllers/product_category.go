@@ -18,6 +18,8 @@ type ProductCategoryController struct{}
 // @Tags ProductCategory
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductCategorySearchReq true "ProductCategory search details"
 // @Success 200 {object} models.SearchRp
 // @Router /api/product-category/list [post]
@@ -46,7 +48,10 @@ func (pc *ProductCategoryController) GetProductCategoryList(c *gin.Context) {
 // @Tags ProductCategory
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.ProductCategoryCreateReq true "ProductCategory creation details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
 // @Success 201 {object} models.ProductCategory
 // @Router /api/product-category/create [post]
 func (pc *ProductCategoryController) CreateProductCategory(c *gin.Context) {
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var ProductCategoryV2 = new(ProductCategoryV2Controller)
+
+type ProductCategoryV2Controller struct{}
+
+// ListProductCategories lists product categories
+// @Summary List product categories
+// @Description Returns the product categories matching the query filters
+// @Tags ProductCategoryV2
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param product_category_name query string false "Category name"
+// @Param status query string false "Status"
+// @Param limit query int false "Page size (default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} models.SearchRp
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/categories [get]
+func (pc *ProductCategoryV2Controller) ListProductCategories(c *gin.Context) {
+	var req models.ProductCategorySearchReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	productCategories, err := services.Service.CategoryService.GetProductCategoryList(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, productCategories)
+}
+
+// CreateProductCategory creates a product category
+// @Summary Create product category
+// @Description Creates a product category
+// @Tags ProductCategoryV2
+// @Accept  json
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ProductCategoryCreateReq true "Product category creation details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
+// @Success 201 {object} models.ProductCategory
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/categories [post]
+func (pc *ProductCategoryV2Controller) CreateProductCategory(c *gin.Context) {
+	var req models.ProductCategoryCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	productCategory, err := services.Service.CategoryService.CreateProductCategory(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, productCategory)
+}
@@ -0,0 +1,344 @@
+package controller
+
+import (
+	"net/http"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var ProductV2 = new(ProductV2Controller)
+
+// ProductV2Controller serves /api/v2/products. Successful responses have no envelope and
+// errors are problem details with the matching HTTP status.
+type ProductV2Controller struct{}
+
+// ListProducts lists products
+// @Summary List products
+// @Description Returns the products matching the query filters. Repeat a parameter to pass several values.
+// @Tags ProductV2
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param query query string false "Free-text search of name, reference, category and supplier names, ordered by relevance"
+// @Param filter query string false "Filter expression, e.g. quantity < 10 AND (status = 'On Order' OR supplier.name ~ 'ACME')"
+// @Param product_references query []string false "Product references" collectionFormat(multi)
+// @Param product_names query []string false "Product names" collectionFormat(multi)
+// @Param status query []string false "Statuses" collectionFormat(multi)
+// @Param product_category_ids query []string false "Category IDs" collectionFormat(multi)
+// @Param supplier_ids query []string false "Supplier IDs" collectionFormat(multi)
+// @Param price_from query int false "Minimum price"
+// @Param price_to query int false "Maximum price"
+// @Param stock_locations query []string false "Stock locations" collectionFormat(multi)
+// @Param abc_classes query []string false "ABC classes" collectionFormat(multi)
+// @Param date_created_from query string false "Created from (YYYY-MM-DD)"
+// @Param date_created_to query string false "Created to (YYYY-MM-DD)"
+// @Param sort query string false "Comma-separated name, price, quantity, date; prefix - for descending (default -date, relevance with query)"
+// @Param cursor query string false "next_cursor of the previous page, replaces offset"
+// @Param facets query bool false "Also count the products per status, category, supplier, stock location and price bucket"
+// @Param limit query int false "Page size (default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} models.ProductSearchRp
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/products [get]
+func (pc *ProductV2Controller) ListProducts(c *gin.Context) {
+	var req models.ProductSearchReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code == rs.ErrInvalidFilter {
+		rs.ProblemResponse(c, http.StatusUnprocessableEntity, code, req.FilterErr.Error())
+		return
+	}
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+
+	products, err := services.Service.ProductService.GetProductList(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, products)
+}
+
+// GetProduct retrieves a product
+// @Summary Get product
+// @Description Returns the product with its ETag. A matching If-None-Match gets 304.
+// @Tags ProductV2
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Product ID"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Success 200 {object} models.Product
+// @Success 304
+// @Failure 404 {object} response.ProblemDetails
+// @Router /api/v2/products/{id} [get]
+func (pc *ProductV2Controller) GetProduct(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	product, err := services.Service.ProductService.GetProduct(c, productID)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	if product == nil {
+		errorProblem(c, models.ErrProductNotFound)
+		return
+	}
+
+	etag := setETag(c, product.Version)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	c.JSON(http.StatusOK, product)
+}
+
+// LookupProduct finds a product by reference or barcode
+// @Summary Lookup product by code
+// @Description Returns the product whose reference or barcode (EAN-13/UPC-A/EAN-8) equals the code.
+// @Tags ProductV2
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param code query string true "Product reference or barcode"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/products/lookup [get]
+func (pc *ProductV2Controller) LookupProduct(c *gin.Context) {
+	var req models.ProductLookupReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	product, err := services.Service.ProductService.GetProductByCode(c, req.Code)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	if product == nil {
+		errorProblem(c, models.ErrProductNotFound)
+		return
+	}
+	setETag(c, product.Version)
+	c.JSON(http.StatusOK, product)
+}
+
+// CreateProduct creates a product
+// @Summary Create product
+// @Description Creates a product and returns it with its Location and ETag
+// @Tags ProductV2
+// @Accept  json
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ProductCreateReq true "Product creation details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
+// @Success 201 {object} models.Product
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 409 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/products [post]
+func (pc *ProductV2Controller) CreateProduct(c *gin.Context) {
+	var req models.ProductCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	if errs := req.Validate(); len(errs) > 0 {
+		rs.ValidationProblemResponse(c, errs)
+		return
+	}
+
+	product, err := services.Service.ProductService.CreateProduct(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	setETag(c, product.Version)
+	c.Header("Location", c.Request.URL.Path+"/"+product.ProductID.String())
+	c.JSON(http.StatusCreated, product)
+}
+
+// UpdateProduct replaces a product
+// @Summary Update product
+// @Description Replaces the product fields. version is required in the body or as If-Match.
+// @Tags ProductV2
+// @Accept  json
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Product ID"
+// @Param request body models.ProductUpdateReq true "Product update details, product_id is taken from the path"
+// @Param If-Match header string false "ETag of the product being updated"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} response.ProblemDetails
+// @Failure 409 {object} response.ProblemDetails
+// @Failure 412 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/products/{id} [put]
+func (pc *ProductV2Controller) UpdateProduct(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.ProductUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	if !matchPathID(c, req.ProductID, productID) {
+		return
+	}
+	req.ProductID = productID.String()
+
+	hasIfMatch, ok := applyIfMatch(c, &req.Version)
+	if !ok {
+		return
+	}
+	if errs := req.Validate(); len(errs) > 0 {
+		rs.ValidationProblemResponse(c, errs)
+		return
+	}
+
+	product, err := services.Service.ProductService.UpdateProduct(c, req)
+	if err != nil {
+		versionedProblem(c, err, hasIfMatch)
+		return
+	}
+	setETag(c, product.Version)
+	c.JSON(http.StatusOK, product)
+}
+
+// PatchProduct partially updates a product
+// @Summary Patch product
+// @Description Updates only the fields present in the body (JSON Merge Patch). version is required in the body or as If-Match; null values are rejected.
+// @Tags ProductV2
+// @Accept  json
+// @Accept  application/merge-patch+json
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Product ID"
+// @Param request body models.ProductPatchReq true "Fields to change, product_id is taken from the path"
+// @Param If-Match header string false "ETag of the product being updated"
+// @Success 200 {object} models.Product
+// @Failure 404 {object} response.ProblemDetails
+// @Failure 409 {object} response.ProblemDetails
+// @Failure 412 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/products/{id} [patch]
+func (pc *ProductV2Controller) PatchProduct(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	var req models.ProductPatchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	if !matchPathID(c, req.ProductID, productID) {
+		return
+	}
+	req.ProductID = productID.String()
+
+	hasIfMatch, ok := applyIfMatch(c, &req.Version)
+	if !ok {
+		return
+	}
+	if errs := req.Validate(); len(errs) > 0 {
+		rs.ValidationProblemResponse(c, errs)
+		return
+	}
+
+	product, err := services.Service.ProductService.PatchProduct(c, req)
+	if err != nil {
+		versionedProblem(c, err, hasIfMatch)
+		return
+	}
+	setETag(c, product.Version)
+	c.JSON(http.StatusOK, product)
+}
+
+// DeleteProduct deletes a product
+// @Summary Delete product
+// @Description Deletes the product. With If-Match the product is only deleted if its version matches.
+// @Tags ProductV2
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param id path string true "Product ID"
+// @Param If-Match header string false "ETag of the product being deleted"
+// @Success 204
+// @Failure 404 {object} response.ProblemDetails
+// @Failure 412 {object} response.ProblemDetails
+// @Router /api/v2/products/{id} [delete]
+func (pc *ProductV2Controller) DeleteProduct(c *gin.Context) {
+	productID, ok := productIDParam(c)
+	if !ok {
+		return
+	}
+	var version int
+	hasIfMatch, ok := applyIfMatch(c, &version)
+	if !ok {
+		return
+	}
+
+	if err := services.Service.ProductService.DeleteProduct(c, productID, version); err != nil {
+		versionedProblem(c, err, hasIfMatch)
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// productIDParam parses the id path parameter. An id that isn't a UUID can't exist, so it
+// is answered with 404.
+func productIDParam(c *gin.Context) (uuid.UUID, bool) {
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		errorProblem(c, models.ErrProductNotFound)
+		return uuid.Nil, false
+	}
+	return productID, true
+}
+
+// matchPathID rejects a body product_id that names another product than the path.
+func matchPathID(c *gin.Context, bodyID string, productID uuid.UUID) bool {
+	if bodyID == "" {
+		return true
+	}
+	if id, err := uuid.Parse(bodyID); err != nil || id != productID {
+		validationProblem(c, rs.ErrInvalidProduct)
+		return false
+	}
+	return true
+}
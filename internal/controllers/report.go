@@ -0,0 +1,312 @@
+package controller
+
+import (
+	"errors"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var Report = new(ReportController)
+
+type ReportController struct{}
+
+// GetSavedSearchList lists the saved searches of the current user
+// @Summary Retrieve saved search list
+// @Description Returns the saved product searches of the current user, by name
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} models.SavedSearch
+// @Router /api/saved-search/list [post]
+func (rc *ReportController) GetSavedSearchList(c *gin.Context) {
+	searches, err := services.Service.ReportService.GetSavedSearchList(c, getAuthUser(c).UserID)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, searches)
+}
+
+// CreateSavedSearch saves a product search
+// @Summary Create saved search
+// @Description Saves a product search under a name unique to the current user. Paging fields are dropped: reports include all the matching products.
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.SavedSearchCreateReq true "Name and search"
+// @Success 200 {object} models.SavedSearch
+// @Router /api/saved-search/create [post]
+func (rc *ReportController) CreateSavedSearch(c *gin.Context) {
+	var req models.SavedSearchCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	search, err := services.Service.ReportService.CreateSavedSearch(c, getAuthUser(c).UserID, req)
+	if err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, search)
+}
+
+// UpdateSavedSearch replaces a saved search
+// @Summary Update saved search
+// @Description Replaces the name and search of a saved search of the current user
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.SavedSearchUpdateReq true "Saved search ID, name and search"
+// @Success 200 {object} models.SavedSearch
+// @Router /api/saved-search/update [put]
+func (rc *ReportController) UpdateSavedSearch(c *gin.Context) {
+	var req models.SavedSearchUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	search, err := services.Service.ReportService.UpdateSavedSearch(c, getAuthUser(c).UserID, req)
+	if err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, search)
+}
+
+// DeleteSavedSearch deletes a saved search
+// @Summary Delete saved search
+// @Description Deletes a saved search of the current user with the report schedules running it and their history
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.SavedSearchDeleteReq true "Saved search ID"
+// @Success 200 {object} response.ResponseData
+// @Router /api/saved-search/delete [post]
+func (rc *ReportController) DeleteSavedSearch(c *gin.Context) {
+	var req models.SavedSearchDeleteReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	id, err := uuid.Parse(req.SavedSearchID)
+	if err != nil {
+		rs.FailResponseWithCode(c, rs.ErrInvalidSavedSearch)
+		return
+	}
+
+	if err := services.Service.ReportService.DeleteSavedSearch(c, getAuthUser(c).UserID, id); err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+// GetScheduleList lists the report schedules of the current user
+// @Summary Retrieve report schedule list
+// @Description Returns the report schedules of the current user, by name
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} models.ReportSchedule
+// @Router /api/report-schedule/list [post]
+func (rc *ReportController) GetScheduleList(c *gin.Context) {
+	schedules, err := services.Service.ReportService.GetScheduleList(c, getAuthUser(c).UserID)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, schedules)
+}
+
+// CreateSchedule creates a report schedule
+// @Summary Create report schedule
+// @Description Runs a saved search of the current user on a cron expression (5 fields or @daily, @weekly...) and writes the export to the report directory or emails it
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ReportScheduleCreateReq true "Schedule details"
+// @Success 200 {object} models.ReportSchedule
+// @Router /api/report-schedule/create [post]
+func (rc *ReportController) CreateSchedule(c *gin.Context) {
+	var req models.ReportScheduleCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	schedule, err := services.Service.ReportService.CreateSchedule(c, getAuthUser(c).UserID, req)
+	if err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, schedule)
+}
+
+// UpdateSchedule replaces a report schedule
+// @Summary Update report schedule
+// @Description Replaces a report schedule of the current user. It takes effect on the next run.
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ReportScheduleUpdateReq true "Schedule ID and details"
+// @Success 200 {object} models.ReportSchedule
+// @Router /api/report-schedule/update [put]
+func (rc *ReportController) UpdateSchedule(c *gin.Context) {
+	var req models.ReportScheduleUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	schedule, err := services.Service.ReportService.UpdateSchedule(c, getAuthUser(c).UserID, req)
+	if err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, schedule)
+}
+
+// DeleteSchedule deletes a report schedule
+// @Summary Delete report schedule
+// @Description Deletes a report schedule of the current user with its run history
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ReportScheduleIdReq true "Schedule ID"
+// @Success 200 {object} response.ResponseData
+// @Router /api/report-schedule/delete [post]
+func (rc *ReportController) DeleteSchedule(c *gin.Context) {
+	id, ok := scheduleIDBody(c)
+	if !ok {
+		return
+	}
+	if err := services.Service.ReportService.DeleteSchedule(c, getAuthUser(c).UserID, id); err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+// RunSchedule runs a report schedule now
+// @Summary Run report schedule
+// @Description Runs a report schedule of the current user now, even when disabled, and returns the run. A failed export or delivery has status failed and its error.
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ReportScheduleIdReq true "Schedule ID"
+// @Success 200 {object} models.ReportRun
+// @Router /api/report-schedule/run [post]
+func (rc *ReportController) RunSchedule(c *gin.Context) {
+	id, ok := scheduleIDBody(c)
+	if !ok {
+		return
+	}
+	run, err := services.Service.ReportService.RunSchedule(c, getAuthUser(c).UserID, id)
+	if err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, run)
+}
+
+// GetRunList lists the runs of a report schedule
+// @Summary Retrieve report run history
+// @Description Returns the runs of a report schedule of the current user, newest first
+// @Tags Report
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.ReportRunSearchReq true "Schedule ID and paging"
+// @Success 200 {object} models.SearchRp
+// @Router /api/report-schedule/runs [post]
+func (rc *ReportController) GetRunList(c *gin.Context) {
+	var req models.ReportRunSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	runs, err := services.Service.ReportService.GetRunList(c, getAuthUser(c).UserID, req)
+	if err != nil {
+		failReport(c, err)
+		return
+	}
+	rs.SuccessResponse(c, runs)
+}
+
+func scheduleIDBody(c *gin.Context) (uuid.UUID, bool) {
+	var req models.ReportScheduleIdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return uuid.Nil, false
+	}
+	id, err := uuid.Parse(req.ScheduleID)
+	if err != nil {
+		rs.FailResponseWithCode(c, rs.ErrInvalidSchedule)
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+func failReport(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrSavedSearchNotFound):
+		rs.FailResponseWithCode(c, rs.ErrInvalidSavedSearch)
+	case errors.Is(err, models.ErrDuplicateSearchName):
+		rs.FailResponseWithCode(c, rs.ErrDuplicateName)
+	case errors.Is(err, models.ErrScheduleNotFound):
+		rs.FailResponseWithCode(c, rs.ErrInvalidSchedule)
+	default:
+		rs.FailResponseWithMessage(c, err.Error())
+	}
+}
@@ -0,0 +1,217 @@
+package controller
+
+import (
+	"errors"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var Role = new(RoleController)
+
+type RoleController struct{}
+
+// GetRoleList lists the roles with their permissions
+// @Summary Retrieve role list
+// @Description Returns every role with the permissions it grants
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Role
+// @Router /api/role/list [post]
+func (rc *RoleController) GetRoleList(c *gin.Context) {
+	roles, err := services.Service.RbacService.GetRoleList(c)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, roles)
+}
+
+// GetPermissionList lists the permissions that can be granted
+// @Summary Retrieve permission list
+// @Description Returns every permission that can be granted to a role
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} string
+// @Router /api/role/permissions [get]
+func (rc *RoleController) GetPermissionList(c *gin.Context) {
+	rs.SuccessResponse(c, models.AllPermissions)
+}
+
+// CreateRole creates a role
+// @Summary Create role
+// @Description Creates a role granting the given permissions
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.RoleCreateReq true "Role details"
+// @Success 200 {object} models.Role
+// @Router /api/role/create [post]
+func (rc *RoleController) CreateRole(c *gin.Context) {
+	var req models.RoleCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	role, err := services.Service.RbacService.CreateRole(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, role)
+}
+
+// UpdateRole updates the description and permissions of a role
+// @Summary Update role
+// @Description Replaces the description and permissions of a role. The admin role cannot be changed.
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.RoleUpdateReq true "Role details"
+// @Success 200 {object} models.Role
+// @Router /api/role/update [put]
+func (rc *RoleController) UpdateRole(c *gin.Context) {
+	var req models.RoleUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	role, err := services.Service.RbacService.UpdateRole(c, req)
+	if err != nil {
+		if errors.Is(err, services.ErrRoleNotFound) {
+			rs.FailResponseWithCode(c, rs.ErrInvalidRole)
+			return
+		}
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, role)
+}
+
+// DeleteRole deletes a role
+// @Summary Delete role
+// @Description Deletes a role that is not assigned to any user. The admin role cannot be deleted.
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.RoleByIdReq true "Role ID"
+// @Success 200 {object} response.ResponseData
+// @Router /api/role/delete [post]
+func (rc *RoleController) DeleteRole(c *gin.Context) {
+	var req models.RoleByIdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	id, err := uuid.Parse(req.RoleID)
+	if err != nil {
+		rs.FailResponseWithCode(c, rs.ErrInvalidRole)
+		return
+	}
+
+	if err := services.Service.RbacService.DeleteRole(c, id); err != nil {
+		if errors.Is(err, services.ErrRoleNotFound) {
+			rs.FailResponseWithCode(c, rs.ErrInvalidRole)
+			return
+		}
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+// GetUserList lists users with their roles
+// @Summary Retrieve user list
+// @Description Returns users matching the filters with the names of their roles
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.UserSearchReq true "Search parameters"
+// @Success 200 {object} models.SearchRp
+// @Router /api/user/list [post]
+func (rc *RoleController) GetUserList(c *gin.Context) {
+	var req models.UserSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	users, err := services.Service.RbacService.GetUserList(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, users)
+}
+
+// AssignUserRoles replaces the roles of a user
+// @Description Replaces the roles of a user with the given roles. The admin role cannot be taken from the last user holding it.
+// @Description Replaces the roles of a user with the given roles
+// @Tags Role
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.UserAssignRolesReq true "User and role IDs"
+// @Success 200 {object} models.UserWithRoles
+// @Router /api/user/assign-roles [post]
+func (rc *RoleController) AssignUserRoles(c *gin.Context) {
+	var req models.UserAssignRolesReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	user, err := services.Service.RbacService.AssignUserRoles(c, req)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrUserNotFound):
+			rs.FailResponseWithCode(c, rs.ErrInvalidUser)
+		case errors.Is(err, services.ErrRoleNotFound):
+			rs.FailResponseWithCode(c, rs.ErrInvalidRole)
+		default:
+			rs.FailResponseWithMessage(c, err.Error())
+		}
+		return
+	}
+	rs.SuccessResponse(c, user)
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"stock-management/internal/models"
 	"stock-management/internal/services"
 	rs "stock-management/pkgs/response"
 
@@ -11,15 +12,29 @@ var Statistics = new(StatisticsController)
 
 type StatisticsController struct{}
 
-// @Summary Get percentage of products per category
-// @Description Get percentage of products per category
+// @Summary Get products statistics per category
+// @Description Get product count, total quantity or total stock value per category with percentages
 // @Tags Statistics
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} map[string]decimal.Decimal
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param metric query string false "count | quantity | value (default count)"
+// @Success 200 {object} models.StatisticsRp
 // @Router /api/statistics/products-per-category [get]
 func (pc *StatisticsController) GetProductPerCategory(c *gin.Context) {
-	results, err := services.Service.ProductService.GetProductPerCategory(c)
+	var req models.StatisticsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.ProductService.GetProductPerCategory(c, req.Metric)
 	if err != nil {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
@@ -27,18 +42,201 @@ func (pc *StatisticsController) GetProductPerCategory(c *gin.Context) {
 	rs.SuccessResponse(c, results)
 }
 
-// @Summary Get percentage of products per supplier
-// @Description Get percentage of products per supplier
+// @Summary Get products statistics per supplier
+// @Description Get product count, total quantity or total stock value per supplier with percentages
 // @Tags Statistics
 // @Accept  json
 // @Produce  json
-// @Success 200 {object} map[string]decimal.Decimal
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param metric query string false "count | quantity | value (default count)"
+// @Success 200 {object} models.StatisticsRp
 // @Router /api/statistics/products-per-supplier [get]
 func (pc *StatisticsController) GetProductPerSupplier(c *gin.Context) {
-	results, err := services.Service.ProductService.GetProductPerSupplier(c)
+	var req models.StatisticsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.ProductService.GetProductPerSupplier(c, req.Metric)
 	if err != nil {
 		rs.FailResponseWithMessage(c, err.Error())
 		return
 	}
 	rs.SuccessResponse(c, results)
 }
+
+// @Summary Get inventory time series
+// @Description Get quantity and value series per product, category or supplier from the daily inventory snapshots
+// @Tags Statistics
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param date_from query string true "Start date (YYYY-MM-DD)"
+// @Param date_to query string true "End date (YYYY-MM-DD)"
+// @Param bucket query string false "day | week | month (default day)"
+// @Param group_by query string false "product | category | supplier (default category)"
+// @Param ids query []string false "Restrict the series to these product, category or supplier IDs" collectionFormat(multi)
+// @Success 200 {object} models.TimeseriesRp
+// @Router /api/statistics/timeseries [get]
+func (pc *StatisticsController) GetTimeseries(c *gin.Context) {
+	var req models.TimeseriesReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.InventorySnapshotService.GetTimeseries(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, results)
+}
+
+// @Summary Get inventory turnover
+// @Description Get the inventory turnover ratio per product, category or supplier: value issued over the period divided by the average inventory value
+// @Tags Statistics
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param date_from query string false "Start date (YYYY-MM-DD, default 30 days before date_to)"
+// @Param date_to query string false "End date (YYYY-MM-DD, default today)"
+// @Param group_by query string false "product | category | supplier (default category)"
+// @Success 200 {object} models.TurnoverRp
+// @Router /api/statistics/turnover [get]
+func (pc *StatisticsController) GetTurnover(c *gin.Context) {
+	var req models.StockAnalyticsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.ValidatePeriod()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.StockAnalyticsService.GetTurnover(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, results)
+}
+
+// @Summary Get days of cover
+// @Description Get how many days the stock on hand of every product lasts at its average daily issue rate
+// @Tags Statistics
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param days query int false "Number of past days used for the average daily issue rate (default 30)"
+// @Success 200 {object} models.DaysOfCoverRp
+// @Router /api/statistics/days-of-cover [get]
+func (pc *StatisticsController) GetDaysOfCover(c *gin.Context) {
+	var req models.StockAnalyticsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.ValidateDays(30)
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.StockAnalyticsService.GetDaysOfCover(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, results)
+}
+
+// @Summary Get dead stock
+// @Description Get products in stock without any movement in the last N days, with totals per category and supplier
+// @Tags Statistics
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param days query int false "Number of days without movement (default 90)"
+// @Success 200 {object} models.DeadStockRp
+// @Router /api/statistics/dead-stock [get]
+func (pc *StatisticsController) GetDeadStock(c *gin.Context) {
+	var req models.StockAnalyticsReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.ValidateDays(90)
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.StockAnalyticsService.GetDeadStock(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, results)
+}
+
+// @Summary Run ABC analysis
+// @Description Classifies products into A/B/C by consumption value over the period and stores the class on each product
+// @Tags Statistics
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.AbcAnalysisReq true "ABC analysis period and cumulative percentage thresholds"
+// @Success 200 {object} models.AbcAnalysisRp
+// @Router /api/statistics/abc-analysis [post]
+func (pc *StatisticsController) RunAbcAnalysis(c *gin.Context) {
+	var req models.AbcAnalysisReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	results, err := services.Service.StockAnalyticsService.RunAbcAnalysis(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, results)
+}
+
+// @Summary Get product cache statistics
+// @Description Hits, misses and hit rate of the product and product list caches since the server started, with the number of invalidations and of Redis errors answered from the database
+// @Tags Statistics
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {object} models.CacheStatsRp
+// @Router /api/statistics/cache [get]
+func (pc *StatisticsController) GetCacheStats(c *gin.Context) {
+	rs.SuccessResponse(c, services.Service.ProductService.GetCacheStats())
+}
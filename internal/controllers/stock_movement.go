@@ -0,0 +1,76 @@
+package controller
+
+import (
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var StockMovement = new(StockMovementController)
+
+type StockMovementController struct{}
+
+// GetMovementList retrieves stock movements based on search criteria
+// @Summary Retrieve stock movement list
+// @Description Returns the stock movements matching the search request, newest first
+// @Tags StockMovement
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.StockMovementSearchReq true "Stock movement search details"
+// @Success 200 {object} models.SearchRp
+// @Router /api/stock-movement/list [post]
+func (mc *StockMovementController) GetMovementList(c *gin.Context) {
+	var req models.StockMovementSearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	movements, err := services.Service.StockMovementService.GetMovementList(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, movements)
+}
+
+// CreateMovement records a stock movement
+// @Summary Create stock movement
+// @Description Records a receipt, issue or adjustment and updates the product quantity
+// @Tags StockMovement
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.StockMovementCreateReq true "Stock movement details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
+// @Success 200 {object} models.StockMovement
+// @Router /api/stock-movement/create [post]
+func (mc *StockMovementController) CreateMovement(c *gin.Context) {
+	var req models.StockMovementCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	movement, err := services.Service.StockMovementService.CreateMovement(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, movement)
+}
@@ -0,0 +1,87 @@
+package controller
+
+import (
+	"net/http"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var StockMovementV2 = new(StockMovementV2Controller)
+
+type StockMovementV2Controller struct{}
+
+// ListMovements lists stock movements
+// @Summary List stock movements
+// @Description Returns the stock movements matching the query filters, newest first. Repeat a parameter to pass several values.
+// @Tags StockMovementV2
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param product_ids query []string false "Product IDs" collectionFormat(multi)
+// @Param movement_types query []string false "Movement types" collectionFormat(multi)
+// @Param date_from query string false "From (YYYY-MM-DD)"
+// @Param date_to query string false "To (YYYY-MM-DD)"
+// @Param limit query int false "Page size (default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} models.SearchRp
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/stock-movements [get]
+func (mc *StockMovementV2Controller) ListMovements(c *gin.Context) {
+	var req models.StockMovementSearchReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	movements, err := services.Service.StockMovementService.GetMovementList(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, movements)
+}
+
+// CreateMovement creates a stock movement
+// @Summary Create stock movement
+// @Description Records a receipt, issue or adjustment and updates the product quantity. Issues larger than the stock on hand get 422.
+// @Tags StockMovementV2
+// @Accept  json
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.StockMovementCreateReq true "Stock movement details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
+// @Success 201 {object} models.StockMovement
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/stock-movements [post]
+func (mc *StockMovementV2Controller) CreateMovement(c *gin.Context) {
+	var req models.StockMovementCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	movement, err := services.Service.StockMovementService.CreateMovement(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, movement)
+}
@@ -18,6 +18,8 @@ type SupplierController struct{}
 // @Tags Supplier
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.SupplierSearchReq true "Supplier search details"
 // @Success 200 {object} models.SearchRp
 // @Router /api/supplier/list [post]
@@ -46,7 +48,10 @@ func (pc *SupplierController) SearchSupplierList(c *gin.Context) {
 // @Tags Supplier
 // @Accept  json
 // @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
 // @Param request body models.SupplierCreateReq true "Supplier creation details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
 // @Success 201 {object} models.Supplier
 // @Router /api/supplier/create [post]
 func (pc *SupplierController) CreateSupplier(c *gin.Context) {
@@ -0,0 +1,85 @@
+package controller
+
+import (
+	"net/http"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+var SupplierV2 = new(SupplierV2Controller)
+
+type SupplierV2Controller struct{}
+
+// ListSuppliers lists suppliers
+// @Summary List suppliers
+// @Description Returns the suppliers matching the query filters
+// @Tags SupplierV2
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param supplier_name query string false "Supplier name"
+// @Param status query string false "Status"
+// @Param limit query int false "Page size (default 20)"
+// @Param offset query int false "Offset"
+// @Success 200 {object} models.SearchRp
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/suppliers [get]
+func (pc *SupplierV2Controller) ListSuppliers(c *gin.Context) {
+	var req models.SupplierSearchReq
+	if err := c.ShouldBindQuery(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	suppliers, err := services.Service.SupplierService.SearchSupplierList(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, suppliers)
+}
+
+// CreateSupplier creates a supplier
+// @Summary Create supplier
+// @Description Creates a supplier
+// @Tags SupplierV2
+// @Accept  json
+// @Produce  json
+// @Produce  application/problem+json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.SupplierCreateReq true "Supplier creation details"
+// @Param Idempotency-Key header string false "Retries with the same key and body return the original response"
+// @Success 201 {object} models.Supplier
+// @Failure 400 {object} response.ProblemDetails
+// @Failure 422 {object} response.ProblemDetails
+// @Router /api/v2/suppliers [post]
+func (pc *SupplierV2Controller) CreateSupplier(c *gin.Context) {
+	var req models.SupplierCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		badRequestProblem(c, err)
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		validationProblem(c, code)
+		return
+	}
+
+	supplier, err := services.Service.SupplierService.CreateSupplier(c, req)
+	if err != nil {
+		errorProblem(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, supplier)
+}
@@ -0,0 +1,229 @@
+package controller
+
+import (
+	"errors"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+var Webhook = new(WebhookController)
+
+type WebhookController struct{}
+
+// GetWebhookList lists the webhooks
+// @Summary Retrieve webhooks
+// @Description Returns the registered webhooks. Secrets are never returned.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Success 200 {array} models.Webhook
+// @Router /api/webhook/list [post]
+func (wc *WebhookController) GetWebhookList(c *gin.Context) {
+	webhooks, err := services.Service.WebhookService.GetWebhookList(c)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, webhooks)
+}
+
+// CreateWebhook registers a webhook
+// @Summary Create webhook
+// @Description Registers an http or https URL to receive the domain events of the given types as POST requests signed with the secret (at least 16 characters). See the README for the headers and how to verify the signature.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.WebhookCreateReq true "URL, secret and event types"
+// @Success 200 {object} models.Webhook
+// @Router /api/webhook/create [post]
+func (wc *WebhookController) CreateWebhook(c *gin.Context) {
+	var req models.WebhookCreateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	webhook, err := services.Service.WebhookService.CreateWebhook(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, webhook)
+}
+
+// UpdateWebhook replaces a webhook
+// @Summary Update webhook
+// @Description Replaces the URL, event types and enabled flag of a webhook, and its secret unless empty. Deliveries of a disabled webhook wait until it is enabled again.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.WebhookUpdateReq true "Webhook ID and details"
+// @Success 200 {object} models.Webhook
+// @Router /api/webhook/update [put]
+func (wc *WebhookController) UpdateWebhook(c *gin.Context) {
+	var req models.WebhookUpdateReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	webhook, err := services.Service.WebhookService.UpdateWebhook(c, req)
+	if err != nil {
+		failWebhook(c, err)
+		return
+	}
+	rs.SuccessResponse(c, webhook)
+}
+
+// DeleteWebhook deletes a webhook
+// @Summary Delete webhook
+// @Description Deletes a webhook with its delivery log, including pending and dead deliveries
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.WebhookIdReq true "Webhook ID"
+// @Success 200 {object} response.ResponseData
+// @Router /api/webhook/delete [post]
+func (wc *WebhookController) DeleteWebhook(c *gin.Context) {
+	var req models.WebhookIdReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	id, err := uuid.Parse(req.WebhookID)
+	if err != nil {
+		rs.FailResponseWithCode(c, rs.ErrInvalidWebhook)
+		return
+	}
+
+	if err := services.Service.WebhookService.DeleteWebhook(c, id); err != nil {
+		failWebhook(c, err)
+		return
+	}
+	rs.SuccessResponse(c, nil)
+}
+
+// GetDeliveryList lists webhook deliveries
+// @Summary Retrieve webhook delivery log
+// @Description Returns the webhook deliveries, newest first, with their status, attempts and the outcome of the last attempt. Filter by webhook and by status: pending, delivered or dead.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.WebhookDeliverySearchReq true "Webhook ID, status and paging"
+// @Success 200 {object} models.SearchRp
+// @Router /api/webhook/delivery/list [post]
+func (wc *WebhookController) GetDeliveryList(c *gin.Context) {
+	req, ok := deliverySearchBody(c)
+	if !ok {
+		return
+	}
+	deliveries, err := services.Service.WebhookService.GetDeliveryList(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, deliveries)
+}
+
+// GetDeadLetterList lists dead webhook deliveries
+// @Summary Retrieve webhook dead letters
+// @Description Returns the deliveries that failed every attempt, newest first. They are kept until redelivered or their webhook is deleted. status is ignored.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.WebhookDeliverySearchReq true "Webhook ID and paging"
+// @Success 200 {object} models.SearchRp
+// @Router /api/webhook/dead-letter/list [post]
+func (wc *WebhookController) GetDeadLetterList(c *gin.Context) {
+	req, ok := deliverySearchBody(c)
+	if !ok {
+		return
+	}
+	req.Status = models.DeliveryDead
+	deliveries, err := services.Service.WebhookService.GetDeliveryList(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, deliveries)
+}
+
+// Redeliver queues dead deliveries again
+// @Summary Redeliver dead webhook deliveries
+// @Description Queues dead deliveries to be sent again with their attempts reset. Deliveries that are not dead are skipped; the response has the number queued.
+// @Tags Webhook
+// @Accept  json
+// @Produce  json
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Param request body models.WebhookRedeliverReq true "Delivery IDs"
+// @Success 200 {object} models.WebhookRedeliverRp
+// @Router /api/webhook/dead-letter/redeliver [post]
+func (wc *WebhookController) Redeliver(c *gin.Context) {
+	var req models.WebhookRedeliverReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return
+	}
+
+	rp, err := services.Service.WebhookService.Redeliver(c, req)
+	if err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return
+	}
+	rs.SuccessResponse(c, rp)
+}
+
+func deliverySearchBody(c *gin.Context) (models.WebhookDeliverySearchReq, bool) {
+	var req models.WebhookDeliverySearchReq
+	if err := c.ShouldBindJSON(&req); err != nil {
+		rs.FailResponseWithMessage(c, err.Error())
+		return req, false
+	}
+	code := req.Validate()
+	if code != rs.OkCode {
+		rs.FailResponseWithCode(c, code)
+		return req, false
+	}
+	return req, true
+}
+
+func failWebhook(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrWebhookNotFound):
+		rs.FailResponseWithCode(c, rs.ErrInvalidWebhook)
+	default:
+		rs.FailResponseWithMessage(c, err.Error())
+	}
+}
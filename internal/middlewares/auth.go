@@ -0,0 +1,76 @@
+package middlewares
+
+import (
+	"errors"
+	"stock-management/internal/models"
+	"stock-management/internal/services"
+	rs "stock-management/pkgs/response"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// AuthMiddleware accepts either an API key in the X-API-Key header or a JWT access token
+// in the Authorization header, and stores the caller under models.AuthUserKey.
+func AuthMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user *models.AuthUser
+		var err error
+
+		if key := c.GetHeader("X-API-Key"); key != "" {
+			user, err = services.Service.AuthService.AuthenticateApiKey(c, key)
+		} else if token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer "); ok && token != "" {
+			user, err = services.Service.AuthService.ParseAccessToken(c, token)
+		} else {
+			rs.UnauthorizedResponse(c, rs.ErrUnauthorized)
+			return
+		}
+
+		if err != nil {
+			switch {
+			case errors.Is(err, services.ErrInvalidApiKey):
+				rs.UnauthorizedResponse(c, rs.ErrInvalidApiKey)
+			case errors.Is(err, services.ErrInvalidToken):
+				rs.UnauthorizedResponse(c, rs.ErrInvalidToken)
+			default:
+				getLogger(c).Error("Authenticate request error", zap.Error(err))
+				rs.UnauthorizedResponse(c, rs.ErrUnauthorized)
+			}
+			return
+		}
+
+		if err := services.Service.RbacService.LoadPermissions(c, user); err != nil {
+			getLogger(c).Error("Load permissions error", zap.Error(err))
+			rs.UnauthorizedResponse(c, rs.ErrUnauthorized)
+			return
+		}
+
+		c.Set(models.AuthUserKey, user)
+		c.Next()
+	}
+}
+
+// RequirePermission rejects the request with 403 unless the authenticated caller has the permission.
+// It must run after AuthMiddleware.
+func RequirePermission(permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value, ok := c.Get(models.AuthUserKey)
+		if !ok {
+			rs.UnauthorizedResponse(c, rs.ErrUnauthorized)
+			return
+		}
+		if !value.(*models.AuthUser).HasPermission(permission) {
+			rs.ForbiddenResponse(c)
+			return
+		}
+		c.Next()
+	}
+}
+
+func getLogger(c *gin.Context) *zap.Logger {
+	if logger, ok := c.Get("Logger"); ok {
+		return logger.(*zap.Logger)
+	}
+	return zap.NewNop()
+}
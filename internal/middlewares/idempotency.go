@@ -0,0 +1,155 @@
+package middlewares
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"stock-management/global"
+	"stock-management/internal/models"
+	rs "stock-management/pkgs/response"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"go.uber.org/zap"
+)
+
+const (
+	maxIdempotencyKeyLength = 255
+	defaultIdempotencyTTL   = 24 * time.Hour
+)
+
+// Idempotency honors the Idempotency-Key header: the first successful response for a key is
+// stored in Redis and replayed for retries with the same key and body until the key expires.
+// Failed responses are not stored, so the client can retry them.
+func Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(models.IdempotencyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			rs.FailResponseWithStatus(c, http.StatusBadRequest, rs.ErrInvalidIdempotencyKey)
+			return
+		}
+
+		body, err := c.GetRawData()
+		if err != nil {
+			rs.FailResponseWithStatus(c, http.StatusBadRequest, rs.ErrBadRequest)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+		sum := sha256.Sum256(append([]byte(c.Request.Method+" "+c.FullPath()+"\n"), body...))
+		fingerprint := hex.EncodeToString(sum[:])
+
+		var userID interface{} = "anonymous"
+		if user, ok := c.Get(models.AuthUserKey); ok {
+			userID = user.(*models.AuthUser).UserID
+		}
+		cacheKey := fmt.Sprintf(models.IdempotencyKey, userID, c.FullPath(), key)
+		ttl := time.Duration(global.Config.Cache.IdempotencyTTL) * time.Second
+		if ttl <= 0 {
+			ttl = defaultIdempotencyTTL
+		}
+
+		pending, _ := json.Marshal(models.IdempotencyRecord{Fingerprint: fingerprint})
+		acquired, err := global.Rdb.SetNX(c, cacheKey, pending, ttl).Result()
+		if err != nil {
+			getLogger(c).Error("Reserve idempotency key error", zap.Error(err))
+			rs.FailResponseWithStatus(c, http.StatusServiceUnavailable, rs.ErrInternal)
+			return
+		}
+		if !acquired {
+			replayIdempotent(c, cacheKey, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = recorder
+		c.Next()
+
+		if recorder.Status() >= http.StatusInternalServerError || !isSuccess(c, recorder) {
+			if err := global.Rdb.Del(c, cacheKey).Err(); err != nil {
+				getLogger(c).Error("Release idempotency key error", zap.Error(err))
+			}
+			return
+		}
+
+		record, _ := json.Marshal(models.IdempotencyRecord{
+			Fingerprint: fingerprint,
+			Status:      recorder.Status(),
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err := global.Rdb.Set(c, cacheKey, record, ttl).Err(); err != nil {
+			getLogger(c).Error("Store idempotent response error", zap.Error(err))
+		}
+	}
+}
+
+func replayIdempotent(c *gin.Context, cacheKey, fingerprint string) {
+	raw, err := global.Rdb.Get(c, cacheKey).Bytes()
+	if err == redis.Nil {
+		// Released by a failed first request in the meantime; the client can retry.
+		rs.FailResponseWithStatus(c, http.StatusConflict, rs.ErrIdempotencyInProgress)
+		return
+	}
+	if err != nil {
+		getLogger(c).Error("Get idempotency key error", zap.Error(err))
+		rs.FailResponseWithStatus(c, http.StatusServiceUnavailable, rs.ErrInternal)
+		return
+	}
+
+	var record models.IdempotencyRecord
+	if err := json.Unmarshal(raw, &record); err != nil {
+		getLogger(c).Error("Decode idempotency record error", zap.Error(err))
+		rs.FailResponseWithStatus(c, http.StatusServiceUnavailable, rs.ErrInternal)
+		return
+	}
+	if record.Fingerprint != fingerprint {
+		rs.FailResponseWithStatus(c, http.StatusUnprocessableEntity, rs.ErrIdempotencyKeyReused)
+		return
+	}
+	if record.Status == 0 {
+		rs.FailResponseWithStatus(c, http.StatusConflict, rs.ErrIdempotencyInProgress)
+		return
+	}
+
+	c.Header("Idempotent-Replayed", "true")
+	c.Data(record.Status, record.ContentType, record.Body)
+	c.Abort()
+}
+
+// isSuccess reports whether the response should be stored. v1 routes answer 200 with an error
+// code in the body, v2 routes use the HTTP status.
+func isSuccess(c *gin.Context, recorder *responseRecorder) bool {
+	if rs.ProblemDetailsEnabled(c) {
+		return recorder.Status() < http.StatusBadRequest
+	}
+	var data rs.ResponseData
+	if err := json.Unmarshal(recorder.body.Bytes(), &data); err != nil {
+		return false
+	}
+	return data.Code == rs.OkCode
+}
+
+// responseRecorder keeps a copy of the response body while writing it to the client.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
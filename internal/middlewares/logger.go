@@ -3,14 +3,21 @@ package middlewares
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"io"
 	"stock-management/global"
+	"stock-management/internal/models"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
 )
 
+// redactedFields are the request body fields never written to the log: passwords on login and
+// user creation, refresh tokens and webhook secrets.
+var redactedFields = []string{"password", "refresh_token", "secret"}
+
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		traceId := c.GetHeader("X-Trace-ID")
@@ -19,7 +26,10 @@ func RequestLogger() gin.HandlerFunc {
 		}
 
 		ctx := c.Request.Context()
-		ctx = context.WithValue(ctx, "TraceID", traceId)
+		ctx = context.WithValue(ctx, models.TraceIDKey, traceId)
+		c.Request = c.Request.WithContext(ctx)
+		c.Set(models.TraceIDKey, traceId)
+		c.Header("X-Trace-ID", traceId)
 
 		logger := global.Logger.AddTraceID(ctx)
 		c.Set("Logger", logger)
@@ -30,9 +40,36 @@ func RequestLogger() gin.HandlerFunc {
 			zap.String("method", c.Request.Method),
 			zap.String("url", c.Request.URL.String()),
 			zap.String("query_params", c.Request.URL.RawQuery),
-			zap.String("body", string(rawData)),
+			zap.String("body", redactBody(rawData)),
 			zap.String("client_ip", c.ClientIP()))
 		c.Request.Body = io.NopCloser(bytes.NewBuffer(rawData))
 		c.Next()
 	}
 }
+
+// redactBody returns a JSON object body with the values of redactedFields replaced. Other
+// bodies are returned as they are.
+func redactBody(rawData []byte) string {
+	var fields map[string]json.RawMessage
+	if json.Unmarshal(rawData, &fields) != nil {
+		return string(rawData)
+	}
+	redacted := false
+	for key := range fields {
+		// Bound case-insensitively, like encoding/json does.
+		for _, name := range redactedFields {
+			if strings.EqualFold(key, name) {
+				fields[key] = json.RawMessage(`"[REDACTED]"`)
+				redacted = true
+			}
+		}
+	}
+	if !redacted {
+		return string(rawData)
+	}
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
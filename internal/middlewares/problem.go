@@ -0,0 +1,16 @@
+package middlewares
+
+import (
+	rs "stock-management/pkgs/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProblemDetails makes the errors of the following middlewares and handlers RFC 7807
+// problem details (application/problem+json) instead of the response envelope.
+func ProblemDetails() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		rs.EnableProblemDetails(c)
+		c.Next()
+	}
+}
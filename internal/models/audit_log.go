@@ -0,0 +1,177 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"stock-management/pkgs/response"
+	"stock-management/pkgs/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is an append-only record of a write on an entity. Rows are never updated or deleted.
+type AuditLog struct {
+	AuditID    uuid.UUID    `gorm:"primaryKey;type:uuid;column:audit_id" json:"audit_id"`
+	EntityType AuditEntity  `gorm:"not null;index:idx_audit_entity;column:entity_type" json:"entity_type"`
+	EntityID   uuid.UUID    `gorm:"not null;type:uuid;index:idx_audit_entity;column:entity_id" json:"entity_id"`
+	Action     AuditAction  `gorm:"not null;column:action" json:"action"`
+	ActorID    *uuid.UUID   `gorm:"type:uuid;index;column:actor_id" json:"actor_id"`
+	ActorName  string       `gorm:"not null;column:actor_name" json:"actor_name"`
+	TraceID    string       `gorm:"not null;column:trace_id" json:"trace_id"`
+	Changes    AuditChanges `gorm:"not null;type:jsonb;column:changes" json:"changes"`
+	CreatedAt  time.Time    `gorm:"not null;index;column:created_at" json:"created_at"`
+}
+
+func (a *AuditLog) TableName() string {
+	return "audit_log"
+}
+
+type AuditEntity string
+
+const (
+	AuditEntityProduct  AuditEntity = "product"
+	AuditEntitySupplier AuditEntity = "supplier"
+	AuditEntityCategory AuditEntity = "product_category"
+)
+
+type AuditAction string
+
+const (
+	AuditCreate AuditAction = "create"
+	AuditUpdate AuditAction = "update"
+	AuditDelete AuditAction = "delete"
+)
+
+// AuditActorSystem is recorded when a write is not made on behalf of an authenticated user.
+const AuditActorSystem string = "system"
+
+// AuditChange holds the value of a field before and after a write.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditChanges maps a field name to its change.
+type AuditChanges map[string]AuditChange
+
+func (c AuditChanges) Value() (driver.Value, error) {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func (c *AuditChanges) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	case nil:
+		*c = nil
+		return nil
+	}
+	return errors.New("unsupported audit changes value")
+}
+
+// DiffAudit compares the JSON fields of before and after. Nested objects such as preloaded
+// relations are ignored; a nil before or after yields the full entity as created or deleted.
+func DiffAudit(before, after interface{}) (AuditChanges, error) {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(AuditChanges)
+	for field, value := range afterFields {
+		prev, ok := beforeFields[field]
+		if !ok || !jsonEqual(prev, value) {
+			changes[field] = AuditChange{Before: prev, After: value}
+		}
+	}
+	for field, value := range beforeFields {
+		if _, ok := afterFields[field]; !ok {
+			changes[field] = AuditChange{Before: value}
+		}
+	}
+	return changes, nil
+}
+
+func auditFields(entity interface{}) (map[string]interface{}, error) {
+	fields := make(map[string]interface{})
+	if entity == nil {
+		return fields, nil
+	}
+	b, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	for field, value := range fields {
+		if _, ok := value.(map[string]interface{}); ok {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+func jsonEqual(a, b interface{}) bool {
+	ab, _ := json.Marshal(a)
+	bb, _ := json.Marshal(b)
+	return string(ab) == string(bb)
+}
+
+// AuditMaxLimit is the most audit log entries returned per page.
+const AuditMaxLimit = 100
+
+type AuditSearchReq struct {
+	EntityType AuditEntity `json:"entity_type,omitempty"`
+	EntityID   string      `json:"entity_id,omitempty"`
+	ActorID    string      `json:"actor_id,omitempty"`
+	ActorName  string      `json:"actor_name,omitempty"`
+	DateFrom   string      `json:"date_from,omitempty"`
+	DateTo     string      `json:"date_to,omitempty"`
+	Pagination
+}
+
+func (req *AuditSearchReq) Validate() response.RespCode {
+	if req.EntityID != "" && !utils.IsValidUUID(req.EntityID) {
+		return response.ErrInvalidEntity
+	}
+	if req.ActorID != "" && !utils.IsValidUUID(req.ActorID) {
+		return response.ErrInvalidUser
+	}
+	if req.DateFrom != "" {
+		if err := validateDateFormat(req.DateFrom); err != nil {
+			return response.ErrInvalidDate
+		}
+	}
+	if req.DateTo != "" {
+		if err := validateDateFormat(req.DateTo); err != nil {
+			return response.ErrInvalidDate
+		}
+	}
+	// Both are "2006-01-02", so they compare as strings.
+	if req.DateFrom != "" && req.DateTo != "" && req.DateFrom > req.DateTo {
+		return response.ErrInvalidDate
+	}
+	// A negative limit would be no limit at all.
+	if req.Limit < 0 {
+		return response.ErrInvalidLimit
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	} else if req.Limit > AuditMaxLimit {
+		req.Limit = AuditMaxLimit
+	}
+	return response.OkCode
+}
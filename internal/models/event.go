@@ -0,0 +1,229 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"regexp"
+	"stock-management/pkgs/response"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEventGroupNotFound = errors.New("event consumer group not found")
+	ErrEventGroupExists   = errors.New("event consumer group already exists")
+)
+
+// DomainEventVersion is the version of the event schema in docs/event_schema.json. It changes
+// only when a field is removed or changes meaning.
+const DomainEventVersion = 1
+
+// DomainEvent is an entry of the event stream.
+type DomainEvent struct {
+	// EventID is unique and the same when an event is published again: consumers deduplicate on it.
+	EventID    string          `json:"event_id"`
+	Type       DomainEventType `json:"type"`
+	Version    int             `json:"version"`
+	EntityType string          `json:"entity_type"`
+	EntityID   uuid.UUID       `json:"entity_id"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       DomainEventData `json:"data"`
+}
+
+// DomainEventData holds the entity before and after created, updated and deleted events, and the
+// field before and after quantity and status changes. Delta is set on quantity changes.
+type DomainEventData struct {
+	Before json.RawMessage `json:"before,omitempty" swaggertype:"object"`
+	After  json.RawMessage `json:"after,omitempty" swaggertype:"object"`
+	Delta  *int            `json:"delta,omitempty"`
+}
+
+type DomainEventType string
+
+const (
+	EventProductCreated         DomainEventType = "product.created"
+	EventProductUpdated         DomainEventType = "product.updated"
+	EventProductDeleted         DomainEventType = "product.deleted"
+	EventProductQuantityChanged DomainEventType = "product.quantity_changed"
+	EventProductStatusChanged   DomainEventType = "product.status_changed"
+	EventStockMovementCreated   DomainEventType = "stock.movement_created"
+	EventSupplierCreated        DomainEventType = "supplier.created"
+	EventSupplierUpdated        DomainEventType = "supplier.updated"
+	EventSupplierStatusChanged  DomainEventType = "supplier.status_changed"
+	EventCategoryCreated        DomainEventType = "category.created"
+	EventCategoryUpdated        DomainEventType = "category.updated"
+	EventCategoryStatusChanged  DomainEventType = "category.status_changed"
+)
+
+var DomainEventTypes = []DomainEventType{
+	EventProductCreated, EventProductUpdated, EventProductDeleted,
+	EventProductQuantityChanged, EventProductStatusChanged,
+	EventStockMovementCreated,
+	EventSupplierCreated, EventSupplierUpdated, EventSupplierStatusChanged,
+	EventCategoryCreated, EventCategoryUpdated, EventCategoryStatusChanged,
+}
+
+func (t DomainEventType) IsValid() bool {
+	for _, eventType := range DomainEventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// StreamEvent is an event with its position in the stream.
+type StreamEvent struct {
+	StreamID string      `json:"stream_id"`
+	Event    DomainEvent `json:"event"`
+}
+
+// EventListRp is a page of the stream. Next is the position to read the following page from,
+// empty at the end of the stream.
+type EventListRp struct {
+	Events []StreamEvent `json:"events"`
+	Next   string        `json:"next"`
+}
+
+// EventGroup is a consumer group of the stream. Lag is the number of events not yet delivered to
+// the group; Redis reports it from version 7.
+type EventGroup struct {
+	Name            string `json:"name"`
+	Consumers       int64  `json:"consumers"`
+	Pending         int64  `json:"pending"`
+	LastDeliveredID string `json:"last_delivered_id"`
+	Lag             int64  `json:"lag"`
+}
+
+var (
+	streamIDRegex  = regexp.MustCompile(`^\d+(-\d+)?$`)
+	groupNameRegex = regexp.MustCompile(`^[A-Za-z0-9_.:-]{1,64}$`)
+)
+
+// ParseStreamPosition converts a position given as a stream ID (1718000000000-0), an RFC 3339
+// time or "$", the end of the stream, to a stream ID.
+func ParseStreamPosition(position string) (string, bool) {
+	switch {
+	case position == "$" || streamIDRegex.MatchString(position):
+		return position, true
+	default:
+		t, err := time.Parse(time.RFC3339, position)
+		if err != nil {
+			return "", false
+		}
+		return strconv.FormatInt(t.UnixMilli(), 10) + "-0", true
+	}
+}
+
+type EventSearchReq struct {
+	// From is the position to read from, included: a stream ID or an RFC 3339 time. Empty
+	// reads from the oldest retained event.
+	From  string            `json:"from,omitempty"`
+	Types []DomainEventType `json:"types,omitempty"`
+	Limit int               `json:"limit,omitempty"`
+}
+
+func (req *EventSearchReq) Validate() response.RespCode {
+	if req.From == "" {
+		req.From = "-"
+	} else {
+		from, ok := ParseStreamPosition(req.From)
+		if !ok || from == "$" {
+			return response.ErrInvalidEventPosition
+		}
+		req.From = from
+	}
+	for _, eventType := range req.Types {
+		if !eventType.IsValid() {
+			return response.ErrInvalidEventType
+		}
+	}
+	if req.Limit <= 0 {
+		req.Limit = 100
+	} else if req.Limit > 1000 {
+		req.Limit = 1000
+	}
+	return response.OkCode
+}
+
+type EventGroupCreateReq struct {
+	Name string `json:"name" binding:"required"`
+	// From is where the group starts: "$" (default) for new events only, "0" for every retained
+	// event, a stream ID or an RFC 3339 time.
+	From string `json:"from,omitempty"`
+}
+
+func (req *EventGroupCreateReq) Validate() response.RespCode {
+	req.Name = strings.TrimSpace(req.Name)
+	if !groupNameRegex.MatchString(req.Name) {
+		return response.ErrInvalidEventGroup
+	}
+	return validateGroupPosition(&req.From)
+}
+
+// EventReplayReq moves a consumer group back so that its consumers read the events from From
+// again.
+type EventReplayReq struct {
+	Group string `json:"group" binding:"required"`
+	From  string `json:"from" binding:"required"`
+}
+
+func (req *EventReplayReq) Validate() response.RespCode {
+	if !groupNameRegex.MatchString(req.Group) {
+		return response.ErrInvalidEventGroup
+	}
+	return validateGroupPosition(&req.From)
+}
+
+func validateGroupPosition(from *string) response.RespCode {
+	if *from == "" {
+		*from = "$"
+		return response.OkCode
+	}
+	position, ok := ParseStreamPosition(*from)
+	if !ok {
+		return response.ErrInvalidEventPosition
+	}
+	// A group delivers the events after its last delivered ID, so start just before From.
+	if position != "$" {
+		position = streamIDBefore(position)
+	}
+	*from = position
+	return response.OkCode
+}
+
+// streamIDBefore returns the ID preceding id, or "0" for the start of the stream.
+func streamIDBefore(id string) string {
+	ms, seq, found := strings.Cut(id, "-")
+	if !found {
+		seq = "0"
+	}
+	msValue, _ := strconv.ParseUint(ms, 10, 64)
+	seqValue, _ := strconv.ParseUint(seq, 10, 64)
+	switch {
+	case seqValue > 0:
+		return strconv.FormatUint(msValue, 10) + "-" + strconv.FormatUint(seqValue-1, 10)
+	case msValue > 0:
+		return strconv.FormatUint(msValue-1, 10) + "-18446744073709551615"
+	default:
+		return "0"
+	}
+}
+
+// NextStreamID returns the ID following id.
+func NextStreamID(id string) string {
+	ms, seq, found := strings.Cut(id, "-")
+	if !found {
+		return id + "-1"
+	}
+	seqValue, _ := strconv.ParseUint(seq, 10, 64)
+	if seqValue == math.MaxUint64 {
+		msValue, _ := strconv.ParseUint(ms, 10, 64)
+		return strconv.FormatUint(msValue+1, 10) + "-0"
+	}
+	return ms + "-" + strconv.FormatUint(seqValue+1, 10)
+}
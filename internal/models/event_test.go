@@ -0,0 +1,105 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"testing"
+)
+
+func TestParseStreamPosition(t *testing.T) {
+	tests := []struct {
+		position string
+		want     string
+		wantOk   bool
+	}{
+		{"$", "$", true},
+		{"1718000000000-0", "1718000000000-0", true},
+		{"1718000000000-12", "1718000000000-12", true},
+		{"1718000000000", "1718000000000", true},
+		{"0", "0", true},
+		{"2024-06-10T06:13:20Z", "1718000000000-0", true},
+		{"2024-06-10T13:13:20+07:00", "1718000000000-0", true},
+		{"1970-01-01T00:00:00Z", "0-0", true},
+		{"", "", false},
+		{"-", "", false},
+		{"+", "", false},
+		{"1718000000000-", "", false},
+		{"-5", "", false},
+		{"2024-06-10", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.position, func(t *testing.T) {
+			got, ok := ParseStreamPosition(tt.position)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("ParseStreamPosition(%q) = %q, %v, want %q, %v", tt.position, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestStreamIDBefore(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"previous sequence", "1718000000000-5", "1718000000000-4"},
+		{"seq 0 goes to the previous millisecond", "1718000000000-0", "1717999999999-18446744073709551615"},
+		{"id without sequence is seq 0", "1718000000000", "1717999999999-18446744073709551615"},
+		{"ms 0", "0-7", "0-6"},
+		{"ms 0 seq 1", "0-1", "0-0"},
+		{"ms 0 seq 0 is the start", "0-0", "0"},
+		{"start", "0", "0"},
+		{"max sequence", "1-18446744073709551615", "1-18446744073709551614"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := streamIDBefore(tt.id); got != tt.want {
+				t.Errorf("streamIDBefore(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextStreamID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   string
+		want string
+	}{
+		{"next sequence", "1718000000000-5", "1718000000000-6"},
+		{"seq 0", "1718000000000-0", "1718000000000-1"},
+		{"ms 0", "0-0", "0-1"},
+		{"id without sequence is seq 0", "1718000000000", "1718000000000-1"},
+		{"max sequence goes to the next millisecond", "1718000000000-18446744073709551615", "1718000000001-0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NextStreamID(tt.id); got != tt.want {
+				t.Errorf("NextStreamID(%q) = %q, want %q", tt.id, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateGroupPosition(t *testing.T) {
+	tests := []struct {
+		from     string
+		want     string
+		wantCode response.RespCode
+	}{
+		{"", "$", response.OkCode},
+		{"$", "$", response.OkCode},
+		{"0", "0", response.OkCode},
+		{"1718000000000-3", "1718000000000-2", response.OkCode},
+		{"2024-06-10T06:13:20Z", "1717999999999-18446744073709551615", response.OkCode},
+		{"yesterday", "yesterday", response.ErrInvalidEventPosition},
+	}
+	for _, tt := range tests {
+		t.Run(tt.from, func(t *testing.T) {
+			from := tt.from
+			if code := validateGroupPosition(&from); code != tt.wantCode || from != tt.want {
+				t.Errorf("validateGroupPosition(%q) = %q, %v, want %q, %v", tt.from, from, code, tt.want, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package models
+
+const (
+	IdempotencyHeader string = "Idempotency-Key"
+	// IdempotencyKey is scoped by user and route, so keys of different clients never collide.
+	IdempotencyKey string = "idempotency:%v:%v:%v"
+)
+
+// IdempotencyRecord is the stored outcome of a request made with an Idempotency-Key.
+// Status is 0 while the first request is still running.
+type IdempotencyRecord struct {
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
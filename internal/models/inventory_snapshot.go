@@ -0,0 +1,105 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InventorySnapshot struct {
+	SnapshotID        uuid.UUID `gorm:"primaryKey;type:uuid;column:snapshot_id" json:"snapshot_id"`
+	SnapshotDate      time.Time `gorm:"not null;type:date;column:snapshot_date;uniqueIndex:idx_inventory_snapshot_date_product" json:"snapshot_date"`
+	ProductID         uuid.UUID `gorm:"not null;type:uuid;column:product_id;uniqueIndex:idx_inventory_snapshot_date_product" json:"product_id"`
+	ProductCategoryID uuid.UUID `gorm:"not null;type:uuid;column:product_category_id" json:"product_category_id"`
+	SupplierID        uuid.UUID `gorm:"not null;type:uuid;column:supplier_id" json:"supplier_id"`
+	Quantity          int       `gorm:"not null;column:quantity" json:"quantity"`
+	Price             int       `gorm:"not null;column:price" json:"price"`
+	Value             int64     `gorm:"not null;column:value" json:"value"`
+	CreatedAt         time.Time `gorm:"not null;column:created_at" json:"created_at"`
+}
+
+func (s *InventorySnapshot) TableName() string {
+	return "inventory_snapshot"
+}
+
+type TimeseriesBucket string
+
+const (
+	TimeseriesBucketDay   TimeseriesBucket = "day"
+	TimeseriesBucketWeek  TimeseriesBucket = "week"
+	TimeseriesBucketMonth TimeseriesBucket = "month"
+)
+
+type TimeseriesReq struct {
+	DateFrom string            `form:"date_from" json:"date_from"`
+	DateTo   string            `form:"date_to" json:"date_to"`
+	Bucket   TimeseriesBucket  `form:"bucket" json:"bucket"`
+	GroupBy  StatisticsGroupBy `form:"group_by" json:"group_by"`
+	IDs      []string          `form:"ids" json:"ids,omitempty"`
+
+	//convert
+	From  time.Time   `form:"-" json:"-"`
+	To    time.Time   `form:"-" json:"-"`
+	UUIDs []uuid.UUID `form:"-" json:"-"`
+}
+
+func (req *TimeseriesReq) Validate() response.RespCode {
+	var err error
+	req.From, err = time.Parse(dateFormat, req.DateFrom)
+	if err != nil {
+		return response.ErrInvalidDate
+	}
+	req.To, err = time.Parse(dateFormat, req.DateTo)
+	if err != nil {
+		return response.ErrInvalidDate
+	}
+	if req.From.After(req.To) {
+		return response.ErrInvalidDate
+	}
+
+	if req.Bucket == "" {
+		req.Bucket = TimeseriesBucketDay
+	}
+	if req.Bucket != TimeseriesBucketDay && req.Bucket != TimeseriesBucketWeek && req.Bucket != TimeseriesBucketMonth {
+		return response.ErrInvalidBucket
+	}
+
+	if req.GroupBy == "" {
+		req.GroupBy = StatisticsGroupByCategory
+	}
+	if !req.GroupBy.IsValid() {
+		return response.ErrInvalidGroupBy
+	}
+
+	var ok bool
+	if req.UUIDs, ok = parseUUIDs(req.IDs); !ok {
+		return req.GroupBy.invalidIDCode()
+	}
+	return response.OkCode
+}
+
+type TimeseriesRow struct {
+	GroupID  uuid.UUID
+	Bucket   time.Time
+	Quantity int64
+	Value    int64
+}
+
+type TimeseriesPoint struct {
+	Date     string `json:"date"`
+	Quantity int64  `json:"quantity"`
+	Value    int64  `json:"value"`
+}
+
+type TimeseriesSeries struct {
+	ID     string            `json:"id"`
+	Name   string            `json:"name"`
+	Points []TimeseriesPoint `json:"points"`
+}
+
+type TimeseriesRp struct {
+	Bucket  TimeseriesBucket   `json:"bucket"`
+	GroupBy StatisticsGroupBy  `json:"group_by"`
+	Series  []TimeseriesSeries `json:"series"`
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"stock-management/pkgs/utils"
+
+	"github.com/google/uuid"
+)
+
+type LabelSymbology string
+
+const (
+	// LabelSymbologyAuto uses EAN-13 for products with a barcode and Code128 of the reference otherwise.
+	LabelSymbologyAuto    LabelSymbology = "auto"
+	LabelSymbologyCode128 LabelSymbology = "code128"
+	LabelSymbologyEAN13   LabelSymbology = "ean13"
+
+	maxLabelProducts int = 500
+	maxLabelCopies   int = 100
+)
+
+type ProductLabelReq struct {
+	ProductIDs []string       `json:"product_ids"`
+	Size       string         `json:"size,omitempty"`
+	Symbology  LabelSymbology `json:"symbology,omitempty"`
+	Copies     int            `json:"copies,omitempty"`
+
+	//convert
+	ProductUUIDs []uuid.UUID `json:"-"`
+}
+
+func (req *ProductLabelReq) Validate() response.RespCode {
+	if len(req.ProductIDs) == 0 || len(req.ProductIDs) > maxLabelProducts {
+		return response.ErrInvalidProduct
+	}
+	for _, id := range req.ProductIDs {
+		if !utils.IsValidUUID(id) {
+			return response.ErrInvalidProduct
+		}
+	}
+	req.ProductUUIDs = getUUIDs(req.ProductIDs)
+
+	if req.Symbology == "" {
+		req.Symbology = LabelSymbologyAuto
+	}
+	if req.Symbology != LabelSymbologyAuto && req.Symbology != LabelSymbologyCode128 && req.Symbology != LabelSymbologyEAN13 {
+		return response.ErrInvalidSymbology
+	}
+
+	if req.Copies == 0 {
+		req.Copies = 1
+	}
+	if req.Copies < 0 || req.Copies > maxLabelCopies {
+		return response.ErrInvalidQuantity
+	}
+	return response.OkCode
+}
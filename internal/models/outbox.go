@@ -0,0 +1,69 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OutboxEvent is a write recorded in the transaction that made it. The outbox relay applies
+// it to the Redis counters and publishes it once the transaction has committed, so a rolled
+// back write has no effect outside the database.
+type OutboxEvent struct {
+	EventID     int64           `gorm:"primaryKey;autoIncrement;column:event_id" json:"event_id"`
+	EventType   OutboxEventType `gorm:"not null;column:event_type" json:"event_type"`
+	EntityID    uuid.UUID       `gorm:"not null;type:uuid;column:entity_id" json:"entity_id"`
+	Change      OutboxChange    `gorm:"not null;type:jsonb;serializer:json;column:change" json:"change"`
+	CreatedAt   time.Time       `gorm:"not null;column:created_at" json:"created_at"`
+	PublishedAt *time.Time      `gorm:"index:idx_outbox_unpublished,where:published_at IS NULL;column:published_at" json:"published_at"`
+	Attempts    int             `gorm:"not null;default:0;column:attempts" json:"attempts"`
+	LastError   string          `gorm:"not null;default:'';column:last_error" json:"last_error"`
+	// FailedAt is set when the relay gave up on the event. It is skipped and kept until
+	// requeued by clearing it.
+	FailedAt *time.Time `gorm:"column:failed_at" json:"failed_at"`
+}
+
+func (e *OutboxEvent) TableName() string {
+	return "outbox_event"
+}
+
+type OutboxEventType string
+
+const (
+	OutboxProductCreated OutboxEventType = "product.created"
+	OutboxProductUpdated OutboxEventType = "product.updated"
+	OutboxProductDeleted OutboxEventType = "product.deleted"
+	// OutboxMovementCreated follows the product.updated event of the quantity it changed.
+	OutboxMovementCreated OutboxEventType = "stock_movement.created"
+	OutboxSupplierCreated OutboxEventType = "supplier.created"
+	OutboxCategoryCreated OutboxEventType = "category.created"
+)
+
+// OutboxChange holds the entity before and after the write. Before is null on creation and
+// After on deletion.
+type OutboxChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// Decode unmarshals the states of the change into before and after. A null state leaves its
+// target unchanged.
+func (c OutboxChange) Decode(before, after interface{}) error {
+	if len(c.Before) > 0 && string(c.Before) != "null" {
+		if err := json.Unmarshal(c.Before, before); err != nil {
+			return err
+		}
+	}
+	if len(c.After) > 0 && string(c.After) != "null" {
+		return json.Unmarshal(c.After, after)
+	}
+	return nil
+}
+
+// OutboxAppliedKey marks an event as applied to the Redis counters, so that an event relayed
+// again after a crash is not counted twice.
+const OutboxAppliedKey string = "outbox_applied:%v"
+
+// OutboxStreamedKey marks an event as appended to the event stream, like OutboxAppliedKey.
+const OutboxStreamedKey string = "outbox_streamed:%v"
@@ -0,0 +1,115 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"stock-management/pkgs/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type ForecastMethod string
+
+const (
+	ForecastMovingAverage        ForecastMethod = "moving_average"
+	ForecastExponentialSmoothing ForecastMethod = "exponential_smoothing"
+)
+
+type PlanningSuggestionReq struct {
+	Method      ForecastMethod `form:"method" json:"method"`
+	HistoryDays int            `form:"history_days" json:"history_days"`
+	Window      int            `form:"window" json:"window"`
+	Alpha       float64        `form:"alpha" json:"alpha"`
+	ReviewDays  int            `form:"review_days" json:"review_days"`
+	SafetyDays  int            `form:"safety_days" json:"safety_days"`
+	SupplierIDs []string       `form:"supplier_ids" json:"supplier_ids,omitempty"`
+
+	//convert
+	SupplierUUIDs []uuid.UUID `form:"-" json:"-"`
+}
+
+func (req *PlanningSuggestionReq) Validate() response.RespCode {
+	if req.Method == "" {
+		req.Method = ForecastMovingAverage
+	}
+	if req.Method != ForecastMovingAverage && req.Method != ForecastExponentialSmoothing {
+		return response.ErrInvalidForecastMethod
+	}
+	if req.HistoryDays == 0 {
+		req.HistoryDays = 90
+	}
+	if req.Window == 0 {
+		req.Window = 30
+	}
+	if req.Alpha == 0 {
+		req.Alpha = 0.3
+	}
+	if req.ReviewDays == 0 {
+		req.ReviewDays = 7
+	}
+	if req.SafetyDays == 0 {
+		req.SafetyDays = 7
+	}
+	if req.HistoryDays < 0 || req.Window < 0 || req.Alpha < 0 || req.Alpha > 1 || req.ReviewDays < 0 || req.SafetyDays < 0 {
+		return response.ErrInvalidForecastParams
+	}
+	var ok bool
+	if req.SupplierUUIDs, ok = parseUUIDs(req.SupplierIDs); !ok {
+		return response.ErrInvalidSupplier
+	}
+	return response.OkCode
+}
+
+type DailyIssueRow struct {
+	ProductID uuid.UUID
+	Day       time.Time
+	Quantity  int64
+}
+
+type PlanningSuggestion struct {
+	ProductID           uuid.UUID       `json:"product_id"`
+	ProductName         string          `json:"product_name"`
+	ProductReference    string          `json:"product_reference"`
+	SupplierID          uuid.UUID       `json:"supplier_id"`
+	SupplierName        string          `json:"supplier_name"`
+	LeadTimeDays        int             `json:"lead_time_days"`
+	OnHand              int             `json:"on_hand"`
+	OnOrder             int64           `json:"on_order"`
+	ForecastDailyDemand decimal.Decimal `json:"forecast_daily_demand"`
+	DemandOverLeadTime  decimal.Decimal `json:"demand_over_lead_time"`
+	SafetyStock         decimal.Decimal `json:"safety_stock"`
+	SuggestedQuantity   int             `json:"suggested_quantity"`
+	UnitPrice           int             `json:"unit_price"`
+}
+
+type PlanningSuggestionRp struct {
+	Method      ForecastMethod       `json:"method"`
+	HistoryDays int                  `json:"history_days"`
+	Data        []PlanningSuggestion `json:"data"`
+}
+
+type PlanningConvertItem struct {
+	ProductID string `json:"product_id"`
+	Quantity  int    `json:"quantity"`
+}
+
+type PlanningConvertReq struct {
+	Items []PlanningConvertItem `json:"items"`
+	Note  string                `json:"note"`
+}
+
+func (req *PlanningConvertReq) Validate() response.RespCode {
+	if len(req.Items) == 0 {
+		return response.ErrInvalidProduct
+	}
+	for _, item := range req.Items {
+		if item.ProductID == "" || !utils.IsValidUUID(item.ProductID) {
+			return response.ErrInvalidProduct
+		}
+		if item.Quantity <= 0 {
+			return response.ErrInvalidQuantity
+		}
+	}
+	return response.OkCode
+}
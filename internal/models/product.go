@@ -1,19 +1,44 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"stock-management/pkgs/response"
 	"stock-management/pkgs/utils"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrVersionConflict is returned when an update carries a version other than the stored one.
+	ErrVersionConflict    = errors.New("version conflict")
+	ErrDuplicateReference = errors.New("product reference already exists")
+	ErrDuplicateBarcode   = errors.New("barcode already exists")
+	ErrProductNotFound    = errors.New("invalid product")
+	ErrSupplierNotFound   = errors.New("invalid supplier")
+	ErrCategoryNotFound   = errors.New("invalid product category")
+)
+
+// ValidationError lists request fields that failed a check against the database, like a
+// category that doesn't exist.
+type ValidationError struct {
+	Fields response.FieldErrors
+}
+
+func (e *ValidationError) Error() string {
+	return e.Fields.Error()
+}
+
 type Product struct {
 	ProductID         uuid.UUID     `gorm:"primaryKey;type:uuid;column:product_id" json:"product_id"`
 	ProductName       string        `gorm:"not null;column:product_name" json:"product_name"`
-	ProductReference  string        `gorm:"not null;column:product_reference" json:"product_reference"`
+	ProductReference  string        `gorm:"not null;uniqueIndex;column:product_reference" json:"product_reference"`
+	Barcode           string        `gorm:"not null;default:'';uniqueIndex:idx_product_barcode,where:barcode <> '';column:barcode" json:"barcode"`
 	Status            ProductStatus `gorm:"not null;column:status" json:"status"`
 	ProductCategoryID uuid.UUID     `gorm:"not null;column:product_category_id" json:"product_category_id"`
 	Price             int           `gorm:"not null;column:price" json:"price"`
@@ -21,6 +46,8 @@ type Product struct {
 	SupplierID        uuid.UUID     `gorm:"not null;column:supplier_id" json:"supplier_id"`
 	Quantity          int           `gorm:"not null;column:quantity" json:"quantity"`
 	DateCreated       time.Time     `gorm:"not null;column:date_created" json:"date_created"`
+	AbcClass          AbcClass      `gorm:"not null;default:'';column:abc_class" json:"abc_class"`
+	Version           int           `gorm:"not null;default:1;column:version" json:"version"`
 
 	//
 	Supplier        Supplier        `json:"supplier"`
@@ -44,9 +71,34 @@ const (
 	ProductStatusOutOfStock ProductStatus = "Out of Stock"
 )
 
+func (s ProductStatus) IsValid() bool {
+	return s == ProductStatusAvailable || s == ProductStatusOnOrder || s == ProductStatusOutOfStock
+}
+
+// Length limits of product text fields, in characters.
+const (
+	ProductNameMaxLength      = 255
+	ProductReferenceMaxLength = 64
+	StockLocationMaxLength    = 255
+
+	ProductSearchQueryMaxLength = 200
+)
+
+// AbcClass is the Pareto class of a product by consumption value, empty until the first analysis.
+type AbcClass string
+
+const (
+	AbcClassA AbcClass = "A"
+	AbcClassB AbcClass = "B"
+	AbcClassC AbcClass = "C"
+)
+
+// ProductCreateReq creates a product. ProductReference is generated from the product.reference
+// config when empty.
 type ProductCreateReq struct {
 	ProductName       string        `json:"product_name"`
 	ProductReference  string        `json:"product_reference"`
+	Barcode           string        `json:"barcode"`
 	Status            ProductStatus `json:"status"`
 	ProductCategoryID string        `json:"product_category_id"`
 	Price             int           `json:"price"`
@@ -55,28 +107,34 @@ type ProductCreateReq struct {
 	SupplierID        string        `json:"supplier_id"`
 }
 
-func (req *ProductCreateReq) Validate() response.RespCode {
-	if req.ProductName == "" {
-		return response.ErrInvalidName
+// Validate checks every field and returns all the failures.
+func (req *ProductCreateReq) Validate() response.FieldErrors {
+	var errs response.FieldErrors
+	validateProductName(&errs, req.ProductName)
+	// An empty reference is generated; a blank one is refused like on update.
+	if req.ProductReference != "" {
+		validateProductReference(&errs, req.ProductReference)
 	}
-	if req.ProductReference == "" {
-		req.ProductReference = uuid.New().String()
+	if req.Barcode != "" && !utils.IsValidBarcode(req.Barcode) {
+		errs.Add("barcode", response.ErrInvalidBarcode)
 	}
-	req.ProductReference = "PROD-" + time.Now().Format("200601") + "-" + req.ProductReference
-
-	if req.Status == "" || (ProductStatus(req.Status) != ProductStatusAvailable && ProductStatus(req.Status) != ProductStatusOnOrder && ProductStatus(req.Status) != ProductStatusOutOfStock) {
-		return response.ErrInvalidStatus
+	if !req.Status.IsValid() {
+		errs.Add("status", response.ErrInvalidStatus)
 	}
 	if req.ProductCategoryID == "" || !utils.IsValidUUID(req.ProductCategoryID) {
-		return response.ErrInvalidCategory
+		errs.Add("product_category_id", response.ErrInvalidCategory)
+	}
+	if req.Price < 0 {
+		errs.Add("price", response.ErrInvalidPrice)
 	}
-	if req.StockLocation == "" {
-		return response.ErrInvalidStockLocation
+	validateStockLocation(&errs, req.StockLocation)
+	if req.Quantity < 0 {
+		errs.Add("quantity", response.ErrInvalidQuantity)
 	}
 	if req.SupplierID == "" || !utils.IsValidUUID(req.SupplierID) {
-		return response.ErrInvalidSupplier
+		errs.Add("supplier_id", response.ErrInvalidSupplier)
 	}
-	return response.OkCode
+	return errs
 }
 
 type ProductUpdateReq struct {
@@ -89,55 +147,273 @@ type ProductUpdateReq struct {
 	StockLocation     string        `json:"stock_location"`
 	Quantity          int           `json:"quantity"`
 	SupplierID        string        `json:"supplier_id"`
+	Barcode           string        `json:"barcode"`
+	Version           int           `json:"version"`
 }
 
-func (req *ProductUpdateReq) Validate() response.RespCode {
+// Validate checks every field and returns all the failures.
+func (req *ProductUpdateReq) Validate() response.FieldErrors {
+	var errs response.FieldErrors
 	if req.ProductID == "" || !utils.IsValidUUID(req.ProductID) {
-		return response.ErrInvalidProduct
+		errs.Add("product_id", response.ErrInvalidProduct)
 	}
-	if req.ProductName == "" {
-		return response.ErrInvalidName
+	if req.Version <= 0 {
+		errs.Add("version", response.ErrInvalidVersion)
 	}
-	if req.ProductReference == "" {
-		return response.ErrInvalidReference
+	validateProductName(&errs, req.ProductName)
+	validateProductReference(&errs, req.ProductReference)
+	if req.Barcode != "" && !utils.IsValidBarcode(req.Barcode) {
+		errs.Add("barcode", response.ErrInvalidBarcode)
 	}
-	if req.Status == "" || (ProductStatus(req.Status) != ProductStatusAvailable && ProductStatus(req.Status) != ProductStatusOnOrder && ProductStatus(req.Status) != ProductStatusOutOfStock) {
-		return response.ErrInvalidStatus
+	if !req.Status.IsValid() {
+		errs.Add("status", response.ErrInvalidStatus)
 	}
 	if req.ProductCategoryID == "" || !utils.IsValidUUID(req.ProductCategoryID) {
-		return response.ErrInvalidCategory
+		errs.Add("product_category_id", response.ErrInvalidCategory)
+	}
+	if req.Price < 0 {
+		errs.Add("price", response.ErrInvalidPrice)
 	}
-	if req.StockLocation == "" {
-		return response.ErrInvalidStockLocation
+	validateStockLocation(&errs, req.StockLocation)
+	if req.Quantity < 0 {
+		errs.Add("quantity", response.ErrInvalidQuantity)
 	}
 	if req.SupplierID == "" || !utils.IsValidUUID(req.SupplierID) {
-		return response.ErrInvalidSupplier
+		errs.Add("supplier_id", response.ErrInvalidSupplier)
+	}
+	return errs
+}
+
+// ProductPatchReq is a JSON Merge Patch (RFC 7396) of a product. Only the fields present in the
+// body are validated and updated. Null is rejected since product fields are not nullable, except
+// for barcode where null removes it.
+type ProductPatchReq struct {
+	ProductID         string         `json:"product_id"`
+	Version           int            `json:"version"`
+	ProductName       *string        `json:"product_name,omitempty"`
+	ProductReference  *string        `json:"product_reference,omitempty"`
+	Status            *ProductStatus `json:"status,omitempty"`
+	ProductCategoryID *string        `json:"product_category_id,omitempty"`
+	Price             *int           `json:"price,omitempty"`
+	StockLocation     *string        `json:"stock_location,omitempty"`
+	Quantity          *int           `json:"quantity,omitempty"`
+	SupplierID        *string        `json:"supplier_id,omitempty"`
+	Barcode           *string        `json:"barcode,omitempty"`
+
+	//
+	present map[string]bool
+}
+
+func (req *ProductPatchReq) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	type patch ProductPatchReq
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode((*patch)(req)); err != nil {
+		return err
+	}
+
+	req.present = make(map[string]bool, len(fields))
+	for field := range fields {
+		req.present[field] = true
+	}
+	return nil
+}
+
+// Validate checks every present field and returns all the failures.
+func (req *ProductPatchReq) Validate() response.FieldErrors {
+	var errs response.FieldErrors
+	if req.ProductID == "" || !utils.IsValidUUID(req.ProductID) {
+		errs.Add("product_id", response.ErrInvalidProduct)
+	}
+	if req.Version <= 0 {
+		errs.Add("version", response.ErrInvalidVersion)
+	}
+	// A field present with a null value decodes to a nil pointer.
+	if req.present["product_name"] {
+		validateProductName(&errs, deref(req.ProductName))
+	}
+	if req.present["product_reference"] {
+		validateProductReference(&errs, deref(req.ProductReference))
+	}
+	if req.Barcode != nil && *req.Barcode != "" && !utils.IsValidBarcode(*req.Barcode) {
+		errs.Add("barcode", response.ErrInvalidBarcode)
+	}
+	if req.present["status"] && (req.Status == nil || !req.Status.IsValid()) {
+		errs.Add("status", response.ErrInvalidStatus)
+	}
+	if req.present["product_category_id"] && (req.ProductCategoryID == nil || !utils.IsValidUUID(*req.ProductCategoryID)) {
+		errs.Add("product_category_id", response.ErrInvalidCategory)
+	}
+	if req.present["price"] && (req.Price == nil || *req.Price < 0) {
+		errs.Add("price", response.ErrInvalidPrice)
+	}
+	if req.present["stock_location"] {
+		validateStockLocation(&errs, deref(req.StockLocation))
+	}
+	if req.present["quantity"] && (req.Quantity == nil || *req.Quantity < 0) {
+		errs.Add("quantity", response.ErrInvalidQuantity)
+	}
+	if req.present["supplier_id"] && (req.SupplierID == nil || !utils.IsValidUUID(*req.SupplierID)) {
+		errs.Add("supplier_id", response.ErrInvalidSupplier)
+	}
+	return errs
+}
+
+func validateProductName(errs *response.FieldErrors, name string) {
+	if strings.TrimSpace(name) == "" {
+		errs.Add("product_name", response.ErrInvalidName)
+	} else if utf8.RuneCountInString(name) > ProductNameMaxLength {
+		errs.Add("product_name", response.ErrTooLong)
 	}
-	return response.OkCode
+}
+
+func validateProductReference(errs *response.FieldErrors, reference string) {
+	if strings.TrimSpace(reference) == "" {
+		errs.Add("product_reference", response.ErrInvalidReference)
+	} else if utf8.RuneCountInString(reference) > ProductReferenceMaxLength {
+		errs.Add("product_reference", response.ErrTooLong)
+	}
+}
+
+func validateStockLocation(errs *response.FieldErrors, location string) {
+	if strings.TrimSpace(location) == "" {
+		errs.Add("stock_location", response.ErrInvalidStockLocation)
+	} else if utf8.RuneCountInString(location) > StockLocationMaxLength {
+		errs.Add("stock_location", response.ErrTooLong)
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// Apply sets the patched fields on the product and returns the changed columns with their new values.
+func (req *ProductPatchReq) Apply(p *Product) map[string]interface{} {
+	columns := make(map[string]interface{})
+	if req.ProductName != nil && *req.ProductName != p.ProductName {
+		p.ProductName = *req.ProductName
+		columns["product_name"] = p.ProductName
+	}
+	if req.ProductReference != nil && *req.ProductReference != p.ProductReference {
+		p.ProductReference = *req.ProductReference
+		columns["product_reference"] = p.ProductReference
+	}
+	if req.Status != nil && *req.Status != p.Status {
+		p.Status = *req.Status
+		columns["status"] = p.Status
+	}
+	if req.ProductCategoryID != nil && uuid.MustParse(*req.ProductCategoryID) != p.ProductCategoryID {
+		p.ProductCategoryID = uuid.MustParse(*req.ProductCategoryID)
+		columns["product_category_id"] = p.ProductCategoryID
+	}
+	if req.Price != nil && *req.Price != p.Price {
+		p.Price = *req.Price
+		columns["price"] = p.Price
+	}
+	if req.StockLocation != nil && *req.StockLocation != p.StockLocation {
+		p.StockLocation = *req.StockLocation
+		columns["stock_location"] = p.StockLocation
+	}
+	if req.Quantity != nil && *req.Quantity != p.Quantity {
+		p.Quantity = *req.Quantity
+		columns["quantity"] = p.Quantity
+	}
+	if req.SupplierID != nil && uuid.MustParse(*req.SupplierID) != p.SupplierID {
+		p.SupplierID = uuid.MustParse(*req.SupplierID)
+		columns["supplier_id"] = p.SupplierID
+	}
+	if req.present["barcode"] {
+		barcode := ""
+		if req.Barcode != nil {
+			barcode = *req.Barcode
+		}
+		if barcode != p.Barcode {
+			p.Barcode = barcode
+			columns["barcode"] = p.Barcode
+		}
+	}
+	return columns
 }
 
 type ProductSearchReq struct {
-	ProductReferences  []string `json:"product_references"`
-	ProductNames       []string `json:"product_names,omitempty"`
-	Status             []string `json:"status,omitempty"`
-	ProductCategoryIDs []string `json:"product_category_ids,omitempty"`
-	SupplierIDs        []string `json:"supplier_ids,omitempty"`
-	PriceFrom          int      `json:"price_from"`
-	PriceTo            int      `json:"price_to"`
-	StockLocations     []string `json:"stock_locations,omitempty"`
-
-	DateCreatedFrom string `json:"date_created_from,omitempty"`
-	DateCreatedTo   string `json:"date_created_to,omitempty"`
+	// Query is a free-text search of the name, reference, category and supplier names,
+	// tolerant to typos. Results are ordered by relevance.
+	Query              string   `form:"query" json:"query,omitempty"`
+	ProductReferences  []string `form:"product_references" json:"product_references"`
+	ProductNames       []string `form:"product_names" json:"product_names,omitempty"`
+	Status             []string `form:"status" json:"status,omitempty"`
+	ProductCategoryIDs []string `form:"product_category_ids" json:"product_category_ids,omitempty"`
+	SupplierIDs        []string `form:"supplier_ids" json:"supplier_ids,omitempty"`
+	PriceFrom          int      `form:"price_from" json:"price_from"`
+	PriceTo            int      `form:"price_to" json:"price_to"`
+	StockLocations     []string `form:"stock_locations" json:"stock_locations,omitempty"`
+	AbcClasses         []string `form:"abc_classes" json:"abc_classes,omitempty"`
+
+	DateCreatedFrom string `form:"date_created_from" json:"date_created_from,omitempty"`
+	DateCreatedTo   string `form:"date_created_to" json:"date_created_to,omitempty"`
+	// Filter is a filter expression ANDed with the other filters, see ParseProductFilter.
+	Filter string `form:"filter" json:"filter,omitempty"`
+
+	// Sort is a comma-separated list of name, price, quantity and date, each optionally
+	// prefixed with "-" for descending order. Defaults to -date, or relevance with Query.
+	Sort string `form:"sort" json:"sort,omitempty"`
+	// Cursor is the next_cursor of the previous page, used instead of offset. It is only
+	// valid with the same sort.
+	Cursor string `form:"cursor" json:"cursor,omitempty"`
+	// Facets adds the counts per status, category, supplier, stock location and price bucket.
+	Facets bool `form:"facets" json:"facets,omitempty"`
 	Pagination
 
 	//convert
-	ProductCategoryUUIDs []uuid.UUID
-	SupplierUUIDs        []uuid.UUID
+	ProductCategoryUUIDs []uuid.UUID      `form:"-" json:"-"`
+	SupplierUUIDs        []uuid.UUID      `form:"-" json:"-"`
+	SortKeys             []ProductSortKey `form:"-" json:"-"`
+	After                *ProductCursor   `form:"-" json:"-"`
+	FilterExpr           ProductFilter    `form:"-" json:"-"`
+	// FilterErr tells where an invalid Filter failed to parse.
+	FilterErr error `form:"-" json:"-"`
+}
+
+// OrderByRelevance reports whether the list is ordered by the relevance to Query, which
+// can't be paged with a cursor.
+func (req *ProductSearchReq) OrderByRelevance() bool {
+	return req.Query != "" && req.Sort == ""
+}
+
+// NextCursor returns the cursor of the page after last.
+func (req *ProductSearchReq) NextCursor(last Product) string {
+	return encodeProductCursor(req.Sort, req.SortKeys, last)
 }
 
 type ProductSearchRp struct {
 	Data []Product `json:"data"`
 	Pagination
+	// Total is the number of products matching the filters, on all pages.
+	Total   int64 `json:"total"`
+	HasMore bool  `json:"has_more"`
+	// NextCursor is empty on the last page and when ordering by relevance.
+	NextCursor string         `json:"next_cursor,omitempty"`
+	Facets     *ProductFacets `json:"facets,omitempty"`
+}
+
+type ProductLookupReq struct {
+	Code string `form:"code" json:"code"`
+}
+
+func (req *ProductLookupReq) Validate() response.RespCode {
+	req.Code = strings.TrimSpace(req.Code)
+	if req.Code == "" {
+		return response.ErrInvalidReference
+	}
+	return response.OkCode
 }
 
 type ProductByIdReq struct {
@@ -150,8 +426,8 @@ type ProductDistanceByIdReq struct {
 }
 
 type Pagination struct {
-	Limit  int `json:"limit"`
-	Offset int `json:"offset"`
+	Limit  int `form:"limit" json:"limit"`
+	Offset int `form:"offset" json:"offset"`
 }
 
 type SearchRp struct {
@@ -160,6 +436,10 @@ type SearchRp struct {
 }
 
 func (req *ProductSearchReq) Validate() response.RespCode {
+	req.Query = strings.TrimSpace(req.Query)
+	if utf8.RuneCountInString(req.Query) > ProductSearchQueryMaxLength {
+		return response.ErrTooLong
+	}
 	if req.DateCreatedFrom != "" {
 		if err := validateDateFormat(req.DateCreatedFrom); err != nil {
 			return response.ErrInvalidDate
@@ -185,6 +465,42 @@ func (req *ProductSearchReq) Validate() response.RespCode {
 		}
 	}
 
+	for _, class := range req.AbcClasses {
+		if AbcClass(class) != AbcClassA && AbcClass(class) != AbcClassB && AbcClass(class) != AbcClassC {
+			return response.ErrInvalidAbcClass
+		}
+	}
+
+	req.Filter = strings.TrimSpace(req.Filter)
+	if req.Filter != "" {
+		filter, err := ParseProductFilter(req.Filter)
+		if err != nil {
+			req.FilterErr = err
+			return response.ErrInvalidFilter
+		}
+		req.FilterExpr = filter
+	}
+
+	if req.Sort != "" {
+		keys, ok := parseProductSort(req.Sort)
+		if !ok {
+			return response.ErrInvalidSort
+		}
+		req.SortKeys = keys
+	} else if req.Query == "" {
+		req.SortKeys = []ProductSortKey{{Key: "date", Desc: true}}
+	}
+	if req.Cursor != "" {
+		if req.OrderByRelevance() {
+			return response.ErrInvalidCursor
+		}
+		after, err := decodeProductCursor(req.Cursor, req.Sort, req.SortKeys)
+		if err != nil {
+			return response.ErrInvalidCursor
+		}
+		req.After = after
+	}
+
 	if len(req.ProductCategoryIDs) > 0 {
 		req.ProductCategoryUUIDs = getUUIDs(req.ProductCategoryIDs)
 	}
@@ -205,6 +521,21 @@ func validateDateFormat(date string) error {
 	}
 	return nil
 }
+
+// parseUUIDs parses ids, and returns false if one is not a UUID. Unlike getUUIDs, a filter
+// made only of malformed IDs is refused rather than dropped.
+func parseUUIDs(ids []string) ([]uuid.UUID, bool) {
+	uids := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		uid, err := uuid.Parse(id)
+		if err != nil {
+			return nil, false
+		}
+		uids = append(uids, uid)
+	}
+	return uids, true
+}
+
 func getUUIDs(l []string) []uuid.UUID {
 	res := []uuid.UUID{}
 	for _, s := range l {
@@ -222,8 +553,29 @@ const (
 	CategoryProductsScanKey string = "category_products:*"
 	SupplierProductsScanKey string = "supplier_products:*"
 	TotalProductsKey        string = "product_total"
+	// ProductCacheKey holds a product with its supplier and category. List pages are cached
+	// under the current generation, incremented on every product write.
+	ProductCacheKey        string = "product:%v"
+	ProductListCacheKey    string = "product_list:%v:%v"
+	ProductListCacheGenKey string = "product_list_gen"
+
+	ProductReferenceSeq string = "product_reference_seq"
 )
 
+// ProductSearchVector is the full-text document of a product. Queries must use the same
+// expression as the idx_product_search index for it to be used.
+const ProductSearchVector = "to_tsvector('english', product_name || ' ' || product_reference)"
+
+// ProductSearchIndexes back the query search: full-text on products and trigram indexes
+// (pg_trgm) for the typo-tolerant matches.
+var ProductSearchIndexes = []string{
+	"CREATE INDEX IF NOT EXISTS idx_product_search ON product USING GIN (" + ProductSearchVector + ")",
+	"CREATE INDEX IF NOT EXISTS idx_product_name_trgm ON product USING GIN (product_name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_product_reference_trgm ON product USING GIN (product_reference gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_product_category_name_trgm ON product_category USING GIN (product_category_name gin_trgm_ops)",
+	"CREATE INDEX IF NOT EXISTS idx_supplier_name_trgm ON supplier USING GIN (supplier_name gin_trgm_ops)",
+}
+
 type ProductDistanceRp struct {
 	IpCurrentCity     string `json:"ip_current_city"`
 	StockLocationCity string `json:"stock_location_city"`
@@ -12,6 +12,7 @@ type ProductCategory struct {
 	Status              ProductCategoryStatus `gorm:"not null;column:status" json:"status"`
 	CreatedAt           string                `gorm:"not null;column:created_at" json:"created_at"`
 	UpdatedAt           string                `gorm:"not null;column:updated_at" json:"updated_at"`
+	Version             int                   `gorm:"not null;default:1;column:version" json:"version"`
 }
 
 func (p *ProductCategory) TableName() string {
@@ -41,8 +42,8 @@ func (req *ProductCategoryCreateReq) Validate() response.RespCode {
 }
 
 type ProductCategorySearchReq struct {
-	ProductCategoryName string         `json:"supplier_name,omitempty"`
-	Status              SupplierStatus `json:"status,omitempty"`
+	ProductCategoryName string         `form:"product_category_name" json:"supplier_name,omitempty"`
+	Status              SupplierStatus `form:"status" json:"status,omitempty"`
 	Pagination
 }
 
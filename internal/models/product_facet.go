@@ -0,0 +1,46 @@
+package models
+
+// ProductFacet is a field product lists can be counted by. Each facet is counted with all the
+// filters of the request except its own, so the UI can show how many products every other
+// value would give.
+type ProductFacet string
+
+const (
+	FacetStatus        ProductFacet = "status"
+	FacetCategory      ProductFacet = "category"
+	FacetSupplier      ProductFacet = "supplier"
+	FacetStockLocation ProductFacet = "stock_location"
+	FacetPrice         ProductFacet = "price"
+)
+
+// FacetRow is a count of one facet value. For FacetPrice, Value is the bucket number of
+// width_bucket over the configured price bounds.
+type FacetRow struct {
+	Facet ProductFacet
+	Value string
+	Label string
+	Count int64
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	// Label is the category or supplier name.
+	Label string `json:"label,omitempty"`
+	Count int64  `json:"count"`
+}
+
+// PriceBucketCount counts the products with From <= price <= To. To is empty for the last
+// bucket.
+type PriceBucketCount struct {
+	From  int   `json:"from"`
+	To    *int  `json:"to,omitempty"`
+	Count int64 `json:"count"`
+}
+
+type ProductFacets struct {
+	Status        []FacetCount       `json:"status"`
+	Category      []FacetCount       `json:"category"`
+	Supplier      []FacetCount       `json:"supplier"`
+	StockLocation []FacetCount       `json:"stock_location"`
+	Price         []PriceBucketCount `json:"price"`
+}
@@ -0,0 +1,489 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const (
+	ProductFilterMaxLength = 1000
+	// ProductFilterMaxDepth and ProductFilterMaxTerms bound the size of the generated SQL.
+	ProductFilterMaxDepth = 10
+	ProductFilterMaxTerms = 50
+)
+
+// FilterType is the type of the values a filter field is compared with.
+type FilterType int
+
+const (
+	FilterString FilterType = iota
+	FilterNumber
+	FilterDate
+	FilterUUID
+	FilterStatus
+	FilterAbcClass
+)
+
+// FilterOp is a comparison operator of a filter expression.
+type FilterOp string
+
+const (
+	FilterEq       FilterOp = "="
+	FilterNe       FilterOp = "!="
+	FilterLt       FilterOp = "<"
+	FilterLe       FilterOp = "<="
+	FilterGt       FilterOp = ">"
+	FilterGe       FilterOp = ">="
+	FilterContains FilterOp = "~"
+	FilterExcludes FilterOp = "!~"
+	FilterIn       FilterOp = "IN"
+	FilterNotIn    FilterOp = "NOT IN"
+)
+
+// Negated reports whether the operator excludes the values it names.
+func (op FilterOp) Negated() bool {
+	return op == FilterNe || op == FilterExcludes || op == FilterNotIn
+}
+
+// Positive returns the operator without its negation.
+func (op FilterOp) Positive() FilterOp {
+	switch op {
+	case FilterNe:
+		return FilterEq
+	case FilterExcludes:
+		return FilterContains
+	case FilterNotIn:
+		return FilterIn
+	}
+	return op
+}
+
+// FilterRelation is a table a field is read from: products match when their ForeignKey is
+// the Key of a row of Table matching the comparison.
+type FilterRelation struct {
+	ForeignKey string
+	Table      string
+	Key        string
+}
+
+// ProductFilterField is a field filter expressions may compare, and the column it reads.
+type ProductFilterField struct {
+	Name     string
+	Column   string
+	Type     FilterType
+	Relation *FilterRelation
+}
+
+var (
+	categoryRelation = &FilterRelation{ForeignKey: "product_category_id", Table: "product_category", Key: "product_category_id"}
+	supplierRelation = &FilterRelation{ForeignKey: "supplier_id", Table: "supplier", Key: "supplier_id"}
+)
+
+// productFilterFields is the whitelist of the fields of filter expressions. Only these
+// columns can appear in the generated SQL.
+var productFilterFields = map[string]ProductFilterField{
+	"name":           {Column: "product_name", Type: FilterString},
+	"reference":      {Column: "product_reference", Type: FilterString},
+	"barcode":        {Column: "barcode", Type: FilterString},
+	"status":         {Column: "status", Type: FilterStatus},
+	"price":          {Column: "price", Type: FilterNumber},
+	"quantity":       {Column: "quantity", Type: FilterNumber},
+	"stock_location": {Column: "stock_location", Type: FilterString},
+	"abc_class":      {Column: "abc_class", Type: FilterAbcClass},
+	"date":           {Column: "CAST(date_created AS date)", Type: FilterDate},
+	"category.id":    {Column: "product_category_id", Type: FilterUUID},
+	"category.name":  {Column: "product_category_name", Type: FilterString, Relation: categoryRelation},
+	"supplier.id":    {Column: "supplier_id", Type: FilterUUID},
+	"supplier.name":  {Column: "supplier_name", Type: FilterString, Relation: supplierRelation},
+}
+
+// filterTypeOps are the operators allowed per field type.
+var filterTypeOps = map[FilterType][]FilterOp{
+	FilterString:   {FilterEq, FilterNe, FilterContains, FilterExcludes, FilterIn, FilterNotIn},
+	FilterNumber:   {FilterEq, FilterNe, FilterLt, FilterLe, FilterGt, FilterGe, FilterIn, FilterNotIn},
+	FilterDate:     {FilterEq, FilterNe, FilterLt, FilterLe, FilterGt, FilterGe},
+	FilterUUID:     {FilterEq, FilterNe, FilterIn, FilterNotIn},
+	FilterStatus:   {FilterEq, FilterNe, FilterIn, FilterNotIn},
+	FilterAbcClass: {FilterEq, FilterNe, FilterIn, FilterNotIn},
+}
+
+// ProductFilter is a node of a parsed filter expression: FilterAnd, FilterOr, FilterNot or
+// FilterComparison.
+type ProductFilter interface {
+	productFilter()
+}
+
+type FilterAnd struct {
+	Terms []ProductFilter
+}
+
+type FilterOr struct {
+	Terms []ProductFilter
+}
+
+type FilterNot struct {
+	Term ProductFilter
+}
+
+// FilterComparison compares a field with one value, or a list for IN. Values have the Go type
+// of the field: string, int64 or uuid.UUID; dates are "2006-01-02" strings.
+type FilterComparison struct {
+	Field  ProductFilterField
+	Op     FilterOp
+	Values []interface{}
+}
+
+func (FilterAnd) productFilter()        {}
+func (FilterOr) productFilter()         {}
+func (FilterNot) productFilter()        {}
+func (FilterComparison) productFilter() {}
+
+// FilterSyntaxError is a filter expression that can't be parsed, at byte Pos.
+type FilterSyntaxError struct {
+	Pos     int
+	Message string
+}
+
+func (e *FilterSyntaxError) Error() string {
+	return fmt.Sprintf("filter: %s at position %d", e.Message, e.Pos)
+}
+
+// ParseProductFilter parses a filter expression such as
+//
+//	quantity < 10 AND (status = 'On Order' OR supplier.name ~ 'ACME')
+//
+// Comparisons are joined with AND, OR and NOT and grouped with parentheses. Strings are
+// single-quoted and a quote inside one is doubled. ~ and !~ match a substring, ignoring case.
+func ParseProductFilter(expr string) (ProductFilter, error) {
+	if len(expr) > ProductFilterMaxLength {
+		return nil, &FilterSyntaxError{Pos: ProductFilterMaxLength, Message: "expression is too long"}
+	}
+	tokens, err := lexFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &filterParser{tokens: tokens}
+	filter, err := p.parseOr(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+	return filter, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenString
+	tokenNumber
+	tokenOp
+	tokenLParen
+	tokenRParen
+	tokenComma
+)
+
+type filterToken struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func lexFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		r, size := utf8.DecodeRuneInString(expr[i:])
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+		case r == '(':
+			tokens = append(tokens, filterToken{tokenLParen, "(", i})
+			i++
+		case r == ')':
+			tokens = append(tokens, filterToken{tokenRParen, ")", i})
+			i++
+		case r == ',':
+			tokens = append(tokens, filterToken{tokenComma, ",", i})
+			i++
+		case r == '\'':
+			var sb strings.Builder
+			start := i
+			i++
+			for {
+				if i >= len(expr) {
+					return nil, &FilterSyntaxError{Pos: start, Message: "unterminated string"}
+				}
+				if expr[i] == '\'' {
+					if i+1 < len(expr) && expr[i+1] == '\'' {
+						sb.WriteByte('\'')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(expr[i])
+				i++
+			}
+			tokens = append(tokens, filterToken{tokenString, sb.String(), start})
+		case r == '-' || (r >= '0' && r <= '9'):
+			start := i
+			i++
+			for i < len(expr) && expr[i] >= '0' && expr[i] <= '9' {
+				i++
+			}
+			if expr[start:i] == "-" {
+				return nil, &FilterSyntaxError{Pos: start, Message: "invalid number"}
+			}
+			tokens = append(tokens, filterToken{tokenNumber, expr[start:i], start})
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(expr) {
+				r, size := utf8.DecodeRuneInString(expr[i:])
+				if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' {
+					break
+				}
+				i += size
+			}
+			tokens = append(tokens, filterToken{tokenIdent, expr[start:i], start})
+		default:
+			op := ""
+			for _, candidate := range []string{"!=", "<>", "<=", ">=", "!~", "=", "<", ">", "~"} {
+				if strings.HasPrefix(expr[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, &FilterSyntaxError{Pos: i, Message: fmt.Sprintf("unexpected %q", r)}
+			}
+			if op == "<>" {
+				op = string(FilterNe)
+			}
+			tokens = append(tokens, filterToken{tokenOp, op, i})
+			i += len(op)
+		}
+	}
+	return append(tokens, filterToken{tokenEOF, "end of expression", len(expr)}), nil
+}
+
+type filterParser struct {
+	tokens []filterToken
+	pos    int
+	terms  int
+}
+
+func (p *filterParser) peek() filterToken {
+	return p.tokens[p.pos]
+}
+
+func (p *filterParser) next() filterToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// keyword consumes the next token if it is the keyword, in any case.
+func (p *filterParser) keyword(word string) bool {
+	tok := p.peek()
+	if tok.kind == tokenIdent && strings.EqualFold(tok.text, word) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *filterParser) errorf(tok filterToken, format string, args ...interface{}) error {
+	return &FilterSyntaxError{Pos: tok.pos, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *filterParser) parseOr(depth int) (ProductFilter, error) {
+	if depth > ProductFilterMaxDepth {
+		return nil, p.errorf(p.peek(), "expression is nested too deeply")
+	}
+	term, err := p.parseAnd(depth)
+	if err != nil {
+		return nil, err
+	}
+	terms := []ProductFilter{term}
+	for p.keyword("OR") {
+		if term, err = p.parseAnd(depth); err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return FilterOr{Terms: terms}, nil
+}
+
+func (p *filterParser) parseAnd(depth int) (ProductFilter, error) {
+	term, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	terms := []ProductFilter{term}
+	for p.keyword("AND") {
+		if term, err = p.parseUnary(depth); err != nil {
+			return nil, err
+		}
+		terms = append(terms, term)
+	}
+	if len(terms) == 1 {
+		return terms[0], nil
+	}
+	return FilterAnd{Terms: terms}, nil
+}
+
+func (p *filterParser) parseUnary(depth int) (ProductFilter, error) {
+	if p.keyword("NOT") {
+		if depth+1 > ProductFilterMaxDepth {
+			return nil, p.errorf(p.peek(), "expression is nested too deeply")
+		}
+		term, err := p.parseUnary(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return FilterNot{Term: term}, nil
+	}
+
+	if tok := p.peek(); tok.kind == tokenLParen {
+		p.next()
+		term, err := p.parseOr(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if tok := p.next(); tok.kind != tokenRParen {
+			return nil, p.errorf(tok, "expected ) but found %q", tok.text)
+		}
+		return term, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *filterParser) parseComparison() (ProductFilter, error) {
+	tok := p.next()
+	if tok.kind != tokenIdent {
+		return nil, p.errorf(tok, "expected a field but found %q", tok.text)
+	}
+	field, ok := productFilterFields[strings.ToLower(tok.text)]
+	if !ok {
+		return nil, p.errorf(tok, "unknown field %q", tok.text)
+	}
+	field.Name = strings.ToLower(tok.text)
+
+	p.terms++
+	if p.terms > ProductFilterMaxTerms {
+		return nil, p.errorf(tok, "too many comparisons")
+	}
+
+	opTok := p.peek()
+	var op FilterOp
+	switch {
+	case opTok.kind == tokenOp:
+		p.next()
+		op = FilterOp(opTok.text)
+	case p.keyword("IN"):
+		op = FilterIn
+	case p.keyword("NOT"):
+		if !p.keyword("IN") {
+			return nil, p.errorf(p.peek(), "expected IN after NOT")
+		}
+		op = FilterNotIn
+	default:
+		return nil, p.errorf(opTok, "expected an operator but found %q", opTok.text)
+	}
+	if !isFilterOpAllowed(field.Type, op) {
+		return nil, p.errorf(opTok, "operator %s is not allowed on %s", op, field.Name)
+	}
+
+	comparison := FilterComparison{Field: field, Op: op}
+	if op != FilterIn && op != FilterNotIn {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = []interface{}{value}
+		return comparison, nil
+	}
+
+	if tok := p.next(); tok.kind != tokenLParen {
+		return nil, p.errorf(tok, "expected ( but found %q", tok.text)
+	}
+	for {
+		value, err := p.parseValue(field)
+		if err != nil {
+			return nil, err
+		}
+		comparison.Values = append(comparison.Values, value)
+		tok := p.next()
+		if tok.kind == tokenRParen {
+			break
+		}
+		if tok.kind != tokenComma {
+			return nil, p.errorf(tok, "expected , or ) but found %q", tok.text)
+		}
+	}
+	return comparison, nil
+}
+
+// parseValue parses a literal and converts it to the type of field.
+func (p *filterParser) parseValue(field ProductFilterField) (interface{}, error) {
+	tok := p.next()
+	if field.Type == FilterNumber {
+		if tok.kind != tokenNumber {
+			return nil, p.errorf(tok, "%s takes a number", field.Name)
+		}
+		n, err := strconv.ParseInt(tok.text, 10, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %s", tok.text)
+		}
+		return n, nil
+	}
+
+	if tok.kind != tokenString {
+		return nil, p.errorf(tok, "%s takes a quoted string", field.Name)
+	}
+	switch field.Type {
+	case FilterDate:
+		if _, err := time.Parse(dateFormat, tok.text); err != nil {
+			return nil, p.errorf(tok, "%s takes a date like '2006-01-02'", field.Name)
+		}
+	case FilterUUID:
+		id, err := uuid.Parse(tok.text)
+		if err != nil {
+			return nil, p.errorf(tok, "%s takes a UUID", field.Name)
+		}
+		return id, nil
+	case FilterStatus:
+		if !ProductStatus(tok.text).IsValid() {
+			return nil, p.errorf(tok, "invalid status %q", tok.text)
+		}
+	case FilterAbcClass:
+		if class := AbcClass(tok.text); class != AbcClassA && class != AbcClassB && class != AbcClassC {
+			return nil, p.errorf(tok, "invalid ABC class %q", tok.text)
+		}
+	}
+	return tok.text, nil
+}
+
+func isFilterOpAllowed(fieldType FilterType, op FilterOp) bool {
+	for _, allowed := range filterTypeOps[fieldType] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,248 @@
+package models
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func filterField(name string) ProductFilterField {
+	field := productFilterFields[name]
+	field.Name = name
+	return field
+}
+
+func comparison(name string, op FilterOp, values ...interface{}) FilterComparison {
+	return FilterComparison{Field: filterField(name), Op: op, Values: values}
+}
+
+func TestParseProductFilter(t *testing.T) {
+	id := uuid.MustParse("0b8e5c4a-3f1d-4c2e-9a7b-6d5e4f3a2b1c")
+	tests := []struct {
+		name string
+		expr string
+		want ProductFilter
+	}{
+		{"number", "quantity < 10", comparison("quantity", FilterLt, int64(10))},
+		{"negative number", "price >= -5", comparison("price", FilterGe, int64(-5))},
+		{"string", "name = 'Widget'", comparison("name", FilterEq, "Widget")},
+		{"doubled quote", "name = 'O''Brien'", comparison("name", FilterEq, "O'Brien")},
+		{"only a doubled quote", "name = ''''", comparison("name", FilterEq, "'")},
+		{"empty string", "name = ''", comparison("name", FilterEq, "")},
+		{"not equal alias", "name <> 'a'", comparison("name", FilterNe, "a")},
+		{"contains", "supplier.name ~ 'ACME'", comparison("supplier.name", FilterContains, "ACME")},
+		{"excludes", "reference !~ 'X'", comparison("reference", FilterExcludes, "X")},
+		{"field in any case", "QUANTITY = 1", comparison("quantity", FilterEq, int64(1))},
+		{"date", "date > '2024-01-31'", comparison("date", FilterGt, "2024-01-31")},
+		{"uuid", "category.id = '" + id.String() + "'", comparison("category.id", FilterEq, id)},
+		{"status", "status = 'On Order'", comparison("status", FilterEq, "On Order")},
+		{"abc class", "abc_class = 'A'", comparison("abc_class", FilterEq, "A")},
+		{"in", "quantity IN (1, 2, 3)", comparison("quantity", FilterIn, int64(1), int64(2), int64(3))},
+		{"not in", "status NOT IN ('Available', 'Out of Stock')", comparison("status", FilterNotIn, "Available", "Out of Stock")},
+		{"not in lower case", "abc_class not in ('C')", comparison("abc_class", FilterNotIn, "C")},
+		{"not in uuid", "supplier.id NOT IN ('" + id.String() + "')", comparison("supplier.id", FilterNotIn, id)},
+		{
+			"and binds tighter than or",
+			"quantity < 10 OR price > 5 AND name ~ 'a'",
+			FilterOr{Terms: []ProductFilter{
+				comparison("quantity", FilterLt, int64(10)),
+				FilterAnd{Terms: []ProductFilter{
+					comparison("price", FilterGt, int64(5)),
+					comparison("name", FilterContains, "a"),
+				}},
+			}},
+		},
+		{
+			"parentheses",
+			"(quantity < 10 OR price > 5) and name ~ 'a'",
+			FilterAnd{Terms: []ProductFilter{
+				FilterOr{Terms: []ProductFilter{
+					comparison("quantity", FilterLt, int64(10)),
+					comparison("price", FilterGt, int64(5)),
+				}},
+				comparison("name", FilterContains, "a"),
+			}},
+		},
+		{
+			"not",
+			"NOT NOT quantity = 0",
+			FilterNot{Term: FilterNot{Term: comparison("quantity", FilterEq, int64(0))}},
+		},
+		{"max depth", strings.Repeat("(", ProductFilterMaxDepth) + "quantity = 1" + strings.Repeat(")", ProductFilterMaxDepth), comparison("quantity", FilterEq, int64(1))},
+		{"max terms", strings.Repeat("quantity = 1 OR ", ProductFilterMaxTerms-1) + "quantity = 1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProductFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("ParseProductFilter(%q) error = %v", tt.expr, err)
+			}
+			if tt.want != nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseProductFilter(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseProductFilterErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		expr    string
+		pos     int
+		message string
+	}{
+		{"empty", "", 0, `expected a field but found "end of expression"`},
+		{"too long", strings.Repeat(" ", ProductFilterMaxLength+1), ProductFilterMaxLength, "expression is too long"},
+		{"unterminated string", "name = 'abc", 7, "unterminated string"},
+		{"unterminated after doubled quote", "name = 'a''", 7, "unterminated string"},
+		{"lone minus", "price = -", 8, "invalid number"},
+		{"unexpected character", "name = 'a' ; drop", 11, `unexpected ';'`},
+		{"unknown field", "secret = 'a'", 0, `unknown field "secret"`},
+		{"missing operator", "name 'a'", 5, `expected an operator but found "a"`},
+		{"operator not allowed", "price ~ '1'", 6, "operator ~ is not allowed on price"},
+		{"in not allowed on dates", "date IN ('2024-01-01')", 5, "operator IN is not allowed on date"},
+		{"not without in", "status NOT = 'Available'", 11, "expected IN after NOT"},
+		{"in without list", "quantity IN 1", 12, `expected ( but found "1"`},
+		{"unclosed list", "quantity NOT IN (1 2)", 19, `expected , or ) but found "2"`},
+		{"unclosed parenthesis", "(quantity = 1", 13, `expected ) but found "end of expression"`},
+		{"trailing token", "quantity = 1)", 12, `unexpected ")"`},
+		{"string for number", "quantity = '1'", 11, "quantity takes a number"},
+		{"number out of range", "quantity = 99999999999999999999", 11, "invalid number 99999999999999999999"},
+		{"number for string", "name = 1", 7, "name takes a quoted string"},
+		{"invalid date", "date = '31/01/2024'", 7, "date takes a date like '2006-01-02'"},
+		{"invalid uuid", "category.id = 'abc'", 14, "category.id takes a UUID"},
+		{"invalid status", "status IN ('Available', 'Lost')", 24, `invalid status "Lost"`},
+		{"invalid abc class", "abc_class = 'D'", 12, `invalid ABC class "D"`},
+		{
+			"too deep",
+			strings.Repeat("(", ProductFilterMaxDepth+1) + "quantity = 1" + strings.Repeat(")", ProductFilterMaxDepth+1),
+			ProductFilterMaxDepth + 1,
+			"expression is nested too deeply",
+		},
+		{
+			"too many nots",
+			strings.Repeat("NOT ", ProductFilterMaxDepth+1) + "quantity = 1",
+			4 * (ProductFilterMaxDepth + 1),
+			"expression is nested too deeply",
+		},
+		{
+			"too many comparisons",
+			strings.Repeat("quantity = 1 OR ", ProductFilterMaxTerms) + "quantity = 1",
+			16 * ProductFilterMaxTerms,
+			"too many comparisons",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseProductFilter(tt.expr)
+			var syntaxErr *FilterSyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseProductFilter(%q) error = %v, want a FilterSyntaxError", tt.expr, err)
+			}
+			if syntaxErr.Pos != tt.pos || syntaxErr.Message != tt.message {
+				t.Errorf("ParseProductFilter(%q) error = %q at %d, want %q at %d",
+					tt.expr, syntaxErr.Message, syntaxErr.Pos, tt.message, tt.pos)
+			}
+		})
+	}
+}
+
+func TestLexFilter(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want []filterToken
+	}{
+		{
+			"comparison",
+			"price>=10",
+			[]filterToken{{tokenIdent, "price", 0}, {tokenOp, ">=", 5}, {tokenNumber, "10", 7}, {tokenEOF, "end of expression", 9}},
+		},
+		{
+			"doubled quotes",
+			"'it''s' ''''",
+			[]filterToken{{tokenString, "it's", 0}, {tokenString, "'", 8}, {tokenEOF, "end of expression", 12}},
+		},
+		{
+			"list",
+			"x NOT IN ('a',-1)",
+			[]filterToken{
+				{tokenIdent, "x", 0}, {tokenIdent, "NOT", 2}, {tokenIdent, "IN", 6}, {tokenLParen, "(", 9},
+				{tokenString, "a", 10}, {tokenComma, ",", 13}, {tokenNumber, "-1", 14}, {tokenRParen, ")", 16},
+				{tokenEOF, "end of expression", 17},
+			},
+		},
+		{
+			"operators",
+			"!= <> <= >= !~ = < > ~",
+			[]filterToken{
+				{tokenOp, "!=", 0}, {tokenOp, "!=", 3}, {tokenOp, "<=", 6}, {tokenOp, ">=", 9}, {tokenOp, "!~", 12},
+				{tokenOp, "=", 15}, {tokenOp, "<", 17}, {tokenOp, ">", 19}, {tokenOp, "~", 21},
+				{tokenEOF, "end of expression", 22},
+			},
+		},
+		{
+			"unicode",
+			"catégorie.nom = 'é'",
+			[]filterToken{{tokenIdent, "catégorie.nom", 0}, {tokenOp, "=", 15}, {tokenString, "é", 17}, {tokenEOF, "end of expression", 21}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lexFilter(tt.expr)
+			if err != nil {
+				t.Fatalf("lexFilter(%q) error = %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("lexFilter(%q) = %v, want %v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	id := uuid.MustParse("0b8e5c4a-3f1d-4c2e-9a7b-6d5e4f3a2b1c")
+	tests := []struct {
+		name    string
+		field   string
+		token   filterToken
+		want    interface{}
+		wantErr string
+	}{
+		{"number", "price", filterToken{tokenNumber, "42", 0}, int64(42), ""},
+		{"negative number", "quantity", filterToken{tokenNumber, "-3", 0}, int64(-3), ""},
+		{"quoted number", "price", filterToken{tokenString, "42", 0}, nil, "price takes a number"},
+		{"string", "name", filterToken{tokenString, "Widget", 0}, "Widget", ""},
+		{"unquoted string", "name", filterToken{tokenIdent, "Widget", 0}, nil, "name takes a quoted string"},
+		{"date", "date", filterToken{tokenString, "2024-02-29", 0}, "2024-02-29", ""},
+		{"impossible date", "date", filterToken{tokenString, "2023-02-29", 0}, nil, "date takes a date like '2006-01-02'"},
+		{"uuid", "supplier.id", filterToken{tokenString, id.String(), 0}, id, ""},
+		{"invalid uuid", "supplier.id", filterToken{tokenString, "1234", 0}, nil, "supplier.id takes a UUID"},
+		{"status", "status", filterToken{tokenString, "Out of Stock", 0}, "Out of Stock", ""},
+		{"status in another case", "status", filterToken{tokenString, "available", 0}, nil, `invalid status "available"`},
+		{"abc class", "abc_class", filterToken{tokenString, "B", 0}, "B", ""},
+		{"lower case abc class", "abc_class", filterToken{tokenString, "b", 0}, nil, `invalid ABC class "b"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := &filterParser{tokens: []filterToken{tt.token, {tokenEOF, "end of expression", 0}}}
+			got, err := p.parseValue(filterField(tt.field))
+			if tt.wantErr != "" {
+				var syntaxErr *FilterSyntaxError
+				if !errors.As(err, &syntaxErr) || syntaxErr.Message != tt.wantErr {
+					t.Fatalf("parseValue(%v) error = %v, want %q", tt.token, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseValue(%v) error = %v", tt.token, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseValue(%v) = %#v, want %#v", tt.token, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package models
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var errInvalidCursor = errors.New("invalid cursor")
+
+// productSortColumns are the sort parameter keys and the column each one orders by.
+var productSortColumns = map[string]string{
+	"name":     "product_name",
+	"price":    "price",
+	"quantity": "quantity",
+	"date":     "date_created",
+}
+
+// ProductSortKey is one key of a product list sort, e.g. "-price" is price descending.
+type ProductSortKey struct {
+	Key  string
+	Desc bool
+}
+
+func (k ProductSortKey) Column() string {
+	return productSortColumns[k.Key]
+}
+
+// value returns the cursor value of the key for p.
+func (k ProductSortKey) value(p Product) interface{} {
+	switch k.Key {
+	case "name":
+		return p.ProductName
+	case "price":
+		return p.Price
+	case "quantity":
+		return p.Quantity
+	default:
+		return p.DateCreated.Format(time.RFC3339Nano)
+	}
+}
+
+// parseValue converts a decoded cursor value back to the column type.
+func (k ProductSortKey) parseValue(raw json.RawMessage) (interface{}, error) {
+	switch k.Key {
+	case "name":
+		var v string
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	case "price", "quantity":
+		var v int
+		err := json.Unmarshal(raw, &v)
+		return v, err
+	default:
+		var v string
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, v)
+	}
+}
+
+// parseProductSort parses a comma-separated list of sort keys, each optionally prefixed with
+// "-" for descending order.
+func parseProductSort(sort string) ([]ProductSortKey, bool) {
+	var keys []ProductSortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(sort, ",") {
+		part = strings.TrimSpace(part)
+		key := ProductSortKey{Key: strings.TrimPrefix(part, "-"), Desc: strings.HasPrefix(part, "-")}
+		if _, ok := productSortColumns[key.Key]; !ok || seen[key.Key] {
+			return nil, false
+		}
+		seen[key.Key] = true
+		keys = append(keys, key)
+	}
+	return keys, true
+}
+
+// ProductCursor is the position after the last product of a page: its sort values and ID,
+// the tie-breaker of every sort.
+type ProductCursor struct {
+	Values    []interface{}
+	ProductID uuid.UUID
+}
+
+// productCursorData is the encoded form of a cursor. The sort is kept to reject a cursor used
+// with another sort.
+type productCursorData struct {
+	Sort      string            `json:"s"`
+	Values    []json.RawMessage `json:"v"`
+	ProductID uuid.UUID         `json:"id"`
+}
+
+func encodeProductCursor(sort string, keys []ProductSortKey, last Product) string {
+	data := productCursorData{Sort: sort, ProductID: last.ProductID}
+	for _, key := range keys {
+		raw, _ := json.Marshal(key.value(last))
+		data.Values = append(data.Values, raw)
+	}
+	b, _ := json.Marshal(data)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func decodeProductCursor(cursor, sort string, keys []ProductSortKey) (*ProductCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errInvalidCursor
+	}
+	var data productCursorData
+	if err := json.Unmarshal(b, &data); err != nil {
+		return nil, errInvalidCursor
+	}
+	if data.Sort != sort || len(data.Values) != len(keys) || data.ProductID == uuid.Nil {
+		return nil, errInvalidCursor
+	}
+
+	after := &ProductCursor{ProductID: data.ProductID}
+	for i, key := range keys {
+		value, err := key.parseValue(data.Values[i])
+		if err != nil {
+			return nil, errInvalidCursor
+		}
+		after.Values = append(after.Values, value)
+	}
+	return after, nil
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"encoding/base64"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseProductSort(t *testing.T) {
+	tests := []struct {
+		sort   string
+		want   []ProductSortKey
+		wantOk bool
+	}{
+		{"price", []ProductSortKey{{Key: "price"}}, true},
+		{"-date", []ProductSortKey{{Key: "date", Desc: true}}, true},
+		{"-price, name", []ProductSortKey{{Key: "price", Desc: true}, {Key: "name"}}, true},
+		{"quantity,-date,name", []ProductSortKey{{Key: "quantity"}, {Key: "date", Desc: true}, {Key: "name"}}, true},
+		{"price,-price", nil, false},
+		{"barcode", nil, false},
+		{"--price", nil, false},
+		{"price,", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sort, func(t *testing.T) {
+			got, ok := parseProductSort(tt.sort)
+			if ok != tt.wantOk || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseProductSort(%q) = %v, %v, want %v, %v", tt.sort, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestProductCursor(t *testing.T) {
+	created := time.Date(2024, 6, 10, 6, 13, 20, 123456789, time.UTC)
+	first := Product{
+		ProductID:   uuid.MustParse("00000000-0000-0000-0000-000000000001"),
+		ProductName: "Widget",
+		Price:       1500,
+		Quantity:    0,
+		DateCreated: created,
+	}
+	// Same sort values as first: only the ID tells them apart.
+	tie := first
+	tie.ProductID = uuid.MustParse("00000000-0000-0000-0000-000000000002")
+
+	tests := []struct {
+		name string
+		sort string
+		last Product
+		want []interface{}
+	}{
+		{"price", "price", first, []interface{}{1500}},
+		{"zero quantity", "-quantity", first, []interface{}{0}},
+		{"date keeps nanoseconds", "-date", first, []interface{}{created}},
+		{"several keys", "name,-price,date", first, []interface{}{"Widget", 1500, created}},
+		{"tie on every key", "-price,name", tie, []interface{}{1500, "Widget"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, ok := parseProductSort(tt.sort)
+			if !ok {
+				t.Fatalf("parseProductSort(%q) failed", tt.sort)
+			}
+			cursor := encodeProductCursor(tt.sort, keys, tt.last)
+			got, err := decodeProductCursor(cursor, tt.sort, keys)
+			if err != nil {
+				t.Fatalf("decodeProductCursor(%q) error = %v", cursor, err)
+			}
+			if got.ProductID != tt.last.ProductID {
+				t.Errorf("cursor ID = %v, want %v", got.ProductID, tt.last.ProductID)
+			}
+			if !reflect.DeepEqual(got.Values, tt.want) {
+				t.Errorf("cursor values = %#v, want %#v", got.Values, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeProductCursorErrors(t *testing.T) {
+	priceKeys, _ := parseProductSort("price")
+	dateKeys, _ := parseProductSort("-date")
+	last := Product{ProductID: uuid.New(), Price: 10, DateCreated: time.Now()}
+	encode := func(s string) string { return base64.RawURLEncoding.EncodeToString([]byte(s)) }
+
+	tests := []struct {
+		name   string
+		cursor string
+		sort   string
+		keys   []ProductSortKey
+	}{
+		{"other sort", encodeProductCursor("price", priceKeys, last), "-price", priceKeys},
+		{"other keys", encodeProductCursor("price", priceKeys, last), "price", dateKeys},
+		{"not base64", "not a cursor!", "price", priceKeys},
+		{"padded base64", encodeProductCursor("price", priceKeys, last) + "=", "price", priceKeys},
+		{"not json", encode("price"), "price", priceKeys},
+		{"no ID", encode(`{"s":"price","v":[10]}`), "price", priceKeys},
+		{"nil ID", encode(`{"s":"price","v":[10],"id":"00000000-0000-0000-0000-000000000000"}`), "price", priceKeys},
+		{"missing value", encode(`{"s":"price","v":[],"id":"` + last.ProductID.String() + `"}`), "price", priceKeys},
+		{"extra value", encode(`{"s":"price","v":[10,11],"id":"` + last.ProductID.String() + `"}`), "price", priceKeys},
+		{"string for number", encode(`{"s":"price","v":["10"],"id":"` + last.ProductID.String() + `"}`), "price", priceKeys},
+		{"invalid date", encode(`{"s":"-date","v":["yesterday"],"id":"` + last.ProductID.String() + `"}`), "-date", dateKeys},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := decodeProductCursor(tt.cursor, tt.sort, tt.keys); err != errInvalidCursor {
+				t.Errorf("decodeProductCursor(%q) error = %v, want %v", tt.cursor, err, errInvalidCursor)
+			}
+		})
+	}
+}
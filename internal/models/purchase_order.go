@@ -0,0 +1,66 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type PurchaseOrder struct {
+	PurchaseOrderID uuid.UUID           `gorm:"primaryKey;type:uuid;column:purchase_order_id" json:"purchase_order_id"`
+	SupplierID      uuid.UUID           `gorm:"not null;type:uuid;index;column:supplier_id" json:"supplier_id"`
+	Status          PurchaseOrderStatus `gorm:"not null;column:status" json:"status"`
+	Note            string              `gorm:"column:note" json:"note"`
+	CreatedAt       time.Time           `gorm:"not null;column:created_at" json:"created_at"`
+	UpdatedAt       time.Time           `gorm:"not null;column:updated_at" json:"updated_at"`
+
+	//
+	Supplier Supplier            `json:"supplier"`
+	Lines    []PurchaseOrderLine `gorm:"foreignKey:PurchaseOrderID" json:"lines"`
+}
+
+func (p *PurchaseOrder) TableName() string {
+	return "purchase_order"
+}
+
+type PurchaseOrderLine struct {
+	LineID          uuid.UUID `gorm:"primaryKey;type:uuid;column:line_id" json:"line_id"`
+	PurchaseOrderID uuid.UUID `gorm:"not null;type:uuid;index;column:purchase_order_id" json:"purchase_order_id"`
+	ProductID       uuid.UUID `gorm:"not null;type:uuid;column:product_id" json:"product_id"`
+	Quantity        int       `gorm:"not null;column:quantity" json:"quantity"`
+	UnitPrice       int       `gorm:"not null;column:unit_price" json:"unit_price"`
+}
+
+func (p *PurchaseOrderLine) TableName() string {
+	return "purchase_order_line"
+}
+
+type PurchaseOrderStatus string
+
+const (
+	PurchaseOrderDraft     PurchaseOrderStatus = "draft"
+	PurchaseOrderOrdered   PurchaseOrderStatus = "ordered"
+	PurchaseOrderReceived  PurchaseOrderStatus = "received"
+	PurchaseOrderCancelled PurchaseOrderStatus = "cancelled"
+)
+
+type PurchaseOrderSearchReq struct {
+	SupplierIDs []string              `json:"supplier_ids,omitempty"`
+	Status      []PurchaseOrderStatus `json:"status,omitempty"`
+	Pagination
+
+	//convert
+	SupplierUUIDs []uuid.UUID `json:"-"`
+}
+
+func (req *PurchaseOrderSearchReq) Validate() response.RespCode {
+	var ok bool
+	if req.SupplierUUIDs, ok = parseUUIDs(req.SupplierIDs); !ok {
+		return response.ErrInvalidSupplier
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return response.OkCode
+}
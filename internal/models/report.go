@@ -0,0 +1,256 @@
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"stock-management/pkgs/response"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrSavedSearchNotFound = errors.New("invalid saved search")
+	ErrDuplicateSearchName = errors.New("saved search name already exists")
+	ErrScheduleNotFound    = errors.New("invalid report schedule")
+)
+
+// SavedSearch is a named product search of a user. Names are unique per user.
+type SavedSearch struct {
+	SavedSearchID uuid.UUID        `gorm:"primaryKey;type:uuid;column:saved_search_id" json:"saved_search_id"`
+	UserID        uuid.UUID        `gorm:"not null;type:uuid;uniqueIndex:idx_saved_search_user_name;column:user_id" json:"user_id"`
+	Name          string           `gorm:"not null;uniqueIndex:idx_saved_search_user_name;column:name" json:"name"`
+	Search        ProductSearchReq `gorm:"not null;type:jsonb;serializer:json;column:search" json:"search"`
+	CreatedAt     time.Time        `gorm:"not null;column:created_at" json:"created_at"`
+	UpdatedAt     time.Time        `gorm:"not null;column:updated_at" json:"updated_at"`
+}
+
+func (s *SavedSearch) TableName() string {
+	return "saved_search"
+}
+
+// ReportSchedule runs a saved search on a cron schedule and delivers the matching products as
+// a PDF or CSV export.
+type ReportSchedule struct {
+	ScheduleID    uuid.UUID      `gorm:"primaryKey;type:uuid;column:schedule_id" json:"schedule_id"`
+	UserID        uuid.UUID      `gorm:"not null;type:uuid;index;column:user_id" json:"user_id"`
+	SavedSearchID uuid.UUID      `gorm:"not null;type:uuid;index;column:saved_search_id" json:"saved_search_id"`
+	Name          string         `gorm:"not null;column:name" json:"name"`
+	Cron          string         `gorm:"not null;column:cron" json:"cron"`
+	Format        ReportFormat   `gorm:"not null;column:format" json:"format"`
+	Delivery      ReportDelivery `gorm:"not null;column:delivery" json:"delivery"`
+	// Recipients are the email addresses of ReportDeliveryEmail.
+	Recipients []string   `gorm:"not null;type:jsonb;serializer:json;column:recipients" json:"recipients"`
+	Enabled    bool       `gorm:"not null;column:enabled" json:"enabled"`
+	LastRunAt  *time.Time `gorm:"column:last_run_at" json:"last_run_at"`
+	CreatedAt  time.Time  `gorm:"not null;column:created_at" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"not null;column:updated_at" json:"updated_at"`
+}
+
+func (s *ReportSchedule) TableName() string {
+	return "report_schedule"
+}
+
+// ReportRun is the history entry of one run of a schedule.
+type ReportRun struct {
+	RunID      uuid.UUID       `gorm:"primaryKey;type:uuid;column:run_id" json:"run_id"`
+	ScheduleID uuid.UUID       `gorm:"not null;type:uuid;index:idx_report_run_schedule;column:schedule_id" json:"schedule_id"`
+	Status     ReportRunStatus `gorm:"not null;column:status" json:"status"`
+	// Products is the number of products in the export.
+	Products int `gorm:"not null;column:products" json:"products"`
+	// Output is the written file, or the recipients of the email.
+	Output     string     `gorm:"not null;column:output" json:"output"`
+	Error      string     `gorm:"not null;column:error" json:"error,omitempty"`
+	StartedAt  time.Time  `gorm:"not null;index:idx_report_run_schedule;column:started_at" json:"started_at"`
+	FinishedAt *time.Time `gorm:"column:finished_at" json:"finished_at"`
+}
+
+func (r *ReportRun) TableName() string {
+	return "report_run"
+}
+
+type ReportFormat string
+
+const (
+	ReportFormatPDF ReportFormat = "pdf"
+	ReportFormatCSV ReportFormat = "csv"
+)
+
+func (f ReportFormat) IsValid() bool {
+	return f == ReportFormatPDF || f == ReportFormatCSV
+}
+
+// ContentType is the MIME type of the export.
+func (f ReportFormat) ContentType() string {
+	if f == ReportFormatCSV {
+		return "text/csv"
+	}
+	return "application/pdf"
+}
+
+type ReportDelivery string
+
+const (
+	// ReportDeliveryDirectory writes the export to the configured report directory.
+	ReportDeliveryDirectory ReportDelivery = "directory"
+	// ReportDeliveryEmail sends the export as an attachment by SMTP.
+	ReportDeliveryEmail ReportDelivery = "email"
+)
+
+type ReportRunStatus string
+
+const (
+	ReportRunRunning ReportRunStatus = "running"
+	ReportRunSuccess ReportRunStatus = "success"
+	ReportRunFailed  ReportRunStatus = "failed"
+)
+
+// ReportCronParser parses schedule expressions: the five standard fields or a descriptor
+// such as @daily.
+var ReportCronParser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor)
+
+type SavedSearchCreateReq struct {
+	Name   string           `json:"name"`
+	Search ProductSearchReq `json:"search"`
+}
+
+func (req *SavedSearchCreateReq) Validate() response.RespCode {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return response.ErrInvalidName
+	}
+	if len(req.Name) > ProductNameMaxLength {
+		return response.ErrTooLong
+	}
+	return validateSavedSearch(&req.Search)
+}
+
+type SavedSearchUpdateReq struct {
+	SavedSearchID string           `json:"saved_search_id"`
+	Name          string           `json:"name"`
+	Search        ProductSearchReq `json:"search"`
+
+	//convert
+	SavedSearchUUID uuid.UUID `json:"-"`
+}
+
+func (req *SavedSearchUpdateReq) Validate() response.RespCode {
+	var err error
+	if req.SavedSearchUUID, err = uuid.Parse(req.SavedSearchID); err != nil {
+		return response.ErrInvalidSavedSearch
+	}
+	create := SavedSearchCreateReq{Name: req.Name, Search: req.Search}
+	code := create.Validate()
+	req.Name, req.Search = create.Name, create.Search
+	return code
+}
+
+// validateSavedSearch validates a search and drops its paging: saved searches are run for
+// all the matching products.
+func validateSavedSearch(search *ProductSearchReq) response.RespCode {
+	search.Pagination = Pagination{}
+	search.Cursor = ""
+	search.Facets = false
+	return search.Validate()
+}
+
+type SavedSearchDeleteReq struct {
+	SavedSearchID string `json:"saved_search_id"`
+}
+
+type ReportScheduleCreateReq struct {
+	SavedSearchID string         `json:"saved_search_id"`
+	Name          string         `json:"name"`
+	Cron          string         `json:"cron"`
+	Format        ReportFormat   `json:"format"`
+	Delivery      ReportDelivery `json:"delivery"`
+	Recipients    []string       `json:"recipients"`
+	Enabled       bool           `json:"enabled"`
+
+	//convert
+	SavedSearchUUID uuid.UUID `json:"-"`
+}
+
+func (req *ReportScheduleCreateReq) Validate() response.RespCode {
+	var err error
+	if req.SavedSearchUUID, err = uuid.Parse(req.SavedSearchID); err != nil {
+		return response.ErrInvalidSavedSearch
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return response.ErrInvalidName
+	}
+	if len(req.Name) > ProductNameMaxLength {
+		return response.ErrTooLong
+	}
+	req.Cron = strings.TrimSpace(req.Cron)
+	if _, err := ReportCronParser.Parse(req.Cron); err != nil {
+		return response.ErrInvalidCron
+	}
+	if req.Format == "" {
+		req.Format = ReportFormatPDF
+	}
+	if !req.Format.IsValid() {
+		return response.ErrInvalidReportFormat
+	}
+
+	switch req.Delivery {
+	case ReportDeliveryDirectory:
+		req.Recipients = []string{}
+	case ReportDeliveryEmail:
+		if len(req.Recipients) == 0 {
+			return response.ErrInvalidRecipient
+		}
+		for i, recipient := range req.Recipients {
+			addr, err := mail.ParseAddress(recipient)
+			if err != nil {
+				return response.ErrInvalidRecipient
+			}
+			req.Recipients[i] = addr.Address
+		}
+	default:
+		return response.ErrInvalidDelivery
+	}
+	return response.OkCode
+}
+
+type ReportScheduleUpdateReq struct {
+	ScheduleID string `json:"schedule_id"`
+	ReportScheduleCreateReq
+
+	//convert
+	ScheduleUUID uuid.UUID `json:"-"`
+}
+
+func (req *ReportScheduleUpdateReq) Validate() response.RespCode {
+	var err error
+	if req.ScheduleUUID, err = uuid.Parse(req.ScheduleID); err != nil {
+		return response.ErrInvalidSchedule
+	}
+	return req.ReportScheduleCreateReq.Validate()
+}
+
+type ReportScheduleIdReq struct {
+	ScheduleID string `json:"schedule_id"`
+}
+
+type ReportRunSearchReq struct {
+	ScheduleID string `json:"schedule_id"`
+	Pagination
+
+	//convert
+	ScheduleUUID uuid.UUID `json:"-"`
+}
+
+func (req *ReportRunSearchReq) Validate() response.RespCode {
+	var err error
+	if req.ScheduleUUID, err = uuid.Parse(req.ScheduleID); err != nil {
+		return response.ErrInvalidSchedule
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	return response.OkCode
+}
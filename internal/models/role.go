@@ -0,0 +1,191 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"stock-management/pkgs/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Role struct {
+	RoleID      uuid.UUID `gorm:"primaryKey;type:uuid;column:role_id" json:"role_id"`
+	Name        string    `gorm:"not null;uniqueIndex;column:name" json:"name"`
+	Description string    `gorm:"column:description" json:"description"`
+	BuiltIn     bool      `gorm:"not null;default:false;column:built_in" json:"built_in"`
+	CreatedAt   time.Time `gorm:"not null;column:created_at" json:"created_at"`
+
+	//
+	RolePermissions []RolePermission `gorm:"foreignKey:RoleID" json:"-"`
+	Permissions     []string         `gorm:"-" json:"permissions"`
+}
+
+func (r *Role) TableName() string {
+	return "role"
+}
+
+type RolePermission struct {
+	RoleID     uuid.UUID `gorm:"primaryKey;type:uuid;column:role_id" json:"role_id"`
+	Permission string    `gorm:"primaryKey;column:permission" json:"permission"`
+}
+
+func (r *RolePermission) TableName() string {
+	return "role_permission"
+}
+
+type UserRole struct {
+	UserID uuid.UUID `gorm:"primaryKey;type:uuid;column:user_id" json:"user_id"`
+	RoleID uuid.UUID `gorm:"primaryKey;type:uuid;index;column:role_id" json:"role_id"`
+}
+
+func (r *UserRole) TableName() string {
+	return "user_role"
+}
+
+const (
+	PermProductRead     string = "product:read"
+	PermProductWrite    string = "product:write"
+	PermProductTools    string = "product:tools"
+	PermCategoryRead    string = "category:read"
+	PermCategoryWrite   string = "category:write"
+	PermSupplierRead    string = "supplier:read"
+	PermSupplierWrite   string = "supplier:write"
+	PermStockRead       string = "stock:read"
+	PermStockWrite      string = "stock:write"
+	PermStatisticsRead  string = "statistics:read"
+	PermStatisticsWrite string = "statistics:write"
+	PermPlanningRead    string = "planning:read"
+	PermPlanningWrite   string = "planning:write"
+	PermUserManage      string = "user:manage"
+	PermAuditRead       string = "audit:read"
+	PermReportManage    string = "report:manage"
+	PermEventRead       string = "event:read"
+	PermEventWrite      string = "event:write"
+	PermWebhookManage   string = "webhook:manage"
+)
+
+var AllPermissions = []string{
+	PermProductRead, PermProductWrite, PermProductTools,
+	PermCategoryRead, PermCategoryWrite,
+	PermSupplierRead, PermSupplierWrite,
+	PermStockRead, PermStockWrite,
+	PermStatisticsRead, PermStatisticsWrite,
+	PermPlanningRead, PermPlanningWrite,
+	PermUserManage, PermAuditRead,
+	PermReportManage,
+	PermEventRead, PermEventWrite,
+	PermWebhookManage,
+}
+
+const (
+	RoleAdmin          string = "admin"
+	RoleWarehouseClerk string = "warehouse_clerk"
+	RolePurchaser      string = "purchaser"
+	RoleViewer         string = "viewer"
+)
+
+// DefaultRoles are created on startup when missing. Their permissions can be changed afterwards.
+var DefaultRoles = map[string][]string{
+	RoleAdmin: AllPermissions,
+	RoleWarehouseClerk: {
+		PermProductRead, PermProductWrite, PermProductTools,
+		PermCategoryRead, PermSupplierRead,
+		PermStockRead, PermStockWrite,
+		PermStatisticsRead,
+	},
+	RolePurchaser: {
+		PermProductRead, PermProductTools, PermCategoryRead,
+		PermSupplierRead, PermSupplierWrite,
+		PermStockRead, PermStatisticsRead,
+		PermPlanningRead, PermPlanningWrite,
+	},
+	// Viewers only see product, category and supplier lists and details, and statistics.
+	RoleViewer: {
+		PermProductRead, PermCategoryRead, PermSupplierRead,
+		PermStatisticsRead,
+	},
+}
+
+func isValidPermission(permission string) bool {
+	for _, p := range AllPermissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+type RoleCreateReq struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func (req *RoleCreateReq) Validate() response.RespCode {
+	if req.Name == "" {
+		return response.ErrInvalidName
+	}
+	for _, permission := range req.Permissions {
+		if !isValidPermission(permission) {
+			return response.ErrInvalidPermission
+		}
+	}
+	return response.OkCode
+}
+
+type RoleUpdateReq struct {
+	RoleID      string   `json:"role_id"`
+	Description string   `json:"description"`
+	Permissions []string `json:"permissions"`
+}
+
+func (req *RoleUpdateReq) Validate() response.RespCode {
+	if req.RoleID == "" || !utils.IsValidUUID(req.RoleID) {
+		return response.ErrInvalidRole
+	}
+	for _, permission := range req.Permissions {
+		if !isValidPermission(permission) {
+			return response.ErrInvalidPermission
+		}
+	}
+	return response.OkCode
+}
+
+type RoleByIdReq struct {
+	RoleID string `json:"role_id"`
+}
+
+type UserAssignRolesReq struct {
+	UserID  string   `json:"user_id"`
+	RoleIDs []string `json:"role_ids"`
+}
+
+func (req *UserAssignRolesReq) Validate() response.RespCode {
+	if req.UserID == "" || !utils.IsValidUUID(req.UserID) {
+		return response.ErrInvalidUser
+	}
+	for _, id := range req.RoleIDs {
+		if !utils.IsValidUUID(id) {
+			return response.ErrInvalidRole
+		}
+	}
+	return response.OkCode
+}
+
+type UserSearchReq struct {
+	Username string     `json:"username,omitempty"`
+	Status   UserStatus `json:"status,omitempty"`
+	Pagination
+}
+
+func (req *UserSearchReq) Validate() response.RespCode {
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return response.OkCode
+}
+
+type UserWithRoles struct {
+	User
+	Roles []string `json:"roles"`
+}
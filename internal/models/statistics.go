@@ -0,0 +1,220 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type StatisticsMetric string
+
+const (
+	StatisticsMetricCount    StatisticsMetric = "count"
+	StatisticsMetricQuantity StatisticsMetric = "quantity"
+	StatisticsMetricValue    StatisticsMetric = "value"
+)
+
+type StatisticsGroupBy string
+
+const (
+	StatisticsGroupByProduct  StatisticsGroupBy = "product"
+	StatisticsGroupByCategory StatisticsGroupBy = "category"
+	StatisticsGroupBySupplier StatisticsGroupBy = "supplier"
+)
+
+func (g StatisticsGroupBy) IsValid() bool {
+	return g == StatisticsGroupByProduct || g == StatisticsGroupByCategory || g == StatisticsGroupBySupplier
+}
+
+// invalidIDCode is the code of a malformed ID of the grouped entity.
+func (g StatisticsGroupBy) invalidIDCode() response.RespCode {
+	switch g {
+	case StatisticsGroupByProduct:
+		return response.ErrInvalidProduct
+	case StatisticsGroupBySupplier:
+		return response.ErrInvalidSupplier
+	default:
+		return response.ErrInvalidCategory
+	}
+}
+
+type StatisticsReq struct {
+	Metric StatisticsMetric `form:"metric" json:"metric"`
+}
+
+func (req *StatisticsReq) Validate() response.RespCode {
+	if req.Metric == "" {
+		req.Metric = StatisticsMetricCount
+	}
+	if req.Metric != StatisticsMetricCount && req.Metric != StatisticsMetricQuantity && req.Metric != StatisticsMetricValue {
+		return response.ErrInvalidMetric
+	}
+	return response.OkCode
+}
+
+type StatisticsItem struct {
+	ID         string          `json:"id"`
+	Name       string          `json:"name"`
+	Value      decimal.Decimal `json:"value"`
+	Percentage decimal.Decimal `json:"percentage"`
+}
+
+type StatisticsRp struct {
+	Metric StatisticsMetric `json:"metric"`
+	Total  decimal.Decimal  `json:"total"`
+	Data   []StatisticsItem `json:"data"`
+}
+
+type TurnoverItem struct {
+	ID                     string          `json:"id"`
+	Name                   string          `json:"name"`
+	IssuedQuantity         int64           `json:"issued_quantity"`
+	IssuedValue            int64           `json:"issued_value"`
+	AverageInventoryValue  decimal.Decimal `json:"average_inventory_value"`
+	TurnoverRatio          decimal.Decimal `json:"turnover_ratio"`
+	AverageDaysToSellStock decimal.Decimal `json:"average_days_to_sell_stock"`
+}
+
+type TurnoverRp struct {
+	DateFrom string            `json:"date_from"`
+	DateTo   string            `json:"date_to"`
+	GroupBy  StatisticsGroupBy `json:"group_by"`
+	Data     []TurnoverItem    `json:"data"`
+}
+
+type DaysOfCoverItem struct {
+	ProductID         string           `json:"product_id"`
+	ProductName       string           `json:"product_name"`
+	ProductReference  string           `json:"product_reference"`
+	Quantity          int              `json:"quantity"`
+	IssuedQuantity    int64            `json:"issued_quantity"`
+	AverageDailyIssue decimal.Decimal  `json:"average_daily_issue"`
+	DaysOfCover       *decimal.Decimal `json:"days_of_cover"`
+}
+
+type DaysOfCoverRp struct {
+	Days int               `json:"days"`
+	Data []DaysOfCoverItem `json:"data"`
+}
+
+type DeadStockItem struct {
+	ProductID         uuid.UUID  `json:"product_id"`
+	ProductName       string     `json:"product_name"`
+	ProductReference  string     `json:"product_reference"`
+	ProductCategoryID uuid.UUID  `json:"product_category_id"`
+	SupplierID        uuid.UUID  `json:"supplier_id"`
+	Quantity          int        `json:"quantity"`
+	Value             int64      `json:"value"`
+	LastMovementAt    *time.Time `json:"last_movement_at"`
+}
+
+type DeadStockGroup struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Products int    `json:"products"`
+	Quantity int64  `json:"quantity"`
+	Value    int64  `json:"value"`
+}
+
+type DeadStockRp struct {
+	Days        int              `json:"days"`
+	Data        []DeadStockItem  `json:"data"`
+	PerCategory []DeadStockGroup `json:"per_category"`
+	PerSupplier []DeadStockGroup `json:"per_supplier"`
+}
+
+type GroupTotalRow struct {
+	GroupID  uuid.UUID
+	Quantity int64
+	Value    decimal.Decimal
+}
+
+type ProductIssueRow struct {
+	ProductID        uuid.UUID
+	ProductName      string
+	ProductReference string
+	Quantity         int
+	IssuedQuantity   int64
+}
+
+type AbcAnalysisReq struct {
+	DateFrom   string  `json:"date_from"`
+	DateTo     string  `json:"date_to"`
+	ThresholdA float64 `json:"threshold_a"`
+	ThresholdB float64 `json:"threshold_b"`
+
+	//convert
+	From time.Time `json:"-"`
+	To   time.Time `json:"-"`
+}
+
+// Validate defaults to the last 365 days and the usual 80/95 cumulative percentage thresholds.
+func (req *AbcAnalysisReq) Validate() response.RespCode {
+	code := parsePeriod(&req.DateFrom, &req.DateTo, &req.From, &req.To, 365)
+	if code != response.OkCode {
+		return code
+	}
+
+	if req.ThresholdA == 0 {
+		req.ThresholdA = 80
+	}
+	if req.ThresholdB == 0 {
+		req.ThresholdB = 95
+	}
+	if req.ThresholdA <= 0 || req.ThresholdA >= req.ThresholdB || req.ThresholdB > 100 {
+		return response.ErrInvalidThreshold
+	}
+	return response.OkCode
+}
+
+type ProductConsumptionRow struct {
+	ProductID        uuid.UUID
+	ProductName      string
+	ProductReference string
+	ConsumptionValue int64
+}
+
+type AbcItem struct {
+	ProductID            string          `json:"product_id"`
+	ProductName          string          `json:"product_name"`
+	ProductReference     string          `json:"product_reference"`
+	ConsumptionValue     int64           `json:"consumption_value"`
+	Percentage           decimal.Decimal `json:"percentage"`
+	CumulativePercentage decimal.Decimal `json:"cumulative_percentage"`
+	Class                AbcClass        `json:"class"`
+}
+
+type AbcClassSummary struct {
+	Class            AbcClass        `json:"class"`
+	Products         int             `json:"products"`
+	ConsumptionValue int64           `json:"consumption_value"`
+	Percentage       decimal.Decimal `json:"percentage"`
+}
+
+type AbcAnalysisRp struct {
+	DateFrom   string            `json:"date_from"`
+	DateTo     string            `json:"date_to"`
+	ThresholdA float64           `json:"threshold_a"`
+	ThresholdB float64           `json:"threshold_b"`
+	TotalValue int64             `json:"total_value"`
+	Summary    []AbcClassSummary `json:"summary"`
+	Data       []AbcItem         `json:"data"`
+}
+
+// CacheCounter counts the lookups of a cache since the server started.
+type CacheCounter struct {
+	Hits    int64   `json:"hits"`
+	Misses  int64   `json:"misses"`
+	HitRate float64 `json:"hit_rate"`
+}
+
+// CacheStatsRp is the hit rate of the product caches. Errors are Redis failures, answered
+// from the database.
+type CacheStatsRp struct {
+	Product       CacheCounter `json:"product"`
+	ProductList   CacheCounter `json:"product_list"`
+	Invalidations int64        `json:"invalidations"`
+	Errors        int64        `json:"errors"`
+}
@@ -0,0 +1,163 @@
+package models
+
+import (
+	"errors"
+	"stock-management/pkgs/response"
+	"stock-management/pkgs/utils"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInsufficientStock is returned when a movement would make the quantity negative.
+var ErrInsufficientStock = errors.New("insufficient stock")
+
+type StockMovement struct {
+	MovementID    uuid.UUID         `gorm:"primaryKey;type:uuid;column:movement_id" json:"movement_id"`
+	ProductID     uuid.UUID         `gorm:"not null;type:uuid;index;column:product_id" json:"product_id"`
+	MovementType  StockMovementType `gorm:"not null;column:movement_type" json:"movement_type"`
+	Quantity      int               `gorm:"not null;column:quantity" json:"quantity"`
+	QuantityAfter int               `gorm:"not null;column:quantity_after" json:"quantity_after"`
+	Note          string            `gorm:"column:note" json:"note"`
+	CreatedAt     time.Time         `gorm:"not null;index;column:created_at" json:"created_at"`
+}
+
+func (m *StockMovement) TableName() string {
+	return "stock_movement"
+}
+
+// StockMovementType tells how the quantity of a movement is signed:
+// receipts are positive, issues are negative and adjustments may be either.
+type StockMovementType string
+
+const (
+	MovementReceipt    StockMovementType = "receipt"
+	MovementIssue      StockMovementType = "issue"
+	MovementAdjustment StockMovementType = "adjustment"
+)
+
+type StockMovementCreateReq struct {
+	ProductID    string            `json:"product_id"`
+	MovementType StockMovementType `json:"movement_type"`
+	Quantity     int               `json:"quantity"`
+	Note         string            `json:"note"`
+}
+
+func (req *StockMovementCreateReq) Validate() response.RespCode {
+	if req.ProductID == "" || !utils.IsValidUUID(req.ProductID) {
+		return response.ErrInvalidProduct
+	}
+	switch req.MovementType {
+	case MovementReceipt, MovementIssue:
+		if req.Quantity <= 0 {
+			return response.ErrInvalidQuantity
+		}
+	case MovementAdjustment:
+		if req.Quantity == 0 {
+			return response.ErrInvalidQuantity
+		}
+	default:
+		return response.ErrInvalidMovementType
+	}
+	return response.OkCode
+}
+
+// Delta returns the signed change applied to the product quantity.
+func (req *StockMovementCreateReq) Delta() int {
+	if req.MovementType == MovementIssue {
+		return -req.Quantity
+	}
+	return req.Quantity
+}
+
+type StockMovementSearchReq struct {
+	ProductIDs    []string            `form:"product_ids" json:"product_ids,omitempty"`
+	MovementTypes []StockMovementType `form:"movement_types" json:"movement_types,omitempty"`
+	DateFrom      string              `form:"date_from" json:"date_from,omitempty"`
+	DateTo        string              `form:"date_to" json:"date_to,omitempty"`
+	Pagination
+
+	//convert
+	ProductUUIDs []uuid.UUID `form:"-" json:"-"`
+}
+
+func (req *StockMovementSearchReq) Validate() response.RespCode {
+	if req.DateFrom != "" {
+		if err := validateDateFormat(req.DateFrom); err != nil {
+			return response.ErrInvalidDate
+		}
+	}
+	if req.DateTo != "" {
+		if err := validateDateFormat(req.DateTo); err != nil {
+			return response.ErrInvalidDate
+		}
+	}
+	var ok bool
+	if req.ProductUUIDs, ok = parseUUIDs(req.ProductIDs); !ok {
+		return response.ErrInvalidProduct
+	}
+	if req.Limit == 0 {
+		req.Limit = 20
+	}
+	return response.OkCode
+}
+
+type StockAnalyticsReq struct {
+	DateFrom string            `form:"date_from" json:"date_from"`
+	DateTo   string            `form:"date_to" json:"date_to"`
+	Days     int               `form:"days" json:"days"`
+	GroupBy  StatisticsGroupBy `form:"group_by" json:"group_by"`
+
+	//convert
+	From time.Time `form:"-" json:"-"`
+	To   time.Time `form:"-" json:"-"`
+}
+
+// ValidatePeriod checks the turnover request, defaulting to the last 30 days.
+func (req *StockAnalyticsReq) ValidatePeriod() response.RespCode {
+	code := parsePeriod(&req.DateFrom, &req.DateTo, &req.From, &req.To, 30)
+	if code != response.OkCode {
+		return code
+	}
+
+	if req.GroupBy == "" {
+		req.GroupBy = StatisticsGroupByCategory
+	}
+	if !req.GroupBy.IsValid() {
+		return response.ErrInvalidGroupBy
+	}
+	return response.OkCode
+}
+
+// ValidateDays checks the days-of-cover and dead stock requests.
+func (req *StockAnalyticsReq) ValidateDays(defaultDays int) response.RespCode {
+	if req.Days == 0 {
+		req.Days = defaultDays
+	}
+	if req.Days < 0 {
+		return response.ErrInvalidDays
+	}
+	return response.OkCode
+}
+
+// parsePeriod parses an optional date range ending today and spanning defaultDays by default,
+// writing the defaults back so responses show the period used.
+func parsePeriod(dateFrom, dateTo *string, from, to *time.Time, defaultDays int) response.RespCode {
+	var err error
+	if *dateTo == "" {
+		*to = time.Now()
+		*dateTo = to.Format(dateFormat)
+	} else if *to, err = time.Parse(dateFormat, *dateTo); err != nil {
+		return response.ErrInvalidDate
+	}
+	if *dateFrom == "" {
+		*from = to.AddDate(0, 0, 1-defaultDays)
+		*dateFrom = from.Format(dateFormat)
+	} else if *from, err = time.Parse(dateFormat, *dateFrom); err != nil {
+		return response.ErrInvalidDate
+	}
+	if from.After(*to) {
+		return response.ErrInvalidDate
+	}
+	return response.OkCode
+}
@@ -10,6 +10,8 @@ type Supplier struct {
 	SupplierID   uuid.UUID      `gorm:"primaryKey;type:uuid;column:supplier_id" json:"supplier_id"`
 	SupplierName string         `gorm:"not null;column:supplier_name" json:"supplier_name"`
 	Status       SupplierStatus `gorm:"not null;column:status" json:"status"`
+	LeadTimeDays int            `gorm:"not null;default:0;column:lead_time_days" json:"lead_time_days"`
+	Version      int            `gorm:"not null;default:1;column:version" json:"version"`
 }
 
 func (Supplier) TableName() string {
@@ -26,6 +28,7 @@ const (
 type SupplierCreateReq struct {
 	SupplierName string         `json:"supplier_name"`
 	Status       SupplierStatus `json:"status"`
+	LeadTimeDays int            `json:"lead_time_days"`
 }
 
 func (req *SupplierCreateReq) Validate() response.RespCode {
@@ -35,12 +38,15 @@ func (req *SupplierCreateReq) Validate() response.RespCode {
 	if req.Status == "" || (SupplierStatus(req.Status) != SupplierActive && SupplierStatus(req.Status) != SupplierInActive) {
 		return response.ErrInvalidStatus
 	}
+	if req.LeadTimeDays < 0 {
+		return response.ErrInvalidLeadTime
+	}
 	return response.OkCode
 }
 
 type SupplierSearchReq struct {
-	SupplierName string         `json:"supplier_name,omitempty"`
-	Status       SupplierStatus `json:"status,omitempty"`
+	SupplierName string         `form:"supplier_name" json:"supplier_name,omitempty"`
+	Status       SupplierStatus `form:"status" json:"status,omitempty"`
 	Pagination
 }
 
@@ -0,0 +1,154 @@
+package models
+
+import (
+	"stock-management/pkgs/response"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type User struct {
+	UserID       uuid.UUID  `gorm:"primaryKey;type:uuid;column:user_id" json:"user_id"`
+	Username     string     `gorm:"not null;uniqueIndex;column:username" json:"username"`
+	PasswordHash string     `gorm:"not null;column:password_hash" json:"-"`
+	Status       UserStatus `gorm:"not null;column:status" json:"status"`
+	CreatedAt    time.Time  `gorm:"not null;column:created_at" json:"created_at"`
+}
+
+func (u *User) TableName() string {
+	return "app_user"
+}
+
+type UserStatus string
+
+const (
+	UserActive   UserStatus = "active"
+	UserInActive UserStatus = "in active"
+)
+
+// RefreshToken stores the SHA-256 hash of an issued refresh token, never the token itself.
+type RefreshToken struct {
+	TokenID   uuid.UUID  `gorm:"primaryKey;type:uuid;column:token_id" json:"token_id"`
+	UserID    uuid.UUID  `gorm:"not null;type:uuid;index;column:user_id" json:"user_id"`
+	TokenHash string     `gorm:"not null;uniqueIndex;column:token_hash" json:"-"`
+	ExpiresAt time.Time  `gorm:"not null;column:expires_at" json:"expires_at"`
+	RevokedAt *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt time.Time  `gorm:"not null;column:created_at" json:"created_at"`
+}
+
+func (t *RefreshToken) TableName() string {
+	return "refresh_token"
+}
+
+// ApiKey stores the SHA-256 hash of a machine client key. Prefix is the start of the
+// key, kept so users can tell their keys apart.
+type ApiKey struct {
+	ApiKeyID   uuid.UUID  `gorm:"primaryKey;type:uuid;column:api_key_id" json:"api_key_id"`
+	UserID     uuid.UUID  `gorm:"not null;type:uuid;index;column:user_id" json:"user_id"`
+	Name       string     `gorm:"not null;column:name" json:"name"`
+	Prefix     string     `gorm:"not null;column:prefix" json:"prefix"`
+	KeyHash    string     `gorm:"not null;uniqueIndex;column:key_hash" json:"-"`
+	ExpiresAt  *time.Time `gorm:"column:expires_at" json:"expires_at"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at" json:"last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at" json:"revoked_at"`
+	CreatedAt  time.Time  `gorm:"not null;column:created_at" json:"created_at"`
+}
+
+func (k *ApiKey) TableName() string {
+	return "api_key"
+}
+
+// AuthUser is the authenticated caller of a request.
+type AuthUser struct {
+	UserID      uuid.UUID `json:"user_id"`
+	Username    string    `json:"username"`
+	AuthMethod  string    `json:"auth_method"`
+	Roles       []string  `json:"roles"`
+	Permissions []string  `json:"permissions"`
+}
+
+func (u *AuthUser) HasPermission(permission string) bool {
+	for _, p := range u.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
+
+const (
+	AuthMethodJWT    string = "jwt"
+	AuthMethodApiKey string = "api_key"
+
+	AuthUserKey string = "AuthUser"
+	TraceIDKey  string = "TraceID"
+)
+
+type LoginReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (req *LoginReq) Validate() response.RespCode {
+	if req.Username == "" || req.Password == "" {
+		return response.ErrInvalidCredentials
+	}
+	return response.OkCode
+}
+
+type RefreshTokenReq struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+func (req *RefreshTokenReq) Validate() response.RespCode {
+	if req.RefreshToken == "" {
+		return response.ErrInvalidToken
+	}
+	return response.OkCode
+}
+
+type TokenRp struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"`
+}
+
+type UserCreateReq struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+func (req *UserCreateReq) Validate() response.RespCode {
+	if req.Username == "" {
+		return response.ErrInvalidName
+	}
+	if len(req.Password) < 8 {
+		return response.ErrInvalidPassword
+	}
+	return response.OkCode
+}
+
+type ApiKeyCreateReq struct {
+	Name          string `json:"name"`
+	ExpiresInDays int    `json:"expires_in_days"`
+}
+
+func (req *ApiKeyCreateReq) Validate() response.RespCode {
+	if req.Name == "" {
+		return response.ErrInvalidName
+	}
+	if req.ExpiresInDays < 0 {
+		return response.ErrInvalidDays
+	}
+	return response.OkCode
+}
+
+type ApiKeyCreateRp struct {
+	ApiKey
+	Key string `json:"key"`
+}
+
+type ApiKeyRevokeReq struct {
+	ApiKeyID string `json:"api_key_id"`
+}
@@ -0,0 +1,188 @@
+package models
+
+import (
+	"errors"
+	"net/url"
+	"stock-management/pkgs/response"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var ErrWebhookNotFound = errors.New("invalid webhook")
+
+// WebhookSecretMinLength is the shortest secret accepted to sign deliveries.
+const WebhookSecretMinLength = 16
+
+// Webhook receives the domain events of the types it subscribes to as signed POST requests.
+type Webhook struct {
+	WebhookID uuid.UUID `gorm:"primaryKey;type:uuid;column:webhook_id" json:"webhook_id"`
+	URL       string    `gorm:"not null;column:url" json:"url"`
+	// Secret signs the deliveries. It is never returned.
+	Secret     string            `gorm:"not null;column:secret" json:"-"`
+	EventTypes []DomainEventType `gorm:"not null;type:jsonb;serializer:json;column:event_types" json:"event_types"`
+	Enabled    bool              `gorm:"not null;column:enabled" json:"enabled"`
+	CreatedAt  time.Time         `gorm:"not null;column:created_at" json:"created_at"`
+	UpdatedAt  time.Time         `gorm:"not null;column:updated_at" json:"updated_at"`
+}
+
+func (w *Webhook) TableName() string {
+	return "webhook"
+}
+
+// Subscribes tells whether the webhook receives events of type eventType.
+func (w *Webhook) Subscribes(eventType DomainEventType) bool {
+	for _, subscribed := range w.EventTypes {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is an event to POST to a webhook, with the outcome of its last attempt.
+// A webhook receives an event once: deliveries are unique per webhook and event.
+type WebhookDelivery struct {
+	DeliveryID uuid.UUID      `gorm:"primaryKey;type:uuid;column:delivery_id" json:"delivery_id"`
+	WebhookID  uuid.UUID      `gorm:"not null;type:uuid;uniqueIndex:idx_webhook_delivery_event;index:idx_webhook_delivery_webhook;column:webhook_id" json:"webhook_id"`
+	EventID    string         `gorm:"not null;uniqueIndex:idx_webhook_delivery_event;column:event_id" json:"event_id"`
+	EventType  string         `gorm:"not null;column:event_type" json:"event_type"`
+	Event      DomainEvent    `gorm:"not null;type:jsonb;serializer:json;column:event" json:"event"`
+	Status     DeliveryStatus `gorm:"not null;index:idx_webhook_delivery_due;column:status" json:"status"`
+	Attempts   int            `gorm:"not null;column:attempts" json:"attempts"`
+	// NextAttemptAt is when a pending delivery is sent next.
+	NextAttemptAt  time.Time  `gorm:"not null;index:idx_webhook_delivery_due;column:next_attempt_at" json:"next_attempt_at"`
+	LastAttemptAt  *time.Time `gorm:"column:last_attempt_at" json:"last_attempt_at"`
+	LastStatusCode int        `gorm:"not null;column:last_status_code" json:"last_status_code"`
+	LastError      string     `gorm:"not null;column:last_error" json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `gorm:"column:delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time  `gorm:"not null;index:idx_webhook_delivery_webhook;column:created_at" json:"created_at"`
+}
+
+func (d *WebhookDelivery) TableName() string {
+	return "webhook_delivery"
+}
+
+type DeliveryStatus string
+
+const (
+	// DeliveryPending is waiting for its first attempt or a retry.
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliveryDelivered DeliveryStatus = "delivered"
+	// DeliveryDead failed every attempt and is kept in the dead-letter list until redelivered.
+	DeliveryDead DeliveryStatus = "dead"
+)
+
+func (s DeliveryStatus) IsValid() bool {
+	return s == DeliveryPending || s == DeliveryDelivered || s == DeliveryDead
+}
+
+type WebhookCreateReq struct {
+	URL        string            `json:"url"`
+	Secret     string            `json:"secret"`
+	EventTypes []DomainEventType `json:"event_types"`
+	Enabled    bool              `json:"enabled"`
+}
+
+func (req *WebhookCreateReq) Validate() response.RespCode {
+	if len(req.Secret) < WebhookSecretMinLength {
+		return response.ErrInvalidWebhookSecret
+	}
+	return req.validate()
+}
+
+func (req *WebhookCreateReq) validate() response.RespCode {
+	req.URL = strings.TrimSpace(req.URL)
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return response.ErrInvalidWebhookUrl
+	}
+	if len(req.EventTypes) == 0 {
+		return response.ErrInvalidEventType
+	}
+	for _, eventType := range req.EventTypes {
+		if !eventType.IsValid() {
+			return response.ErrInvalidEventType
+		}
+	}
+	return response.OkCode
+}
+
+// WebhookUpdateReq replaces a webhook. An empty secret keeps the current one.
+type WebhookUpdateReq struct {
+	WebhookID string `json:"webhook_id"`
+	WebhookCreateReq
+
+	//convert
+	WebhookUUID uuid.UUID `json:"-"`
+}
+
+func (req *WebhookUpdateReq) Validate() response.RespCode {
+	var err error
+	if req.WebhookUUID, err = uuid.Parse(req.WebhookID); err != nil {
+		return response.ErrInvalidWebhook
+	}
+	if req.Secret != "" && len(req.Secret) < WebhookSecretMinLength {
+		return response.ErrInvalidWebhookSecret
+	}
+	return req.validate()
+}
+
+type WebhookIdReq struct {
+	WebhookID string `json:"webhook_id"`
+}
+
+type WebhookDeliverySearchReq struct {
+	WebhookID string         `json:"webhook_id,omitempty"`
+	Status    DeliveryStatus `json:"status,omitempty"`
+	Pagination
+
+	//convert
+	WebhookUUID *uuid.UUID `json:"-"`
+}
+
+func (req *WebhookDeliverySearchReq) Validate() response.RespCode {
+	if req.WebhookID != "" {
+		id, err := uuid.Parse(req.WebhookID)
+		if err != nil {
+			return response.ErrInvalidWebhook
+		}
+		req.WebhookUUID = &id
+	}
+	if req.Status != "" && !req.Status.IsValid() {
+		return response.ErrInvalidStatus
+	}
+	if req.Limit <= 0 {
+		req.Limit = 20
+	}
+	return response.OkCode
+}
+
+// WebhookRedeliverReq queues dead deliveries again, with their attempts reset.
+type WebhookRedeliverReq struct {
+	DeliveryIDs []string `json:"delivery_ids"`
+
+	//convert
+	DeliveryUUIDs []uuid.UUID `json:"-"`
+}
+
+func (req *WebhookRedeliverReq) Validate() response.RespCode {
+	if len(req.DeliveryIDs) == 0 {
+		return response.ErrInvalidDeliveryID
+	}
+	req.DeliveryUUIDs = make([]uuid.UUID, 0, len(req.DeliveryIDs))
+	for _, id := range req.DeliveryIDs {
+		deliveryID, err := uuid.Parse(id)
+		if err != nil {
+			return response.ErrInvalidDeliveryID
+		}
+		req.DeliveryUUIDs = append(req.DeliveryUUIDs, deliveryID)
+	}
+	return response.OkCode
+}
+
+// WebhookRedeliverRp is the number of dead deliveries queued again.
+type WebhookRedeliverRp struct {
+	Redelivered int64 `json:"redelivered"`
+}
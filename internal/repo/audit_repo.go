@@ -0,0 +1,104 @@
+package repo
+
+import (
+	"context"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuditRepo interface {
+	GetAuditList(ctx context.Context, req models.AuditSearchReq) ([]models.AuditLog, int, error)
+}
+
+type auditRepo struct {
+	pdb *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) AuditRepo {
+	return &auditRepo{
+		pdb: db,
+	}
+}
+
+func (ar *auditRepo) GetAuditList(ctx context.Context, req models.AuditSearchReq) ([]models.AuditLog, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := ar.pdb.WithContext(ctx).Model(&models.AuditLog{})
+	if req.EntityType != "" {
+		q = q.Where("entity_type = ?", req.EntityType)
+	}
+	if req.EntityID != "" {
+		q = q.Where("entity_id = ?", req.EntityID)
+	}
+	if req.ActorID != "" {
+		q = q.Where("actor_id = ?", req.ActorID)
+	}
+	if req.ActorName != "" {
+		q = q.Where("actor_name = ?", req.ActorName)
+	}
+	if req.DateFrom != "" {
+		q = q.Where("created_at >= ?", req.DateFrom)
+	}
+	if req.DateTo != "" {
+		q = q.Where("created_at < CAST(? AS date) + 1", req.DateTo)
+	}
+
+	var totalCount int64
+	if err := q.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AuditLog
+	if err := q.Order("created_at desc").Offset(req.Offset).Limit(req.Limit).Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	nextOffset := req.Offset + req.Limit
+	if nextOffset >= int(totalCount) {
+		nextOffset = 0
+	}
+	return logs, nextOffset, nil
+}
+
+// writeAuditLog records a write in the audit log using tx, so that the entry is committed or
+// rolled back together with the write. The actor and trace ID are taken from the request context.
+func writeAuditLog(ctx context.Context, tx *gorm.DB, entityType models.AuditEntity, entityID uuid.UUID, action models.AuditAction, before, after interface{}) error {
+	log, err := newAuditLog(ctx, entityType, entityID, action, before, after)
+	if err != nil || log == nil {
+		return err
+	}
+	return tx.WithContext(ctx).Create(log).Error
+}
+
+// newAuditLog returns the audit entry of a write, or nil for an update that changed nothing.
+func newAuditLog(ctx context.Context, entityType models.AuditEntity, entityID uuid.UUID, action models.AuditAction, before, after interface{}) (*models.AuditLog, error) {
+	changes, err := models.DiffAudit(before, after)
+	if err != nil {
+		return nil, err
+	}
+	if action == models.AuditUpdate && len(changes) == 0 {
+		return nil, nil
+	}
+
+	log := models.AuditLog{
+		AuditID:    uuid.New(),
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		ActorName:  models.AuditActorSystem,
+		Changes:    changes,
+		CreatedAt:  time.Now().UTC(),
+	}
+	if user, ok := ctx.Value(models.AuthUserKey).(*models.AuthUser); ok && user != nil {
+		log.ActorID = &user.UserID
+		log.ActorName = user.Username
+	}
+	if traceID, ok := ctx.Value(models.TraceIDKey).(string); ok {
+		log.TraceID = traceID
+	}
+	return &log, nil
+}
@@ -0,0 +1,122 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type AuthRepo interface {
+	CreateRefreshToken(ctx context.Context, token models.RefreshToken) error
+	GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error)
+	RevokeRefreshToken(ctx context.Context, id uuid.UUID) (bool, error)
+	CreateApiKey(ctx context.Context, key models.ApiKey) error
+	GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error)
+	GetApiKeyList(ctx context.Context, userID uuid.UUID) ([]models.ApiKey, error)
+	RevokeApiKey(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	TouchApiKey(ctx context.Context, id uuid.UUID) error
+}
+
+type authRepo struct {
+	pdb *gorm.DB
+}
+
+func NewAuthRepo(db *gorm.DB) AuthRepo {
+	return &authRepo{
+		pdb: db,
+	}
+}
+
+func (ar *authRepo) CreateRefreshToken(ctx context.Context, token models.RefreshToken) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return ar.pdb.WithContext(ctx).Create(&token).Error
+}
+
+func (ar *authRepo) GetRefreshTokenByHash(ctx context.Context, hash string) (*models.RefreshToken, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var token models.RefreshToken
+	if err := ar.pdb.WithContext(ctx).First(&token, "token_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &token, nil
+}
+
+// RevokeRefreshToken reports whether the token was still active, so a token can only be
+// exchanged once even under concurrent refresh requests.
+func (ar *authRepo) RevokeRefreshToken(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rs := ar.pdb.WithContext(ctx).Model(&models.RefreshToken{}).
+		Where("token_id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", time.Now().UTC())
+	if rs.Error != nil {
+		return false, rs.Error
+	}
+	return rs.RowsAffected > 0, nil
+}
+
+func (ar *authRepo) CreateApiKey(ctx context.Context, key models.ApiKey) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return ar.pdb.WithContext(ctx).Create(&key).Error
+}
+
+func (ar *authRepo) GetApiKeyByHash(ctx context.Context, hash string) (*models.ApiKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var key models.ApiKey
+	if err := ar.pdb.WithContext(ctx).First(&key, "key_hash = ?", hash).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &key, nil
+}
+
+func (ar *authRepo) GetApiKeyList(ctx context.Context, userID uuid.UUID) ([]models.ApiKey, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	keys := []models.ApiKey{}
+	if err := ar.pdb.WithContext(ctx).Where("user_id = ?", userID).Order("created_at desc").Find(&keys).Error; err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+func (ar *authRepo) RevokeApiKey(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rs := ar.pdb.WithContext(ctx).Model(&models.ApiKey{}).
+		Where("api_key_id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", time.Now().UTC())
+	if rs.Error != nil {
+		return false, rs.Error
+	}
+	return rs.RowsAffected > 0, nil
+}
+
+func (ar *authRepo) TouchApiKey(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return ar.pdb.WithContext(ctx).Model(&models.ApiKey{}).
+		Where("api_key_id = ?", id).
+		Update("last_used_at", time.Now().UTC()).Error
+}
@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"stock-management/internal/models"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// eventScanBatches bounds the stream entries read for one page of events filtered by type.
+const eventScanBatches = 10
+
+// appendOnce appends the (type, event) pairs of ARGV[3:] to the stream KEYS[2], trimmed to about
+// ARGV[2] entries unless it is 0, unless KEYS[1], the streamed marker of the outbox event, is
+// already set.
+var appendOnce = redis.NewScript(`
+if not redis.call('SET', KEYS[1], '1', 'NX', 'EX', ARGV[1]) then
+	return 0
+end
+for i = 3, #ARGV, 2 do
+	if ARGV[2] == '0' then
+		redis.call('XADD', KEYS[2], '*', 'type', ARGV[i], 'event', ARGV[i + 1])
+	else
+		redis.call('XADD', KEYS[2], 'MAXLEN', '~', ARGV[2], '*', 'type', ARGV[i], 'event', ARGV[i + 1])
+	end
+end
+return 1
+`)
+
+type EventRepo interface {
+	AppendOnce(ctx context.Context, eventID int64, events []models.DomainEvent) error
+	GetEvents(ctx context.Context, req models.EventSearchReq) (*models.EventListRp, error)
+	GetGroups(ctx context.Context) ([]models.EventGroup, error)
+	CreateGroup(ctx context.Context, name, from string) error
+	SetGroupPosition(ctx context.Context, name, from string) error
+}
+
+type eventRepo struct {
+	cache  *redis.Client
+	stream string
+	maxLen int64
+}
+
+// NewEventRepo reads and writes the event stream at key stream, trimmed to about maxLen entries.
+// A maxLen of 0 keeps every entry.
+func NewEventRepo(redis *redis.Client, stream string, maxLen int64) EventRepo {
+	return &eventRepo{
+		cache:  redis,
+		stream: stream,
+		maxLen: maxLen,
+	}
+}
+
+// AppendOnce appends the events derived from an outbox event to the stream atomically, unless
+// they were already appended.
+func (er *eventRepo) AppendOnce(ctx context.Context, eventID int64, events []models.DomainEvent) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	keys := []string{fmt.Sprintf(models.OutboxStreamedKey, eventID), er.stream}
+	args := []interface{}{int64(outboxAppliedTTL / time.Second), er.maxLen}
+	for _, event := range events {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		args = append(args, string(event.Type), data)
+	}
+	return appendOnce.Run(ctx, er.cache, keys, args...).Err()
+}
+
+// GetEvents reads the stream from req.From. With types, the entries of other types are skipped;
+// a page can then hold fewer events than the limit while Next is still set.
+func (er *eventRepo) GetEvents(ctx context.Context, req models.EventSearchReq) (*models.EventListRp, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	types := make(map[string]bool, len(req.Types))
+	for _, eventType := range req.Types {
+		types[string(eventType)] = true
+	}
+
+	rp := &models.EventListRp{Events: []models.StreamEvent{}}
+	start := req.From
+	for batch := 0; batch < eventScanBatches; batch++ {
+		messages, err := er.cache.XRangeN(ctx, er.stream, start, "+", int64(req.Limit)).Result()
+		if err != nil {
+			return nil, err
+		}
+		for _, message := range messages {
+			rp.Next = models.NextStreamID(message.ID)
+			if eventType, _ := message.Values["type"].(string); len(types) > 0 && !types[eventType] {
+				continue
+			}
+			data, _ := message.Values["event"].(string)
+			var event models.DomainEvent
+			if err := json.Unmarshal([]byte(data), &event); err != nil {
+				return nil, fmt.Errorf("decode event %s: %w", message.ID, err)
+			}
+			rp.Events = append(rp.Events, models.StreamEvent{StreamID: message.ID, Event: event})
+			if len(rp.Events) == req.Limit {
+				return rp, nil
+			}
+		}
+		if len(messages) < req.Limit {
+			rp.Next = ""
+			return rp, nil
+		}
+		start = rp.Next
+	}
+	return rp, nil
+}
+
+func (er *eventRepo) GetGroups(ctx context.Context) ([]models.EventGroup, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	infos, err := er.cache.XInfoGroups(ctx, er.stream).Result()
+	if err != nil {
+		// The stream is created by the first event or group.
+		if strings.Contains(err.Error(), "no such key") {
+			return []models.EventGroup{}, nil
+		}
+		return nil, err
+	}
+
+	groups := make([]models.EventGroup, 0, len(infos))
+	for _, info := range infos {
+		groups = append(groups, models.EventGroup{
+			Name:            info.Name,
+			Consumers:       info.Consumers,
+			Pending:         info.Pending,
+			LastDeliveredID: info.LastDeliveredID,
+			Lag:             info.Lag,
+		})
+	}
+	return groups, nil
+}
+
+// CreateGroup creates a consumer group that delivers the events after from.
+func (er *eventRepo) CreateGroup(ctx context.Context, name, from string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := er.cache.XGroupCreateMkStream(ctx, er.stream, name, from).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return models.ErrEventGroupExists
+	}
+	return err
+}
+
+// SetGroupPosition makes a consumer group deliver the events after from, whether it is before
+// or after its current position. Pending events stay pending.
+func (er *eventRepo) SetGroupPosition(ctx context.Context, name, from string) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := er.cache.XGroupSetID(ctx, er.stream, name, from).Err()
+	if err != nil && (strings.HasPrefix(err.Error(), "NOGROUP") || strings.Contains(err.Error(), "requires the key to exist")) {
+		return models.ErrEventGroupNotFound
+	}
+	return err
+}
@@ -0,0 +1,116 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"stock-management/internal/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type InventorySnapshotRepo interface {
+	CreateSnapshot(ctx context.Context, date time.Time) (int64, error)
+	GetTimeseries(ctx context.Context, req models.TimeseriesReq) ([]models.TimeseriesRow, error)
+	GetAverageInventoryPerGroup(ctx context.Context, groupBy models.StatisticsGroupBy, from, to time.Time) ([]models.GroupTotalRow, error)
+}
+
+type inventorySnapshotRepo struct {
+	pdb *gorm.DB
+}
+
+func NewInventorySnapshotRepo(db *gorm.DB) InventorySnapshotRepo {
+	return &inventorySnapshotRepo{
+		pdb: db,
+	}
+}
+
+var statisticsGroupColumns = map[models.StatisticsGroupBy]string{
+	models.StatisticsGroupByProduct:  "product_id",
+	models.StatisticsGroupByCategory: "product_category_id",
+	models.StatisticsGroupBySupplier: "supplier_id",
+}
+
+// CreateSnapshot stores the current quantity and value of every product for the given date,
+// overwriting the snapshot of that date if the job already ran.
+func (sr *inventorySnapshotRepo) CreateSnapshot(ctx context.Context, date time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	rs := sr.pdb.WithContext(ctx).Exec(`
+		INSERT INTO inventory_snapshot
+			(snapshot_id, snapshot_date, product_id, product_category_id, supplier_id, quantity, price, value, created_at)
+		SELECT uuid_generate_v4(), ?, product_id, product_category_id, supplier_id, quantity, price, CAST(price AS BIGINT) * quantity, ?
+		FROM product
+		ON CONFLICT (snapshot_date, product_id) DO UPDATE SET
+			product_category_id = EXCLUDED.product_category_id,
+			supplier_id = EXCLUDED.supplier_id,
+			quantity = EXCLUDED.quantity,
+			price = EXCLUDED.price,
+			value = EXCLUDED.value,
+			created_at = EXCLUDED.created_at`,
+		date.Format("2006-01-02"), time.Now().UTC(),
+	)
+	if rs.Error != nil {
+		return 0, rs.Error
+	}
+	return rs.RowsAffected, nil
+}
+
+// GetTimeseries sums the snapshots per group and day, then keeps the last day of every bucket
+// so week and month points show the closing stock of the period.
+func (sr *inventorySnapshotRepo) GetTimeseries(ctx context.Context, req models.TimeseriesReq) ([]models.TimeseriesRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	groupColumn, ok := statisticsGroupColumns[req.GroupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid group by %s", req.GroupBy)
+	}
+
+	daily := sr.pdb.WithContext(ctx).Model(&models.InventorySnapshot{}).
+		Select(fmt.Sprintf("%s AS group_id, snapshot_date, SUM(quantity) AS quantity, SUM(value) AS value", groupColumn)).
+		Where("snapshot_date BETWEEN ? AND ?", req.From.Format("2006-01-02"), req.To.Format("2006-01-02")).
+		Group(groupColumn + ", snapshot_date")
+	if len(req.UUIDs) > 0 {
+		daily = daily.Where(groupColumn+" IN (?)", req.UUIDs)
+	}
+
+	bucketExpr := fmt.Sprintf("date_trunc('%s', snapshot_date)", req.Bucket)
+	var rows []models.TimeseriesRow
+	err := sr.pdb.WithContext(ctx).
+		Table("(?) AS daily", daily).
+		Select(fmt.Sprintf("DISTINCT ON (group_id, %s) group_id, %s AS bucket, quantity, value", bucketExpr, bucketExpr)).
+		Order(fmt.Sprintf("group_id, %s, snapshot_date DESC", bucketExpr)).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (sr *inventorySnapshotRepo) GetAverageInventoryPerGroup(ctx context.Context, groupBy models.StatisticsGroupBy, from, to time.Time) ([]models.GroupTotalRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	groupColumn, ok := statisticsGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid group by %s", groupBy)
+	}
+
+	daily := sr.pdb.WithContext(ctx).Model(&models.InventorySnapshot{}).
+		Select(fmt.Sprintf("%s AS group_id, snapshot_date, SUM(quantity) AS quantity, SUM(value) AS value", groupColumn)).
+		Where("snapshot_date BETWEEN ? AND ?", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group(groupColumn + ", snapshot_date")
+
+	var rows []models.GroupTotalRow
+	err := sr.pdb.WithContext(ctx).
+		Table("(?) AS daily", daily).
+		Select("group_id, CAST(AVG(quantity) AS BIGINT) AS quantity, AVG(value) AS value").
+		Group("group_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,164 @@
+package repo
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// outboxRelayLock is the Postgres advisory lock held by the relay, so that a single instance
+// publishes and events of an entity go out in the order they were written.
+const outboxRelayLock = 7240431
+
+// outboxAppliedTTL is how long an applied event is remembered. The relay retries an event
+// within seconds, so this only needs to outlive a crashed relay.
+const outboxAppliedTTL = 7 * 24 * time.Hour
+
+// incrementOnce applies the increments of KEYS[2:] unless KEYS[1], the applied marker of the
+// event, is already set.
+var incrementOnce = redis.NewScript(`
+if not redis.call('SET', KEYS[1], '1', 'NX', 'EX', ARGV[1]) then
+	return 0
+end
+for i = 2, #KEYS do
+	redis.call('INCRBY', KEYS[i], ARGV[i])
+end
+return 1
+`)
+
+type OutboxRepo interface {
+	RelayEvents(ctx context.Context, limit, maxAttempts int, publish func(ctx context.Context, event models.OutboxEvent) error) (int, error)
+	IncrementOnce(ctx context.Context, eventID int64, increments map[string]int64) error
+	DeletePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+type outboxRepo struct {
+	pdb   *gorm.DB
+	cache *redis.Client
+}
+
+func NewOutboxRepo(db *gorm.DB, redis *redis.Client) OutboxRepo {
+	return &outboxRepo{
+		pdb:   db,
+		cache: redis,
+	}
+}
+
+// RelayEvents publishes up to limit unpublished events, oldest first, and marks them published.
+// It stops at the first event that fails, which is retried on the next call, and returns the
+// number of events published. An event failing its maxAttempts-th attempt is marked failed and
+// skipped instead, so that it does not hold back every later event; its error is returned. Nothing
+// is published while another instance holds the relay.
+func (or *outboxRepo) RelayEvents(ctx context.Context, limit, maxAttempts int, publish func(ctx context.Context, event models.OutboxEvent) error) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	published := 0
+	var publishErrs []error
+	err := or.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var locked bool
+		if err := tx.Raw("SELECT pg_try_advisory_xact_lock(?)", outboxRelayLock).Scan(&locked).Error; err != nil {
+			return err
+		}
+		if !locked {
+			return nil
+		}
+
+		var events []models.OutboxEvent
+		err := tx.Where("published_at IS NULL AND failed_at IS NULL").Order("event_id").Limit(limit).Find(&events).Error
+		if err != nil {
+			return err
+		}
+
+		for _, event := range events {
+			if err := publish(ctx, event); err != nil {
+				updates := map[string]interface{}{"attempts": gorm.Expr("attempts + 1"), "last_error": err.Error()}
+				if maxAttempts <= 0 || event.Attempts+1 < maxAttempts {
+					publishErrs = append(publishErrs, fmt.Errorf("publish outbox event %d: %w", event.EventID, err))
+					// Commit the events published so far along with the failure.
+					return tx.Model(&models.OutboxEvent{}).Where("event_id = ?", event.EventID).Updates(updates).Error
+				}
+
+				publishErrs = append(publishErrs, fmt.Errorf("outbox event %d failed after %d attempts: %w", event.EventID, event.Attempts+1, err))
+				updates["failed_at"] = time.Now().UTC()
+				if err := tx.Model(&models.OutboxEvent{}).Where("event_id = ?", event.EventID).Updates(updates).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			err := tx.Model(&models.OutboxEvent{}).Where("event_id = ?", event.EventID).
+				Updates(map[string]interface{}{"published_at": time.Now().UTC(), "attempts": gorm.Expr("attempts + 1")}).Error
+			if err != nil {
+				return err
+			}
+			published++
+		}
+		return nil
+	})
+	if err != nil {
+		return published, err
+	}
+	return published, errors.Join(publishErrs...)
+}
+
+// IncrementOnce applies the increments of an event to Redis atomically, unless they were
+// already applied.
+func (or *outboxRepo) IncrementOnce(ctx context.Context, eventID int64, increments map[string]int64) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	keys := []string{fmt.Sprintf(models.OutboxAppliedKey, eventID)}
+	args := []interface{}{int64(outboxAppliedTTL / time.Second)}
+	for key, increment := range increments {
+		keys = append(keys, key)
+		args = append(args, increment)
+	}
+	return incrementOnce.Run(ctx, or.cache, keys, args...).Err()
+}
+
+func (or *outboxRepo) DeletePublished(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	result := or.pdb.WithContext(ctx).Where("published_at < ?", before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// writeOutboxEvent records a write in the outbox within its transaction. before is nil on
+// creation and after on deletion.
+func writeOutboxEvent(ctx context.Context, tx *gorm.DB, eventType models.OutboxEventType, entityID uuid.UUID, before, after interface{}) error {
+	event, err := newOutboxEvent(eventType, entityID, before, after)
+	if err != nil {
+		return err
+	}
+	return tx.WithContext(ctx).Create(&event).Error
+}
+
+func newOutboxEvent(eventType models.OutboxEventType, entityID uuid.UUID, before, after interface{}) (models.OutboxEvent, error) {
+	var change models.OutboxChange
+	var err error
+	if before != nil {
+		if change.Before, err = json.Marshal(before); err != nil {
+			return models.OutboxEvent{}, err
+		}
+	}
+	if after != nil {
+		if change.After, err = json.Marshal(after); err != nil {
+			return models.OutboxEvent{}, err
+		}
+	}
+
+	return models.OutboxEvent{
+		EventType: eventType,
+		EntityID:  entityID,
+		Change:    change,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
@@ -0,0 +1,257 @@
+package repo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"stock-management/internal/models"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// ProductCache is a read-through Redis cache of products and product list pages. Concurrent
+// misses of a key share one database load. Redis failures fall back to the database.
+//
+// Writers invalidate after their commit: a product is deleted from the cache, and list pages
+// are dropped all at once by moving to a new generation of keys; the old ones expire. A load
+// that read the database before a commit and stores after its invalidation stays stale for
+// at most its TTL.
+type ProductCache struct {
+	rdb     *redis.Client
+	ttl     time.Duration
+	listTTL time.Duration
+	group   singleflight.Group
+
+	productHits, productMisses atomic.Int64
+	listHits, listMisses       atomic.Int64
+	invalidations, failures    atomic.Int64
+}
+
+// NewProductCache caches products for ttl and list pages for listTTL. A zero TTL disables that
+// cache.
+func NewProductCache(rdb *redis.Client, ttl, listTTL time.Duration) *ProductCache {
+	return &ProductCache{
+		rdb:     rdb,
+		ttl:     ttl,
+		listTTL: listTTL,
+	}
+}
+
+// productListPage is the cached result of GetProductList.
+type productListPage struct {
+	Products []models.Product `json:"products"`
+	Total    int64            `json:"total"`
+	HasMore  bool             `json:"has_more"`
+}
+
+// getProduct returns the cached product, or loads and caches it. Missing products are cached
+// too, as null.
+func (pc *ProductCache) getProduct(ctx context.Context, id uuid.UUID, load func(ctx context.Context) (*models.Product, error)) (*models.Product, error) {
+	if pc == nil || pc.ttl <= 0 {
+		return load(ctx)
+	}
+
+	key := fmt.Sprintf(models.ProductCacheKey, id)
+	var product *models.Product
+	if pc.get(ctx, key, &product) {
+		pc.productHits.Add(1)
+		return product, nil
+	}
+	pc.productMisses.Add(1)
+
+	v, err, _ := pc.group.Do(key, func() (interface{}, error) {
+		ctx, cancel := detach(ctx)
+		defer cancel()
+		product, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pc.set(ctx, key, product, pc.ttl)
+		return product, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if product = v.(*models.Product); product == nil {
+		return nil, nil
+	}
+	// Callers may change the product, which is shared with the other waiters.
+	copied := *product
+	return &copied, nil
+}
+
+// getProductList returns the cached page of req, or loads and caches it. Unpaginated loads of the
+// whole catalog, such as planning and scheduled reports, are not cached.
+func (pc *ProductCache) getProductList(ctx context.Context, req models.ProductSearchReq, load func(ctx context.Context) (productListPage, error)) (productListPage, error) {
+	if pc == nil || pc.listTTL <= 0 || req.Limit <= 0 {
+		return load(ctx)
+	}
+
+	gen, err := pc.rdb.Get(ctx, models.ProductListCacheGenKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		pc.failures.Add(1)
+		return load(ctx)
+	}
+	key := fmt.Sprintf(models.ProductListCacheKey, gen, productListHash(req))
+
+	var page productListPage
+	if pc.get(ctx, key, &page) {
+		pc.listHits.Add(1)
+		return page, nil
+	}
+	pc.listMisses.Add(1)
+
+	v, err, _ := pc.group.Do(key, func() (interface{}, error) {
+		ctx, cancel := detach(ctx)
+		defer cancel()
+		page, err := load(ctx)
+		if err != nil {
+			return nil, err
+		}
+		pc.set(ctx, key, page, pc.listTTL)
+		return page, nil
+	})
+	if err != nil {
+		return productListPage{}, err
+	}
+	page = v.(productListPage)
+	page.Products = append([]models.Product(nil), page.Products...)
+	return page, nil
+}
+
+// invalidate drops the cached products of ids and every cached list page. It is called after
+// the write is committed.
+func (pc *ProductCache) invalidate(ctx context.Context, ids ...uuid.UUID) {
+	if pc == nil || (pc.ttl <= 0 && pc.listTTL <= 0) {
+		return
+	}
+	pc.invalidations.Add(1)
+
+	pipe := pc.rdb.Pipeline()
+	for _, id := range ids {
+		pipe.Del(ctx, fmt.Sprintf(models.ProductCacheKey, id))
+	}
+	pipe.Incr(ctx, models.ProductListCacheGenKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		pc.failures.Add(1)
+	}
+}
+
+// Stats returns the hit rates since the server started.
+func (pc *ProductCache) Stats() models.CacheStatsRp {
+	if pc == nil {
+		return models.CacheStatsRp{}
+	}
+	return models.CacheStatsRp{
+		Product:       cacheCounter(pc.productHits.Load(), pc.productMisses.Load()),
+		ProductList:   cacheCounter(pc.listHits.Load(), pc.listMisses.Load()),
+		Invalidations: pc.invalidations.Load(),
+		Errors:        pc.failures.Load(),
+	}
+}
+
+func (pc *ProductCache) get(ctx context.Context, key string, v interface{}) bool {
+	data, err := pc.rdb.Get(ctx, key).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			pc.failures.Add(1)
+		}
+		return false
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		pc.failures.Add(1)
+		return false
+	}
+	return true
+}
+
+func (pc *ProductCache) set(ctx context.Context, key string, v interface{}, ttl time.Duration) {
+	data, err := json.Marshal(v)
+	if err == nil {
+		err = pc.rdb.Set(ctx, key, data, ttl).Err()
+	}
+	if err != nil {
+		pc.failures.Add(1)
+	}
+}
+
+func cacheCounter(hits, misses int64) models.CacheCounter {
+	counter := models.CacheCounter{Hits: hits, Misses: misses}
+	if total := hits + misses; total > 0 {
+		counter.HitRate = float64(hits) / float64(total)
+	}
+	return counter
+}
+
+// detach keeps a shared load running when the request that started it is canceled, since
+// other requests wait for it.
+func detach(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+}
+
+// productListHash identifies the page of a search. Lists of values are sorted so that the same
+// filters in another order share the page.
+func productListHash(req models.ProductSearchReq) string {
+	key := struct {
+		Query           string      `json:"q"`
+		Filter          string      `json:"f"`
+		References      []string    `json:"r"`
+		Names           []string    `json:"n"`
+		Status          []string    `json:"s"`
+		Categories      []uuid.UUID `json:"c"`
+		Suppliers       []uuid.UUID `json:"p"`
+		PriceFrom       int         `json:"pf"`
+		PriceTo         int         `json:"pt"`
+		StockLocations  []string    `json:"l"`
+		AbcClasses      []string    `json:"a"`
+		DateCreatedFrom string      `json:"df"`
+		DateCreatedTo   string      `json:"dt"`
+		Sort            string      `json:"o"`
+		Cursor          string      `json:"k"`
+		Limit           int         `json:"li"`
+		Offset          int         `json:"of"`
+	}{
+		Query:           req.Query,
+		Filter:          req.Filter,
+		References:      sortedStrings(req.ProductReferences),
+		Names:           sortedStrings(req.ProductNames),
+		Status:          sortedStrings(req.Status),
+		Categories:      sortedUUIDs(req.ProductCategoryUUIDs),
+		Suppliers:       sortedUUIDs(req.SupplierUUIDs),
+		PriceFrom:       req.PriceFrom,
+		PriceTo:         req.PriceTo,
+		StockLocations:  sortedStrings(req.StockLocations),
+		AbcClasses:      sortedStrings(req.AbcClasses),
+		DateCreatedFrom: req.DateCreatedFrom,
+		DateCreatedTo:   req.DateCreatedTo,
+		Sort:            req.Sort,
+		Cursor:          req.Cursor,
+		Limit:           req.Limit,
+		Offset:          req.Offset,
+	}
+	data, _ := json.Marshal(key)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func sortedStrings(values []string) []string {
+	sorted := append([]string(nil), values...)
+	sort.Strings(sorted)
+	return sorted
+}
+
+func sortedUUIDs(values []uuid.UUID) []uuid.UUID {
+	sorted := append([]uuid.UUID(nil), values...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].String() < sorted[j].String()
+	})
+	return sorted
+}
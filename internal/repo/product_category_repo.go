@@ -82,8 +82,18 @@ func (cr *productCategoryRepo) CreateProductCategory(ctx context.Context, req mo
 		Status:              models.ProductCategoryStatus(req.Status),
 		CreatedAt:           time.Now().Format("2006-01-02"),
 		UpdatedAt:           time.Now().Format("2006-01-02"),
+		Version:             1,
 	}
-	if err := cr.pdb.WithContext(ctx).Create(&productCategory).Error; err != nil {
+	err := cr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&productCategory).Error; err != nil {
+			return err
+		}
+		if err := writeAuditLog(ctx, tx, models.AuditEntityCategory, productCategory.ProductCategoryID, models.AuditCreate, nil, productCategory); err != nil {
+			return err
+		}
+		return writeOutboxEvent(ctx, tx, models.OutboxCategoryCreated, productCategory.ProductCategoryID, nil, productCategory)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &productCategory, nil
@@ -2,9 +2,11 @@ package repo
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"stock-management/internal/models"
+	"stock-management/pkgs/response"
 	"stock-management/pkgs/utils"
 	"strconv"
 	"strings"
@@ -12,29 +14,40 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/redis/go-redis/v9"
-	"github.com/shopspring/decimal"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepo interface {
 	GetProduct(ctx context.Context, id uuid.UUID) (*models.Product, error)
-	GetProductList(ctx context.Context, req models.ProductSearchReq) ([]models.Product, int, error)
+	GetProductsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Product, error)
+	GetProductList(ctx context.Context, req models.ProductSearchReq) ([]models.Product, int64, bool, error)
+	GetProductFacets(ctx context.Context, req models.ProductSearchReq, priceBounds []int) ([]models.FacetRow, error)
 	CreateProduct(ctx context.Context, product models.ProductCreateReq) (models.Product, error)
 	UpdateProduct(ctx context.Context, product models.ProductUpdateReq) (models.Product, error)
-	GetProductPercentagePerKey(ctx context.Context, key string) (map[string]decimal.Decimal, error)
+	PatchProduct(ctx context.Context, req models.ProductPatchReq) (models.Product, error)
+	DeleteProduct(ctx context.Context, id uuid.UUID, version int) (models.Product, error)
+	NextReferenceSequence(ctx context.Context) (int64, error)
+	GetProductByCode(ctx context.Context, code string) (*models.Product, error)
+	GetProductCountPerKey(ctx context.Context, key string) (map[string]int64, error)
+	GetProductSumPerGroup(ctx context.Context, groupBy models.StatisticsGroupBy, metric models.StatisticsMetric) (map[string]int64, error)
+	UpdateAbcClasses(ctx context.Context, classes map[models.AbcClass][]uuid.UUID) error
+	GetCacheStats() models.CacheStatsRp
 }
 
 type productRepo struct {
 	pdb                 *gorm.DB
 	cache               *redis.Client
+	productCache        *ProductCache
 	productCategoryRepo ProductCategoryRepo
 	supplierRepo        SupplierRepo
 }
 
-func NewProductRepo(db *gorm.DB, redis *redis.Client, cr ProductCategoryRepo, sp SupplierRepo) ProductRepo {
+func NewProductRepo(db *gorm.DB, redis *redis.Client, pc *ProductCache, cr ProductCategoryRepo, sp SupplierRepo) ProductRepo {
 	return &productRepo{
 		pdb:                 db,
 		cache:               redis,
+		productCache:        pc,
 		productCategoryRepo: cr,
 		supplierRepo:        sp,
 	}
@@ -44,21 +57,48 @@ func (pr *productRepo) GetProduct(ctx context.Context, id uuid.UUID) (*models.Pr
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	var product models.Product
-	err := pr.pdb.WithContext(ctx).Preload("Supplier").Preload("ProductCategory").First(&product, id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, nil
+	return pr.productCache.getProduct(ctx, id, func(ctx context.Context) (*models.Product, error) {
+		var product models.Product
+		err := pr.pdb.WithContext(ctx).Preload("Supplier").Preload("ProductCategory").First(&product, id).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, nil
+			}
+			return nil, err
 		}
+		return &product, nil
+	})
+}
+
+func (pr *productRepo) GetProductsByIds(ctx context.Context, ids []uuid.UUID) ([]models.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return []models.Product{}, nil
+	}
+	var products []models.Product
+	if err := pr.pdb.WithContext(ctx).Where("product_id IN ?", ids).Find(&products).Error; err != nil {
 		return nil, err
 	}
-	return &product, nil
+	return products, nil
 }
 
-func (pr *productRepo) GetProductList(ctx context.Context, req models.ProductSearchReq) ([]models.Product, int, error) {
+func (pr *productRepo) GetProductList(ctx context.Context, req models.ProductSearchReq) ([]models.Product, int64, bool, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
+	page, err := pr.productCache.getProductList(ctx, req, func(ctx context.Context) (productListPage, error) {
+		products, totalCount, hasMore, err := pr.loadProductList(ctx, req)
+		return productListPage{Products: products, Total: totalCount, HasMore: hasMore}, err
+	})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	return page.Products, page.Total, page.HasMore, nil
+}
+
+func (pr *productRepo) loadProductList(ctx context.Context, req models.ProductSearchReq) ([]models.Product, int64, bool, error) {
 	var categoryUUIDs []uuid.UUID
 	var supplierUUIDs []uuid.UUID
 
@@ -91,71 +131,223 @@ func (pr *productRepo) GetProductList(ctx context.Context, req models.ProductSea
 
 	err := wg.Wait()
 	if err != nil {
-		return nil, 0, err
+		return nil, 0, false, err
 	}
 
 	var products []models.Product
 
 	if len(req.ProductCategoryUUIDs) > 0 && len(categoryUUIDs) == 0 {
-		return products, 0, nil
+		return products, 0, false, nil
 	}
 	if len(req.SupplierUUIDs) > 0 && len(supplierUUIDs) == 0 {
-		return products, 0, nil
+		return products, 0, false, nil
 	}
 
-	q := pr.pdb.WithContext(ctx).Model(&models.Product{})
+	totalCount, err := pr.getTotalCount(ctx, req, categoryUUIDs, supplierUUIDs)
+	if err != nil {
+		return nil, 0, false, err
+	}
 
+	q := pr.pdb.WithContext(ctx).Model(&models.Product{})
 	q = pr.applyFilters(q, req, categoryUUIDs, supplierUUIDs)
+	q = q.Order(productListOrder(req))
 
-	// If Offset and Limit are both 0, fetch all products without pagination
-	if req.Offset == 0 && req.Limit == 0 {
-		err = q.Preload("Supplier").Preload("ProductCategory").Find(&products).Error
-	} else {
-		// Apply pagination if Offset and Limit are specified
-		err = q.Order("date_created desc").
-			Limit(req.Limit).
-			Offset(req.Offset).
-			Preload("Supplier").
-			Preload("ProductCategory").
-			Find(&products).Error
+	if req.After != nil {
+		condition, vars := keysetCondition(req.SortKeys, req.After)
+		q = q.Where(condition, vars...)
+	} else if req.Offset > 0 {
+		q = q.Offset(req.Offset)
+	}
+	// Without a limit all products are returned. One more row tells whether there is a next page.
+	if req.Limit > 0 {
+		q = q.Limit(req.Limit + 1)
 	}
 
-	if err != nil {
-		return nil, 0, err
+	if err := q.Preload("Supplier").Preload("ProductCategory").Find(&products).Error; err != nil {
+		return nil, 0, false, err
 	}
 
-	totalCount, err := pr.getTotalCount(ctx, req, categoryUUIDs, supplierUUIDs)
-	if err != nil {
-		return nil, 0, err
+	hasMore := req.Limit > 0 && len(products) > req.Limit
+	if hasMore {
+		products = products[:req.Limit]
+	}
+	return products, totalCount, hasMore, nil
+}
+
+// productListOrder orders by the sort keys, or by relevance to the query, and then by
+// product_id so that the order is total and can be paged with a cursor.
+func productListOrder(req models.ProductSearchReq) interface{} {
+	if req.OrderByRelevance() {
+		// A single clause: a later Order call would replace an expression.
+		return clause.OrderBy{Expression: clause.NamedExpr{
+			SQL:  productSearchRank + " DESC, date_created desc, product_id",
+			Vars: []interface{}{sql.Named("query", req.Query)},
+		}}
 	}
 
-	nextOffset := req.Offset + req.Limit
-	if nextOffset >= int(totalCount) {
-		nextOffset = 0
+	columns := make([]string, 0, len(req.SortKeys)+1)
+	for _, key := range req.SortKeys {
+		if key.Desc {
+			columns = append(columns, key.Column()+" desc")
+		} else {
+			columns = append(columns, key.Column())
+		}
 	}
+	return strings.Join(append(columns, "product_id"), ", ")
+}
 
-	return products, nextOffset, nil
+// keysetCondition selects the rows after the cursor in the productListOrder order:
+// (a > x) OR (a = x AND b < y) OR (a = x AND b = y AND product_id > id), with < for
+// descending keys.
+func keysetCondition(keys []models.ProductSortKey, after *models.ProductCursor) (string, []interface{}) {
+	columns := make([]string, 0, len(keys)+1)
+	operators := make([]string, 0, len(keys)+1)
+	for _, key := range keys {
+		columns = append(columns, key.Column())
+		if key.Desc {
+			operators = append(operators, "<")
+		} else {
+			operators = append(operators, ">")
+		}
+	}
+	columns = append(columns, "product_id")
+	operators = append(operators, ">")
+	values := append(append([]interface{}{}, after.Values...), after.ProductID)
+
+	var terms []string
+	var vars []interface{}
+	for i := range columns {
+		var parts []string
+		for j := 0; j < i; j++ {
+			parts = append(parts, columns[j]+" = ?")
+			vars = append(vars, values[j])
+		}
+		parts = append(parts, columns[i]+" "+operators[i]+" ?")
+		vars = append(vars, values[i])
+		terms = append(terms, "("+strings.Join(parts, " AND ")+")")
+	}
+	return "(" + strings.Join(terms, " OR ") + ")", vars
 }
 
+// productSearchCondition matches products whose full-text document matches the query, or whose
+// name or reference contains a word close to it (pg_trgm word similarity above
+// pg_trgm.word_similarity_threshold) to tolerate typos. Category and supplier names are
+// matched the same way.
+const productSearchCondition = "(" + models.ProductSearchVector + " @@ websearch_to_tsquery('english', @query)" +
+	" OR @query <% product_name OR @query <% product_reference" +
+	" OR product_category_id IN (SELECT product_category_id FROM product_category" +
+	" WHERE to_tsvector('english', product_category_name) @@ websearch_to_tsquery('english', @query) OR @query <% product_category_name)" +
+	" OR supplier_id IN (SELECT supplier_id FROM supplier" +
+	" WHERE to_tsvector('english', supplier_name) @@ websearch_to_tsquery('english', @query) OR @query <% supplier_name))"
+
+// productSearchRank orders query results: the full-text rank plus the best word similarity of
+// the name or reference. Products matched by their category or supplier only come last.
+const productSearchRank = "ts_rank(" + models.ProductSearchVector + ", websearch_to_tsquery('english', @query))" +
+	" + GREATEST(word_similarity(@query, product_name), word_similarity(@query, product_reference))"
+
 func (pr *productRepo) applyFilters(q *gorm.DB, req models.ProductSearchReq, categoryUUIDs, supplierUUIDs []uuid.UUID) *gorm.DB {
+	return pr.applyFiltersExcept(q, req, categoryUUIDs, supplierUUIDs, "")
+}
+
+// productFilterCondition translates a filter expression to a condition. Columns come from
+// the field whitelist of models and every value is bound as a parameter.
+func productFilterCondition(filter models.ProductFilter) (string, []interface{}) {
+	switch f := filter.(type) {
+	case models.FilterAnd:
+		return joinFilterTerms(f.Terms, " AND ")
+	case models.FilterOr:
+		return joinFilterTerms(f.Terms, " OR ")
+	case models.FilterNot:
+		condition, vars := productFilterCondition(f.Term)
+		return "NOT (" + condition + ")", vars
+	case models.FilterComparison:
+		return filterComparisonCondition(f)
+	}
+	return "FALSE", nil
+}
+
+func joinFilterTerms(terms []models.ProductFilter, sep string) (string, []interface{}) {
+	parts := make([]string, len(terms))
+	var vars []interface{}
+	for i, term := range terms {
+		condition, termVars := productFilterCondition(term)
+		parts[i] = "(" + condition + ")"
+		vars = append(vars, termVars...)
+	}
+	return strings.Join(parts, sep), vars
+}
+
+// filterComparisonCondition compares a column of the product, or of a related table through
+// a subquery. Negations of related fields exclude the products of any matching row.
+func filterComparisonCondition(f models.FilterComparison) (string, []interface{}) {
+	op := f.Op
+	if f.Field.Relation != nil {
+		op = op.Positive()
+	}
+
+	var condition string
+	var vars []interface{}
+	switch op {
+	case models.FilterIn, models.FilterNotIn:
+		condition = fmt.Sprintf("%s %s ?", f.Field.Column, op)
+		vars = []interface{}{f.Values}
+	case models.FilterContains, models.FilterExcludes:
+		like := "ILIKE"
+		if op == models.FilterExcludes {
+			like = "NOT ILIKE"
+		}
+		condition = fmt.Sprintf("%s %s ?", f.Field.Column, like)
+		vars = []interface{}{"%" + escapeLike(f.Values[0].(string)) + "%"}
+	default:
+		condition = fmt.Sprintf("%s %s ?", f.Field.Column, op)
+		vars = []interface{}{f.Values[0]}
+	}
+
+	if rel := f.Field.Relation; rel != nil {
+		in := "IN"
+		if f.Op.Negated() {
+			in = "NOT IN"
+		}
+		condition = fmt.Sprintf("%s %s (SELECT %s FROM %s WHERE %s)", rel.ForeignKey, in, rel.Key, rel.Table, condition)
+	}
+	return condition, vars
+}
+
+// escapeLike escapes the wildcards of a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// applyFiltersExcept applies the filters of req except the one of facet.
+func (pr *productRepo) applyFiltersExcept(q *gorm.DB, req models.ProductSearchReq, categoryUUIDs, supplierUUIDs []uuid.UUID, facet models.ProductFacet) *gorm.DB {
+	if req.Query != "" {
+		q = q.Where(productSearchCondition, sql.Named("query", req.Query))
+	}
+	if req.FilterExpr != nil {
+		condition, vars := productFilterCondition(req.FilterExpr)
+		q = q.Where(condition, vars...)
+	}
 	if len(req.ProductNames) > 0 {
 		q = q.Where("product_name IN (?)", req.ProductNames)
 	}
 	if len(req.ProductReferences) > 0 {
 		q = q.Where("product_reference IN (?)", req.ProductReferences)
 	}
-	if len(req.Status) > 0 {
+	if len(req.Status) > 0 && facet != models.FacetStatus {
 		q = q.Where("status IN (?)", req.Status)
 	}
-	if req.PriceFrom > 0 {
+	if req.PriceFrom > 0 && facet != models.FacetPrice {
 		q = q.Where("price >= ?", req.PriceFrom)
 	}
-	if req.PriceTo > 0 {
+	if req.PriceTo > 0 && facet != models.FacetPrice {
 		q = q.Where("price <= ?", req.PriceTo)
 	}
-	if len(req.StockLocations) > 0 {
+	if len(req.StockLocations) > 0 && facet != models.FacetStockLocation {
 		q = q.Where("stock_location IN (?)", req.StockLocations)
 	}
+	if len(req.AbcClasses) > 0 {
+		q = q.Where("abc_class IN (?)", req.AbcClasses)
+	}
 	if req.DateCreatedFrom != "" {
 		q = q.Where("date_created >= ?", req.DateCreatedFrom)
 	}
@@ -163,16 +355,71 @@ func (pr *productRepo) applyFilters(q *gorm.DB, req models.ProductSearchReq, cat
 		q = q.Where("date_created <= ?", req.DateCreatedTo)
 	}
 
-	if len(categoryUUIDs) > 0 {
+	if len(categoryUUIDs) > 0 && facet != models.FacetCategory {
 		q = q.Where("product_category_id IN (?)", categoryUUIDs)
 	}
-	if len(supplierUUIDs) > 0 {
+	if len(supplierUUIDs) > 0 && facet != models.FacetSupplier {
 		q = q.Where("supplier_id IN (?)", supplierUUIDs)
 	}
 
 	return q
 }
 
+// facetQuery selects the value and label of a facet and groups by it.
+type facetQuery struct {
+	facet   models.ProductFacet
+	columns string
+	group   string
+}
+
+// GetProductFacets counts the products per value of each facet, with all the filters of req
+// except the facet's own, in a single query. Prices are counted per bucket of priceBounds
+// (ascending lower bounds); the price facet is left out without bounds.
+func (pr *productRepo) GetProductFacets(ctx context.Context, req models.ProductSearchReq, priceBounds []int) ([]models.FacetRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	facets := []facetQuery{
+		{models.FacetStatus, "CAST(status AS text) AS value, '' AS label", "status"},
+		{models.FacetCategory, "CAST(product_category_id AS text) AS value, " +
+			"COALESCE((SELECT product_category_name FROM product_category AS c WHERE c.product_category_id = product.product_category_id), '') AS label", "product_category_id"},
+		{models.FacetSupplier, "CAST(supplier_id AS text) AS value, " +
+			"COALESCE((SELECT supplier_name FROM supplier AS s WHERE s.supplier_id = product.supplier_id), '') AS label", "supplier_id"},
+		{models.FacetStockLocation, "stock_location AS value, '' AS label", "stock_location"},
+	}
+	if len(priceBounds) > 0 {
+		// Grouped by position: the bounds parameter makes the expression differ from the select list.
+		facets = append(facets, facetQuery{models.FacetPrice, "CAST(width_bucket(price, CAST(? AS bigint[])) AS text) AS value, '' AS label", "2"})
+	}
+
+	subQueries := make([]interface{}, 0, len(facets))
+	for _, f := range facets {
+		vars := []interface{}{string(f.facet)}
+		if f.facet == models.FacetPrice {
+			vars = append(vars, bigintArray(priceBounds))
+		}
+		q := pr.pdb.Model(&models.Product{}).Select("? AS facet, "+f.columns+", COUNT(*) AS count", vars...)
+		q = pr.applyFiltersExcept(q, req, req.ProductCategoryUUIDs, req.SupplierUUIDs, f.facet)
+		subQueries = append(subQueries, q.Clauses(clause.GroupBy{Columns: []clause.Column{{Name: f.group, Raw: true}}}))
+	}
+
+	var rows []models.FacetRow
+	union := strings.TrimSuffix(strings.Repeat("(?) UNION ALL ", len(subQueries)), " UNION ALL ")
+	if err := pr.pdb.WithContext(ctx).Raw(union, subQueries...).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// bigintArray formats values as a Postgres array literal, bound as a single parameter.
+func bigintArray(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return "{" + strings.Join(parts, ",") + "}"
+}
+
 func (pr *productRepo) getTotalCount(ctx context.Context, req models.ProductSearchReq, categoryUUIDs, supplierUUIDs []uuid.UUID) (int64, error) {
 	countQuery := pr.pdb.WithContext(ctx).Model(&models.Product{})
 
@@ -200,55 +447,22 @@ func (pr *productRepo) CreateProduct(ctx context.Context, req models.ProductCrea
 		ProductID:         uuid.New(),
 		ProductName:       req.ProductName,
 		ProductReference:  req.ProductReference,
+		Barcode:           req.Barcode,
 		Status:            req.Status,
 		ProductCategoryID: uuid.MustParse(req.ProductCategoryID),
 		Price:             req.Price,
 		StockLocation:     req.StockLocation,
 		Quantity:          req.Quantity,
 		SupplierID:        uuid.MustParse(req.SupplierID),
+		Version:           1,
 	}
 
-	var err error
-
-	wg := utils.NewWgGroup()
-	wg.Go(func() error {
-		cacheKey := fmt.Sprintf(models.SupplierProductsKey, product.SupplierID)
-		_, err := pr.cache.Get(ctx, cacheKey).Result()
-		if err == nil {
-			return nil
-		} else if err == redis.Nil {
-			var supplier *models.Supplier
-			supplier, err = pr.supplierRepo.GetSupplier(ctx, product.SupplierID)
-			if err != nil {
-				return err
-			}
-			if supplier == nil {
-				return errors.New("invalid supplier")
-			}
-		}
-		return nil
-	})
-
-	wg.Go(func() error {
-		cacheKey := fmt.Sprintf(models.CategoryProductsKey, product.ProductCategoryID)
-		_, err := pr.cache.Get(ctx, cacheKey).Result()
-		if err == nil {
-			return nil
-		} else if err == redis.Nil {
-			var productCategory *models.ProductCategory
-			productCategory, err = pr.productCategoryRepo.GetCategoryByID(ctx, product.ProductCategoryID)
-			if err != nil {
-				return err
-			}
-			if productCategory == nil {
-				return errors.New("invalid product category")
-			}
-		}
-		return nil
-	})
+	if err := pr.checkRelations(ctx, &product.ProductCategoryID, &product.SupplierID); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
 
-	err = wg.Wait()
-	if err != nil {
+	if err := checkProductUnique(ctx, tx, product); err != nil {
 		tx.Rollback()
 		return models.Product{}, err
 	}
@@ -258,21 +472,27 @@ func (pr *productRepo) CreateProduct(ctx context.Context, req models.ProductCrea
 		return models.Product{}, err
 	}
 
-	//
-	pipe := pr.cache.Pipeline()
-	pipe.Incr(ctx, fmt.Sprintf(models.SupplierProductsKey, product.SupplierID))
-	pipe.Incr(ctx, models.TotalProductsKey)
-	pipe.Incr(ctx, fmt.Sprintf(models.CategoryProductsKey, product.ProductCategoryID))
-	_, err = pipe.Exec(ctx)
-	if err != nil {
+	if err := writeOutboxEvent(ctx, tx, models.OutboxProductCreated, product.ProductID, nil, product); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if product.Quantity != 0 {
+		if err := createMovement(ctx, tx, newProductMovement(product, models.MovementReceipt, product.Quantity, "product created")); err != nil {
+			tx.Rollback()
+			return models.Product{}, err
+		}
+	}
+
+	if err := writeAuditLog(ctx, tx, models.AuditEntityProduct, product.ProductID, models.AuditCreate, nil, product); err != nil {
 		tx.Rollback()
 		return models.Product{}, err
 	}
-	//
 
 	if err := tx.Commit().Error; err != nil {
 		return models.Product{}, err
 	}
+	pr.productCache.invalidate(ctx)
 
 	return product, nil
 }
@@ -287,11 +507,23 @@ func (pr *productRepo) UpdateProduct(ctx context.Context, req models.ProductUpda
 	}
 
 	var product models.Product
-	if err := tx.WithContext(ctx).First(&product, req.ProductID).Error; err != nil {
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, "product_id = ?", req.ProductID).Error
+	if err != nil {
 		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Product{}, models.ErrProductNotFound
+		}
 		return models.Product{}, err
 	}
+	// The row stays locked until commit, so no other update can slip in after this check.
+	if product.Version != req.Version {
+		tx.Rollback()
+		return models.Product{}, models.ErrVersionConflict
+	}
 
+	before := product
+	preQuantity := product.Quantity
 	preProductCategoryID := product.ProductCategoryID
 	newProductCategoryID := uuid.MustParse(req.ProductCategoryID)
 	preSupplierID := product.SupplierID
@@ -299,111 +531,352 @@ func (pr *productRepo) UpdateProduct(ctx context.Context, req models.ProductUpda
 
 	product.ProductName = req.ProductName
 	product.ProductReference = req.ProductReference
+	product.Barcode = req.Barcode
 	product.Status = req.Status
 	product.Price = req.Price
 	product.Quantity = req.Quantity
 	product.SupplierID = uuid.MustParse(req.SupplierID)
 	product.StockLocation = req.StockLocation
 	product.ProductCategoryID = uuid.MustParse(req.ProductCategoryID)
+	product.Version++
 
-	wg := utils.NewWgGroup()
-	var err error
-
+	var categoryID, supplierID *uuid.UUID
+	if preProductCategoryID != newProductCategoryID {
+		categoryID = &product.ProductCategoryID
+	}
 	if preSupplierID != newSupplierID {
-		wg.Go(func() error {
-			cacheKey := fmt.Sprintf(models.SupplierProductsKey, product.SupplierID)
-			_, err := pr.cache.Get(ctx, cacheKey).Result()
-			if err == nil {
-				return nil
-			} else if err == redis.Nil {
-				var supplier *models.Supplier
-				supplier, err = pr.supplierRepo.GetSupplier(ctx, product.SupplierID)
-				if err != nil {
-					return err
-				}
-				if supplier == nil {
-					return errors.New("invalid supplier")
-				}
-			}
-			return nil
-		})
+		supplierID = &product.SupplierID
+	}
+	if err := pr.checkRelations(ctx, categoryID, supplierID); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
 	}
 
-	if preProductCategoryID != newProductCategoryID {
-		wg.Go(func() error {
-			cacheKey := fmt.Sprintf(models.CategoryProductsKey, product.ProductCategoryID)
-			_, err := pr.cache.Get(ctx, cacheKey).Result()
-			if err == nil {
-				return nil
-			} else if err == redis.Nil {
-				var productCategory *models.ProductCategory
-				productCategory, err = pr.productCategoryRepo.GetCategoryByID(ctx, product.ProductCategoryID)
-				if err != nil {
-					return err
-				}
-				if productCategory == nil {
-					return errors.New("invalid product category")
-				}
-			}
-			return nil
-		})
+	if before.ProductReference != product.ProductReference || before.Barcode != product.Barcode {
+		if err := checkProductUnique(ctx, tx, product); err != nil {
+			tx.Rollback()
+			return models.Product{}, err
+		}
+	}
+
+	if err := tx.WithContext(ctx).Save(&product).Error; err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if err := writeOutboxEvent(ctx, tx, models.OutboxProductUpdated, product.ProductID, before, product); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if product.Quantity != preQuantity {
+		if err := createMovement(ctx, tx, newProductMovement(product, models.MovementAdjustment, product.Quantity-preQuantity, "product updated")); err != nil {
+			tx.Rollback()
+			return models.Product{}, err
+		}
+	}
+
+	if err := writeAuditLog(ctx, tx, models.AuditEntityProduct, product.ProductID, models.AuditUpdate, before, product); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return models.Product{}, err
 	}
+	pr.productCache.invalidate(ctx, product.ProductID)
+	return product, nil
+}
 
-	err = wg.Wait()
+// PatchProduct updates only the columns present in the merge patch.
+func (pr *productRepo) PatchProduct(ctx context.Context, req models.ProductPatchReq) (models.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx := pr.pdb.Begin()
+	if tx.Error != nil {
+		return models.Product{}, tx.Error
+	}
+
+	var product models.Product
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, "product_id = ?", req.ProductID).Error
 	if err != nil {
 		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Product{}, models.ErrProductNotFound
+		}
 		return models.Product{}, err
 	}
+	if product.Version != req.Version {
+		tx.Rollback()
+		return models.Product{}, models.ErrVersionConflict
+	}
 
-	if err := tx.WithContext(ctx).Save(&product).Error; err != nil {
+	before := product
+	columns := req.Apply(&product)
+	if len(columns) == 0 {
+		tx.Rollback()
+		return product, nil
+	}
+
+	var categoryID, supplierID *uuid.UUID
+	if product.ProductCategoryID != before.ProductCategoryID {
+		categoryID = &product.ProductCategoryID
+	}
+	if product.SupplierID != before.SupplierID {
+		supplierID = &product.SupplierID
+	}
+	if err := pr.checkRelations(ctx, categoryID, supplierID); err != nil {
 		tx.Rollback()
 		return models.Product{}, err
 	}
 
-	// Update cache
-	pipe := pr.cache.Pipeline()
-	if preSupplierID != newSupplierID {
-		pipe.Incr(ctx, fmt.Sprintf(models.SupplierProductsKey, newSupplierID))
-		pipe.Decr(ctx, fmt.Sprintf(models.SupplierProductsKey, preSupplierID))
+	if before.ProductReference != product.ProductReference || before.Barcode != product.Barcode {
+		if err := checkProductUnique(ctx, tx, product); err != nil {
+			tx.Rollback()
+			return models.Product{}, err
+		}
 	}
-	if preProductCategoryID != newProductCategoryID {
-		pipe.Incr(ctx, fmt.Sprintf(models.CategoryProductsKey, newProductCategoryID))
-		pipe.Decr(ctx, fmt.Sprintf(models.CategoryProductsKey, preProductCategoryID))
+
+	product.Version++
+	columns["version"] = product.Version
+	if err := tx.WithContext(ctx).Model(&models.Product{}).Where("product_id = ?", product.ProductID).Updates(columns).Error; err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if err := writeOutboxEvent(ctx, tx, models.OutboxProductUpdated, product.ProductID, before, product); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if product.Quantity != before.Quantity {
+		if err := createMovement(ctx, tx, newProductMovement(product, models.MovementAdjustment, product.Quantity-before.Quantity, "product patched")); err != nil {
+			tx.Rollback()
+			return models.Product{}, err
+		}
+	}
+
+	if err := writeAuditLog(ctx, tx, models.AuditEntityProduct, product.ProductID, models.AuditUpdate, before, product); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return models.Product{}, err
+	}
+	pr.productCache.invalidate(ctx, product.ProductID)
+	return product, nil
+}
+
+// DeleteProduct deletes a product. A version of 0 skips the version check. Stock movements,
+// snapshots and purchase order lines of the product are kept as history.
+func (pr *productRepo) DeleteProduct(ctx context.Context, id uuid.UUID, version int) (models.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx := pr.pdb.Begin()
+	if tx.Error != nil {
+		return models.Product{}, tx.Error
 	}
-	_, err = pipe.Exec(ctx)
+
+	var product models.Product
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, "product_id = ?", id).Error
 	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return models.Product{}, models.ErrProductNotFound
+		}
+		return models.Product{}, err
+	}
+	if version != 0 && product.Version != version {
+		tx.Rollback()
+		return models.Product{}, models.ErrVersionConflict
+	}
+
+	if err := tx.WithContext(ctx).Delete(&models.Product{}, "product_id = ?", id).Error; err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if err := writeAuditLog(ctx, tx, models.AuditEntityProduct, product.ProductID, models.AuditDelete, product, nil); err != nil {
+		tx.Rollback()
+		return models.Product{}, err
+	}
+
+	if err := writeOutboxEvent(ctx, tx, models.OutboxProductDeleted, product.ProductID, product, nil); err != nil {
 		tx.Rollback()
 		return models.Product{}, err
 	}
-	//
 
 	if err := tx.Commit().Error; err != nil {
 		return models.Product{}, err
 	}
+	pr.productCache.invalidate(ctx, product.ProductID)
 	return product, nil
 }
 
-func (pr *productRepo) GetProductPercentagePerKey(ctx context.Context, key string) (map[string]decimal.Decimal, error) {
+// checkProductUnique returns an error if another product has the same reference or barcode.
+// The unique indexes still guard against concurrent inserts.
+func checkProductUnique(ctx context.Context, tx *gorm.DB, product models.Product) error {
+	var count int64
+	err := tx.WithContext(ctx).Model(&models.Product{}).
+		Where("product_reference = ? AND product_id <> ?", product.ProductReference, product.ProductID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrDuplicateReference
+	}
+
+	if product.Barcode == "" {
+		return nil
+	}
+	err = tx.WithContext(ctx).Model(&models.Product{}).
+		Where("barcode IN (?) AND product_id <> ?", utils.BarcodeVariants(product.Barcode), product.ProductID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrDuplicateBarcode
+	}
+	return nil
+}
+
+// NextReferenceSequence returns the next value of the sequence used for generated references.
+func (pr *productRepo) NextReferenceSequence(ctx context.Context) (int64, error) {
 	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
-	countList := make(map[string]int64)
-	percentages := make(map[string]decimal.Decimal)
+	var seq int64
+	if err := pr.pdb.WithContext(ctx).Raw("SELECT nextval(?)", models.ProductReferenceSeq).Scan(&seq).Error; err != nil {
+		return 0, err
+	}
+	return seq, nil
+}
+
+// GetProductByCode finds a product by its reference or barcode. UPC-A and EAN-13 forms of
+// the same barcode match each other.
+func (pr *productRepo) GetProductByCode(ctx context.Context, code string) (*models.Product, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
 
-	totalProducts, err := pr.getTotalProducts(ctx)
+	var product models.Product
+	err := pr.pdb.WithContext(ctx).Preload("Supplier").Preload("ProductCategory").
+		Where("product_reference = ? OR barcode IN (?)", code, utils.BarcodeVariants(code)).
+		First(&product).Error
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
 		return nil, err
 	}
+	return &product, nil
+}
+
+// checkRelations checks that the category and supplier exist, skipping nil IDs. Missing ones
+// are all reported in a *models.ValidationError.
+func (pr *productRepo) checkRelations(ctx context.Context, categoryID, supplierID *uuid.UUID) error {
+	var categoryErr, supplierErr error
+	wg := utils.NewWgGroup()
+	if categoryID != nil {
+		wg.Go(func() error {
+			categoryErr = pr.checkCategory(ctx, *categoryID)
+			return nil
+		})
+	}
+	if supplierID != nil {
+		wg.Go(func() error {
+			supplierErr = pr.checkSupplier(ctx, *supplierID)
+			return nil
+		})
+	}
+	_ = wg.Wait()
+
+	var fields response.FieldErrors
+	for _, err := range []error{categoryErr, supplierErr} {
+		switch {
+		case err == nil:
+		case errors.Is(err, models.ErrCategoryNotFound):
+			fields.Add("product_category_id", response.ErrInvalidCategory)
+		case errors.Is(err, models.ErrSupplierNotFound):
+			fields.Add("supplier_id", response.ErrInvalidSupplier)
+		default:
+			return err
+		}
+	}
+	if len(fields) > 0 {
+		return &models.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// checkSupplier returns an error unless the supplier exists. A cached product counter for the
+// supplier is enough proof of existence.
+func (pr *productRepo) checkSupplier(ctx context.Context, id uuid.UUID) error {
+	cacheKey := fmt.Sprintf(models.SupplierProductsKey, id)
+	_, err := pr.cache.Get(ctx, cacheKey).Result()
+	if err == nil {
+		return nil
+	} else if err == redis.Nil {
+		var supplier *models.Supplier
+		supplier, err = pr.supplierRepo.GetSupplier(ctx, id)
+		if err != nil {
+			return err
+		}
+		if supplier == nil {
+			return models.ErrSupplierNotFound
+		}
+	}
+	return nil
+}
+
+// checkCategory returns an error unless the category exists, like checkSupplier.
+func (pr *productRepo) checkCategory(ctx context.Context, id uuid.UUID) error {
+	cacheKey := fmt.Sprintf(models.CategoryProductsKey, id)
+	_, err := pr.cache.Get(ctx, cacheKey).Result()
+	if err == nil {
+		return nil
+	} else if err == redis.Nil {
+		var productCategory *models.ProductCategory
+		productCategory, err = pr.productCategoryRepo.GetCategoryByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if productCategory == nil {
+			return models.ErrCategoryNotFound
+		}
+	}
+	return nil
+}
 
-	if totalProducts == 0 {
-		return percentages, nil
+func newProductMovement(product models.Product, movementType models.StockMovementType, quantity int, note string) *models.StockMovement {
+	return &models.StockMovement{
+		MovementID:    uuid.New(),
+		ProductID:     product.ProductID,
+		MovementType:  movementType,
+		Quantity:      quantity,
+		QuantityAfter: product.Quantity,
+		Note:          note,
+		CreatedAt:     time.Now().UTC(),
 	}
+}
+
+func (pr *productRepo) GetProductCountPerKey(ctx context.Context, key string) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	countList := make(map[string]int64)
 
 	var cursor uint64
 	for {
 		keys, newCursor, err := pr.cache.Scan(ctx, cursor, key, 100).Result()
 		if err != nil {
-			continue
+			return nil, err
 		}
 
 		for _, key := range keys {
@@ -432,25 +905,131 @@ func (pr *productRepo) GetProductPercentagePerKey(ctx context.Context, key strin
 		}
 	}
 
-	for id, count := range countList {
-		percentages[id] = decimal.NewFromInt(count).Mul(decimal.NewFromInt(100)).Div(decimal.NewFromInt(totalProducts)).Round(2)
+	return countList, nil
+}
+
+func (pr *productRepo) GetProductSumPerGroup(ctx context.Context, groupBy models.StatisticsGroupBy, metric models.StatisticsMetric) (map[string]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	groupColumn, ok := statisticsGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid group by %s", groupBy)
 	}
 
-	return percentages, nil
-}
+	var sumExpr string
+	switch metric {
+	case models.StatisticsMetricQuantity:
+		sumExpr = "COALESCE(SUM(quantity), 0)"
+	case models.StatisticsMetricValue:
+		sumExpr = "COALESCE(SUM(CAST(price AS BIGINT) * quantity), 0)"
+	default:
+		sumExpr = "COUNT(*)"
+	}
 
-func (pr *productRepo) getTotalProducts(ctx context.Context) (int64, error) {
-	val, err := pr.cache.Get(ctx, models.TotalProductsKey).Result()
+	var rows []struct {
+		GroupID uuid.UUID
+		Total   int64
+	}
+	err := pr.pdb.WithContext(ctx).Model(&models.Product{}).
+		Select(fmt.Sprintf("%s AS group_id, %s AS total", groupColumn, sumExpr)).
+		Group(groupColumn).
+		Scan(&rows).Error
 	if err != nil {
-		if err == redis.Nil {
-			return 0, nil
+		return nil, err
+	}
+
+	sums := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		sums[row.GroupID.String()] = row.Total
+	}
+	return sums, nil
+}
+
+// UpdateAbcClasses stores the class of each product of classes. A product whose class changes
+// gets a new version, an audit entry and an outbox event, like any other product write.
+func (pr *productRepo) UpdateAbcClasses(ctx context.Context, classes map[models.AbcClass][]uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	defer cancel()
+
+	var ids, values []string
+	for class, classIDs := range classes {
+		for _, id := range classIDs {
+			ids = append(ids, id.String())
+			values = append(values, string(class))
 		}
-		return 0, err
+	}
+	if len(ids) == 0 {
+		return nil
 	}
 
-	total, err := strconv.ParseInt(val, 10, 64)
+	var updated []uuid.UUID
+	err := pr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// The classes are passed as two array parameters: a catalog can have more products than
+		// a statement can have bind parameters.
+		var products []models.Product
+		err := tx.Raw(`SELECT p.* FROM product p
+			JOIN unnest(?::text::uuid[], ?::text::text[]) AS c(product_id, abc_class) ON c.product_id = p.product_id
+			WHERE p.abc_class <> c.abc_class
+			ORDER BY p.product_id
+			FOR UPDATE OF p`, pgArray(ids), pgArray(values)).Scan(&products).Error
+		if err != nil || len(products) == 0 {
+			return err
+		}
+
+		classOf := make(map[string]models.AbcClass, len(ids))
+		for i, id := range ids {
+			classOf[id] = models.AbcClass(values[i])
+		}
+		ids, values = ids[:0], values[:0]
+		logs := make([]models.AuditLog, 0, len(products))
+		events := make([]models.OutboxEvent, 0, len(products))
+		for _, before := range products {
+			after := before
+			after.AbcClass = classOf[before.ProductID.String()]
+			after.Version++
+
+			log, err := newAuditLog(ctx, models.AuditEntityProduct, after.ProductID, models.AuditUpdate, before, after)
+			if err != nil {
+				return err
+			}
+			if log != nil {
+				logs = append(logs, *log)
+			}
+			event, err := newOutboxEvent(models.OutboxProductUpdated, after.ProductID, before, after)
+			if err != nil {
+				return err
+			}
+			events = append(events, event)
+			ids = append(ids, after.ProductID.String())
+			values = append(values, string(after.AbcClass))
+			updated = append(updated, after.ProductID)
+		}
+
+		err = tx.Exec(`UPDATE product p SET abc_class = c.abc_class, version = p.version + 1
+			FROM unnest(?::text::uuid[], ?::text::text[]) AS c(product_id, abc_class)
+			WHERE p.product_id = c.product_id`, pgArray(ids), pgArray(values)).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.CreateInBatches(&events, 1000).Error; err != nil || len(logs) == 0 {
+			return err
+		}
+		return tx.CreateInBatches(&logs, 1000).Error
+	})
 	if err != nil {
-		return 0, fmt.Errorf("error converting value to int64: %v", err)
+		return err
 	}
-	return total, nil
+	pr.productCache.invalidate(ctx, updated...)
+	return nil
+}
+
+// pgArray returns values as a Postgres array literal, to be cast to an array type. The values
+// must not need quoting, as UUIDs and ABC classes do not.
+func pgArray(values []string) string {
+	return "{" + strings.Join(values, ",") + "}"
+}
+
+func (pr *productRepo) GetCacheStats() models.CacheStatsRp {
+	return pr.productCache.Stats()
 }
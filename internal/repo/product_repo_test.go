@@ -0,0 +1,160 @@
+package repo
+
+import (
+	"reflect"
+	"sort"
+	"stock-management/internal/models"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKeysetCondition(t *testing.T) {
+	id := uuid.MustParse("00000000-0000-0000-0000-000000000007")
+	created := time.Date(2024, 6, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		keys     []models.ProductSortKey
+		values   []interface{}
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			"no keys",
+			nil, nil,
+			"((product_id > ?))",
+			[]interface{}{id},
+		},
+		{
+			"ascending",
+			[]models.ProductSortKey{{Key: "price"}}, []interface{}{100},
+			"((price > ?) OR (price = ? AND product_id > ?))",
+			[]interface{}{100, 100, id},
+		},
+		{
+			"descending",
+			[]models.ProductSortKey{{Key: "date", Desc: true}}, []interface{}{created},
+			"((date_created < ?) OR (date_created = ? AND product_id > ?))",
+			[]interface{}{created, created, id},
+		},
+		{
+			"mixed",
+			[]models.ProductSortKey{{Key: "quantity", Desc: true}, {Key: "name"}}, []interface{}{0, "Widget"},
+			"((quantity < ?) OR (quantity = ? AND product_name > ?) OR (quantity = ? AND product_name = ? AND product_id > ?))",
+			[]interface{}{0, 0, "Widget", 0, "Widget", id},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, vars := keysetCondition(tt.keys, &models.ProductCursor{Values: tt.values, ProductID: id})
+			if sql != tt.wantSQL {
+				t.Errorf("keysetCondition() sql = %s, want %s", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(vars, tt.wantVars) {
+				t.Errorf("keysetCondition() vars = %v, want %v", vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+// TestKeysetConditionPages checks that the rows after each row of a sorted list are the rest of
+// the list, so that pages neither skip nor repeat rows with equal sort values.
+func TestKeysetConditionPages(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"product_id": uuid.MustParse("00000000-0000-0000-0000-000000000003"), "price": 200, "quantity": 1},
+		{"product_id": uuid.MustParse("00000000-0000-0000-0000-000000000001"), "price": 100, "quantity": 5},
+		{"product_id": uuid.MustParse("00000000-0000-0000-0000-000000000002"), "price": 100, "quantity": 5},
+		{"product_id": uuid.MustParse("00000000-0000-0000-0000-000000000005"), "price": 100, "quantity": 5},
+		{"product_id": uuid.MustParse("00000000-0000-0000-0000-000000000004"), "price": 100, "quantity": 9},
+		{"product_id": uuid.MustParse("00000000-0000-0000-0000-000000000006"), "price": 50, "quantity": 5},
+	}
+	tests := []struct {
+		name string
+		keys []models.ProductSortKey
+	}{
+		{"ties broken by ID", []models.ProductSortKey{{Key: "price", Desc: true}}},
+		{"ties on two keys", []models.ProductSortKey{{Key: "price", Desc: true}, {Key: "quantity"}}},
+		{"every key tied", []models.ProductSortKey{{Key: "quantity"}}},
+		{"ID only", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := append([]map[string]interface{}{}, rows...)
+			sort.SliceStable(sorted, func(i, j int) bool { return rowLess(tt.keys, sorted[i], sorted[j]) })
+
+			for i, last := range sorted {
+				cursor := &models.ProductCursor{ProductID: last["product_id"].(uuid.UUID)}
+				for _, key := range tt.keys {
+					cursor.Values = append(cursor.Values, last[key.Column()])
+				}
+				sql, vars := keysetCondition(tt.keys, cursor)
+
+				var after []uuid.UUID
+				for _, row := range sorted {
+					if evalKeyset(t, sql, vars, row) {
+						after = append(after, row["product_id"].(uuid.UUID))
+					}
+				}
+				var want []uuid.UUID
+				for _, row := range sorted[i+1:] {
+					want = append(want, row["product_id"].(uuid.UUID))
+				}
+				if !reflect.DeepEqual(after, want) {
+					t.Errorf("rows after %v = %v, want %v", cursor.ProductID, after, want)
+				}
+			}
+		})
+	}
+}
+
+// rowLess orders rows like productListOrder.
+func rowLess(keys []models.ProductSortKey, a, b map[string]interface{}) bool {
+	for _, key := range keys {
+		if c := compareValues(a[key.Column()], b[key.Column()]); c != 0 {
+			return (c < 0) != key.Desc
+		}
+	}
+	return compareValues(a["product_id"], b["product_id"]) < 0
+}
+
+// evalKeyset evaluates a keysetCondition, an OR of ANDs of "column op ?", on a row.
+func evalKeyset(t *testing.T, sql string, vars []interface{}, row map[string]interface{}) bool {
+	sql = strings.TrimSuffix(strings.TrimPrefix(sql, "("), ")")
+	matched := false
+	for _, term := range strings.Split(sql, " OR ") {
+		term = strings.TrimSuffix(strings.TrimPrefix(term, "("), ")")
+		all := true
+		for _, part := range strings.Split(term, " AND ") {
+			fields := strings.Fields(part)
+			if len(fields) != 3 || fields[2] != "?" {
+				t.Fatalf("unexpected condition %q", part)
+			}
+			c := compareValues(row[fields[0]], vars[0])
+			vars = vars[1:]
+			switch fields[1] {
+			case "=":
+				all = all && c == 0
+			case "<":
+				all = all && c < 0
+			case ">":
+				all = all && c > 0
+			default:
+				t.Fatalf("unexpected operator %q", fields[1])
+			}
+		}
+		matched = matched || all
+	}
+	return matched
+}
+
+func compareValues(a, b interface{}) int {
+	switch a := a.(type) {
+	case int:
+		return a - b.(int)
+	case uuid.UUID:
+		return strings.Compare(a.String(), b.(uuid.UUID).String())
+	}
+	panic("unexpected value type")
+}
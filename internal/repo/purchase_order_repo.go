@@ -0,0 +1,98 @@
+package repo
+
+import (
+	"context"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type PurchaseOrderRepo interface {
+	CreatePurchaseOrders(ctx context.Context, orders []models.PurchaseOrder) error
+	GetPurchaseOrderList(ctx context.Context, req models.PurchaseOrderSearchReq) ([]models.PurchaseOrder, int, error)
+	GetOnOrderQuantities(ctx context.Context) (map[uuid.UUID]int64, error)
+}
+
+type purchaseOrderRepo struct {
+	pdb *gorm.DB
+}
+
+func NewPurchaseOrderRepo(db *gorm.DB) PurchaseOrderRepo {
+	return &purchaseOrderRepo{
+		pdb: db,
+	}
+}
+
+func (po *purchaseOrderRepo) CreatePurchaseOrders(ctx context.Context, orders []models.PurchaseOrder) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if len(orders) == 0 {
+		return nil
+	}
+	return po.pdb.WithContext(ctx).Omit("Supplier").Create(&orders).Error
+}
+
+func (po *purchaseOrderRepo) GetPurchaseOrderList(ctx context.Context, req models.PurchaseOrderSearchReq) ([]models.PurchaseOrder, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := po.pdb.WithContext(ctx).Model(&models.PurchaseOrder{})
+	if len(req.SupplierUUIDs) > 0 {
+		q = q.Where("supplier_id IN (?)", req.SupplierUUIDs)
+	}
+	if len(req.Status) > 0 {
+		q = q.Where("status IN (?)", req.Status)
+	}
+
+	var totalCount int64
+	if err := q.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orders []models.PurchaseOrder
+	err := q.Order("created_at desc").
+		Offset(req.Offset).
+		Limit(req.Limit).
+		Preload("Supplier").
+		Preload("Lines").
+		Find(&orders).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	nextOffset := req.Offset + req.Limit
+	if nextOffset >= int(totalCount) {
+		nextOffset = 0
+	}
+	return orders, nextOffset, nil
+}
+
+// GetOnOrderQuantities returns the quantity per product on draft and ordered purchase orders,
+// which is stock already planned but not received yet.
+func (po *purchaseOrderRepo) GetOnOrderQuantities(ctx context.Context) (map[uuid.UUID]int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var rows []struct {
+		ProductID uuid.UUID
+		Quantity  int64
+	}
+	err := po.pdb.WithContext(ctx).Table("purchase_order_line AS l").
+		Joins("JOIN purchase_order AS o ON o.purchase_order_id = l.purchase_order_id").
+		Select("l.product_id, SUM(l.quantity) AS quantity").
+		Where("o.status IN (?)", []models.PurchaseOrderStatus{models.PurchaseOrderDraft, models.PurchaseOrderOrdered}).
+		Group("l.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	quantities := make(map[uuid.UUID]int64, len(rows))
+	for _, row := range rows {
+		quantities[row.ProductID] = row.Quantity
+	}
+	return quantities, nil
+}
@@ -0,0 +1,263 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ReportRepo interface {
+	CreateSavedSearch(ctx context.Context, search models.SavedSearch) error
+	UpdateSavedSearch(ctx context.Context, search models.SavedSearch) (bool, error)
+	DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) ([]uuid.UUID, bool, error)
+	GetSavedSearch(ctx context.Context, id uuid.UUID) (*models.SavedSearch, error)
+	GetSavedSearchList(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error)
+	CreateSchedule(ctx context.Context, schedule models.ReportSchedule) error
+	UpdateSchedule(ctx context.Context, schedule models.ReportSchedule) (bool, error)
+	DeleteSchedule(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	GetSchedule(ctx context.Context, id uuid.UUID) (*models.ReportSchedule, error)
+	GetScheduleList(ctx context.Context, userID uuid.UUID) ([]models.ReportSchedule, error)
+	GetEnabledSchedules(ctx context.Context) ([]models.ReportSchedule, error)
+	CreateRun(ctx context.Context, run models.ReportRun) error
+	FinishRun(ctx context.Context, run models.ReportRun) error
+	GetRunList(ctx context.Context, req models.ReportRunSearchReq) ([]models.ReportRun, int, error)
+}
+
+type reportRepo struct {
+	pdb *gorm.DB
+}
+
+func NewReportRepo(db *gorm.DB) ReportRepo {
+	return &reportRepo{
+		pdb: db,
+	}
+}
+
+func (rr *reportRepo) CreateSavedSearch(ctx context.Context, search models.SavedSearch) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := rr.checkSearchName(ctx, search); err != nil {
+		return err
+	}
+	return rr.pdb.WithContext(ctx).Create(&search).Error
+}
+
+// UpdateSavedSearch replaces the name and search of a saved search of search.UserID. It returns
+// false when the user has no such saved search.
+func (rr *reportRepo) UpdateSavedSearch(ctx context.Context, search models.SavedSearch) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := rr.checkSearchName(ctx, search); err != nil {
+		return false, err
+	}
+	rs := rr.pdb.WithContext(ctx).Model(&models.SavedSearch{}).
+		Where("saved_search_id = ? AND user_id = ?", search.SavedSearchID, search.UserID).
+		Select("name", "search", "updated_at").
+		Updates(&search)
+	if rs.Error != nil {
+		return false, rs.Error
+	}
+	return rs.RowsAffected > 0, nil
+}
+
+// checkSearchName returns models.ErrDuplicateSearchName if the user has another saved search
+// with the same name. The unique index still guards against concurrent inserts.
+func (rr *reportRepo) checkSearchName(ctx context.Context, search models.SavedSearch) error {
+	var count int64
+	err := rr.pdb.WithContext(ctx).Model(&models.SavedSearch{}).
+		Where("user_id = ? AND name = ? AND saved_search_id <> ?", search.UserID, search.Name, search.SavedSearchID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return models.ErrDuplicateSearchName
+	}
+	return nil
+}
+
+// DeleteSavedSearch deletes a saved search of the user with its schedules and their run
+// history, and returns the IDs of the deleted schedules.
+func (rr *reportRepo) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) ([]uuid.UUID, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var scheduleIDs []uuid.UUID
+	deleted := false
+	err := rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rs := tx.Where("saved_search_id = ? AND user_id = ?", id, userID).Delete(&models.SavedSearch{})
+		if rs.Error != nil || rs.RowsAffected == 0 {
+			return rs.Error
+		}
+		deleted = true
+
+		if err := tx.Model(&models.ReportSchedule{}).Where("saved_search_id = ?", id).Pluck("schedule_id", &scheduleIDs).Error; err != nil {
+			return err
+		}
+		if len(scheduleIDs) == 0 {
+			return nil
+		}
+		if err := tx.Where("schedule_id IN (?)", scheduleIDs).Delete(&models.ReportRun{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("schedule_id IN (?)", scheduleIDs).Delete(&models.ReportSchedule{}).Error
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return scheduleIDs, deleted, nil
+}
+
+func (rr *reportRepo) GetSavedSearch(ctx context.Context, id uuid.UUID) (*models.SavedSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var search models.SavedSearch
+	if err := rr.pdb.WithContext(ctx).First(&search, "saved_search_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (rr *reportRepo) GetSavedSearchList(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	searches := []models.SavedSearch{}
+	if err := rr.pdb.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&searches).Error; err != nil {
+		return nil, err
+	}
+	return searches, nil
+}
+
+func (rr *reportRepo) CreateSchedule(ctx context.Context, schedule models.ReportSchedule) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return rr.pdb.WithContext(ctx).Create(&schedule).Error
+}
+
+// UpdateSchedule replaces a schedule of schedule.UserID. It returns false when the user has
+// no such schedule.
+func (rr *reportRepo) UpdateSchedule(ctx context.Context, schedule models.ReportSchedule) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rs := rr.pdb.WithContext(ctx).Model(&models.ReportSchedule{}).
+		Where("schedule_id = ? AND user_id = ?", schedule.ScheduleID, schedule.UserID).
+		Select("saved_search_id", "name", "cron", "format", "delivery", "recipients", "enabled", "updated_at").
+		Updates(&schedule)
+	if rs.Error != nil {
+		return false, rs.Error
+	}
+	return rs.RowsAffected > 0, nil
+}
+
+// DeleteSchedule deletes a schedule of the user with its run history.
+func (rr *reportRepo) DeleteSchedule(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	deleted := false
+	err := rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rs := tx.Where("schedule_id = ? AND user_id = ?", id, userID).Delete(&models.ReportSchedule{})
+		if rs.Error != nil || rs.RowsAffected == 0 {
+			return rs.Error
+		}
+		deleted = true
+		return tx.Where("schedule_id = ?", id).Delete(&models.ReportRun{}).Error
+	})
+	return deleted, err
+}
+
+func (rr *reportRepo) GetSchedule(ctx context.Context, id uuid.UUID) (*models.ReportSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var schedule models.ReportSchedule
+	if err := rr.pdb.WithContext(ctx).First(&schedule, "schedule_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (rr *reportRepo) GetScheduleList(ctx context.Context, userID uuid.UUID) ([]models.ReportSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	schedules := []models.ReportSchedule{}
+	if err := rr.pdb.WithContext(ctx).Where("user_id = ?", userID).Order("name").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (rr *reportRepo) GetEnabledSchedules(ctx context.Context) ([]models.ReportSchedule, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var schedules []models.ReportSchedule
+	if err := rr.pdb.WithContext(ctx).Where("enabled").Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (rr *reportRepo) CreateRun(ctx context.Context, run models.ReportRun) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return rr.pdb.WithContext(ctx).Create(&run).Error
+}
+
+// FinishRun stores the outcome of a run and its time as the last run of the schedule.
+func (rr *reportRepo) FinishRun(ctx context.Context, run models.ReportRun) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.ReportRun{}).Where("run_id = ?", run.RunID).
+			Select("status", "products", "output", "error", "finished_at").
+			Updates(&run).Error
+		if err != nil {
+			return err
+		}
+		return tx.Model(&models.ReportSchedule{}).Where("schedule_id = ?", run.ScheduleID).
+			Update("last_run_at", run.StartedAt).Error
+	})
+}
+
+func (rr *reportRepo) GetRunList(ctx context.Context, req models.ReportRunSearchReq) ([]models.ReportRun, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := rr.pdb.WithContext(ctx).Model(&models.ReportRun{}).Where("schedule_id = ?", req.ScheduleUUID)
+
+	var totalCount int64
+	if err := q.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	runs := []models.ReportRun{}
+	if err := q.Order("started_at desc").Offset(req.Offset).Limit(req.Limit).Find(&runs).Error; err != nil {
+		return nil, 0, err
+	}
+
+	nextOffset := req.Offset + req.Limit
+	if nextOffset >= int(totalCount) {
+		nextOffset = 0
+	}
+	return runs, nextOffset, nil
+}
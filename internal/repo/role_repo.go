@@ -0,0 +1,224 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RoleRepo interface {
+	GetRoleList(ctx context.Context) ([]models.Role, error)
+	GetRole(ctx context.Context, id uuid.UUID) (*models.Role, error)
+	GetRoleByName(ctx context.Context, name string) (*models.Role, error)
+	CreateRole(ctx context.Context, role models.Role) (*models.Role, error)
+	UpdateRole(ctx context.Context, role models.Role) (*models.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	GetUserRoles(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]models.Role, error)
+	SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, keepRoleID uuid.UUID) (bool, error)
+	CountUsersWithRole(ctx context.Context, roleID uuid.UUID) (int64, error)
+}
+
+type roleRepo struct {
+	pdb *gorm.DB
+}
+
+func NewRoleRepo(db *gorm.DB) RoleRepo {
+	return &roleRepo{
+		pdb: db,
+	}
+}
+
+func (rr *roleRepo) GetRoleList(ctx context.Context) ([]models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var roles []models.Role
+	if err := rr.pdb.WithContext(ctx).Preload("RolePermissions").Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	for i := range roles {
+		fillPermissions(&roles[i])
+	}
+	return roles, nil
+}
+
+func (rr *roleRepo) GetRole(ctx context.Context, id uuid.UUID) (*models.Role, error) {
+	return rr.getRole(ctx, "role_id = ?", id)
+}
+
+func (rr *roleRepo) GetRoleByName(ctx context.Context, name string) (*models.Role, error) {
+	return rr.getRole(ctx, "name = ?", name)
+}
+
+func (rr *roleRepo) getRole(ctx context.Context, query string, arg interface{}) (*models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var role models.Role
+	if err := rr.pdb.WithContext(ctx).Preload("RolePermissions").First(&role, query, arg).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	fillPermissions(&role)
+	return &role, nil
+}
+
+func (rr *roleRepo) CreateRole(ctx context.Context, role models.Role) (*models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("RolePermissions").Create(&role).Error; err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role.RoleID, role.Permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (rr *roleRepo) UpdateRole(ctx context.Context, role models.Role) (*models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	err := rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&models.Role{}).Where("role_id = ?", role.RoleID).
+			Update("description", role.Description).Error
+		if err != nil {
+			return err
+		}
+		return replaceRolePermissions(tx, role.RoleID, role.Permissions)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+func (rr *roleRepo) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", id).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&models.RolePermission{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("role_id = ?", id).Delete(&models.Role{}).Error
+	})
+}
+
+func (rr *roleRepo) GetUserRoles(ctx context.Context, userIDs []uuid.UUID) (map[uuid.UUID][]models.Role, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var userRoles []models.UserRole
+	if err := rr.pdb.WithContext(ctx).Where("user_id IN (?)", userIDs).Find(&userRoles).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[uuid.UUID][]models.Role)
+	if len(userRoles) == 0 {
+		return result, nil
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(userRoles))
+	for _, ur := range userRoles {
+		roleIDs = append(roleIDs, ur.RoleID)
+	}
+	var roles []models.Role
+	if err := rr.pdb.WithContext(ctx).Preload("RolePermissions").Where("role_id IN (?)", roleIDs).Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	roleMap := make(map[uuid.UUID]models.Role, len(roles))
+	for _, role := range roles {
+		fillPermissions(&role)
+		roleMap[role.RoleID] = role
+	}
+	for _, ur := range userRoles {
+		if role, ok := roleMap[ur.RoleID]; ok {
+			result[ur.UserID] = append(result[ur.UserID], role)
+		}
+	}
+	return result, nil
+}
+
+// SetUserRoles replaces the roles of a user. Unless keepRoleID is uuid.Nil or among roleIDs, it
+// returns false and changes nothing when the user is the only one with keepRoleID. The users
+// with keepRoleID are locked meanwhile, so that concurrent calls can't each remove another one.
+func (rr *roleRepo) SetUserRoles(ctx context.Context, userID uuid.UUID, roleIDs []uuid.UUID, keepRoleID uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	replaced := false
+	err := rr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if keepRoleID != uuid.Nil && !slices.Contains(roleIDs, keepRoleID) {
+			var holders []uuid.UUID
+			err := tx.Model(&models.UserRole{}).Clauses(clause.Locking{Strength: "UPDATE"}).
+				Where("role_id = ?", keepRoleID).Pluck("user_id", &holders).Error
+			if err != nil {
+				return err
+			}
+			if len(holders) == 1 && holders[0] == userID {
+				return nil
+			}
+		}
+
+		if err := tx.Where("user_id = ?", userID).Delete(&models.UserRole{}).Error; err != nil {
+			return err
+		}
+		replaced = true
+		if len(roleIDs) == 0 {
+			return nil
+		}
+		userRoles := make([]models.UserRole, 0, len(roleIDs))
+		for _, id := range roleIDs {
+			userRoles = append(userRoles, models.UserRole{UserID: userID, RoleID: id})
+		}
+		return tx.Create(&userRoles).Error
+	})
+	return replaced && err == nil, err
+}
+
+func (rr *roleRepo) CountUsersWithRole(ctx context.Context, roleID uuid.UUID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	if err := rr.pdb.WithContext(ctx).Model(&models.UserRole{}).Where("role_id = ?", roleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func replaceRolePermissions(tx *gorm.DB, roleID uuid.UUID, permissions []string) error {
+	if err := tx.Where("role_id = ?", roleID).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+	rows := make([]models.RolePermission, 0, len(permissions))
+	for _, p := range permissions {
+		rows = append(rows, models.RolePermission{RoleID: roleID, Permission: p})
+	}
+	return tx.Create(&rows).Error
+}
+
+func fillPermissions(role *models.Role) {
+	role.Permissions = make([]string, 0, len(role.RolePermissions))
+	for _, rp := range role.RolePermissions {
+		role.Permissions = append(role.Permissions, rp.Permission)
+	}
+}
@@ -0,0 +1,244 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StockMovementRepo interface {
+	CreateMovement(ctx context.Context, req models.StockMovementCreateReq) (*models.StockMovement, error)
+	GetMovementList(ctx context.Context, req models.StockMovementSearchReq) ([]models.StockMovement, int, error)
+	GetIssuedPerGroup(ctx context.Context, groupBy models.StatisticsGroupBy, from, to time.Time) ([]models.GroupTotalRow, error)
+	GetProductIssues(ctx context.Context, since time.Time) ([]models.ProductIssueRow, error)
+	GetDeadStock(ctx context.Context, before time.Time) ([]models.DeadStockItem, error)
+	GetConsumptionPerProduct(ctx context.Context, from, to time.Time) ([]models.ProductConsumptionRow, error)
+	GetDailyIssues(ctx context.Context, from time.Time, productIDs []uuid.UUID) ([]models.DailyIssueRow, error)
+}
+
+type stockMovementRepo struct {
+	pdb          *gorm.DB
+	productCache *ProductCache
+}
+
+func NewStockMovementRepo(db *gorm.DB, pc *ProductCache) StockMovementRepo {
+	return &stockMovementRepo{
+		pdb:          db,
+		productCache: pc,
+	}
+}
+
+func (mr *stockMovementRepo) CreateMovement(ctx context.Context, req models.StockMovementCreateReq) (*models.StockMovement, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	tx := mr.pdb.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	var product models.Product
+	err := tx.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&product, "product_id = ?", req.ProductID).Error
+	if err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, models.ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	quantityAfter := product.Quantity + req.Delta()
+	if quantityAfter < 0 {
+		tx.Rollback()
+		return nil, fmt.Errorf("%w: %d available", models.ErrInsufficientStock, product.Quantity)
+	}
+
+	before := product
+	err = tx.WithContext(ctx).Model(&models.Product{}).Where("product_id = ?", product.ProductID).
+		Updates(map[string]interface{}{"quantity": quantityAfter, "version": gorm.Expr("version + 1")}).Error
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	product.Quantity = quantityAfter
+	product.Version++
+
+	if err := writeAuditLog(ctx, tx, models.AuditEntityProduct, product.ProductID, models.AuditUpdate, before, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := writeOutboxEvent(ctx, tx, models.OutboxProductUpdated, product.ProductID, before, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	movement := models.StockMovement{
+		MovementID:    uuid.New(),
+		ProductID:     product.ProductID,
+		MovementType:  req.MovementType,
+		Quantity:      req.Delta(),
+		QuantityAfter: quantityAfter,
+		Note:          req.Note,
+		CreatedAt:     time.Now().UTC(),
+	}
+	if err := createMovement(ctx, tx, &movement); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+	mr.productCache.invalidate(ctx, product.ProductID)
+	return &movement, nil
+}
+
+// createMovement records a movement and its outbox event within tx.
+func createMovement(ctx context.Context, tx *gorm.DB, movement *models.StockMovement) error {
+	if err := tx.WithContext(ctx).Create(movement).Error; err != nil {
+		return err
+	}
+	return writeOutboxEvent(ctx, tx, models.OutboxMovementCreated, movement.MovementID, nil, movement)
+}
+
+func (mr *stockMovementRepo) GetMovementList(ctx context.Context, req models.StockMovementSearchReq) ([]models.StockMovement, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := mr.pdb.WithContext(ctx).Model(&models.StockMovement{})
+	if len(req.ProductUUIDs) > 0 {
+		q = q.Where("product_id IN (?)", req.ProductUUIDs)
+	}
+	if len(req.MovementTypes) > 0 {
+		q = q.Where("movement_type IN (?)", req.MovementTypes)
+	}
+	if req.DateFrom != "" {
+		q = q.Where("created_at >= ?", req.DateFrom)
+	}
+	if req.DateTo != "" {
+		q = q.Where("created_at < CAST(? AS date) + 1", req.DateTo)
+	}
+
+	var totalCount int64
+	if err := q.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var movements []models.StockMovement
+	if err := q.Order("created_at desc").Offset(req.Offset).Limit(req.Limit).Find(&movements).Error; err != nil {
+		return nil, 0, err
+	}
+
+	nextOffset := req.Offset + req.Limit
+	if nextOffset >= int(totalCount) {
+		nextOffset = 0
+	}
+	return movements, nextOffset, nil
+}
+
+func (mr *stockMovementRepo) GetIssuedPerGroup(ctx context.Context, groupBy models.StatisticsGroupBy, from, to time.Time) ([]models.GroupTotalRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	groupColumn, ok := statisticsGroupColumns[groupBy]
+	if !ok {
+		return nil, fmt.Errorf("invalid group by %s", groupBy)
+	}
+
+	var rows []models.GroupTotalRow
+	err := mr.pdb.WithContext(ctx).Table("stock_movement AS m").
+		Joins("JOIN product AS p ON p.product_id = m.product_id").
+		Select(fmt.Sprintf("p.%s AS group_id, SUM(-m.quantity) AS quantity, SUM(-m.quantity * CAST(p.price AS BIGINT)) AS value", groupColumn)).
+		Where("m.movement_type = ?", models.MovementIssue).
+		Where("m.created_at >= ? AND m.created_at < CAST(? AS date) + 1", from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Group("p." + groupColumn).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (mr *stockMovementRepo) GetProductIssues(ctx context.Context, since time.Time) ([]models.ProductIssueRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var rows []models.ProductIssueRow
+	err := mr.pdb.WithContext(ctx).Table("product AS p").
+		Joins("LEFT JOIN stock_movement AS m ON m.product_id = p.product_id AND m.movement_type = ? AND m.created_at >= ?", models.MovementIssue, since).
+		Select("p.product_id, p.product_name, p.product_reference, p.quantity, COALESCE(SUM(-m.quantity), 0) AS issued_quantity").
+		Group("p.product_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetDeadStock returns products still in stock whose last movement, or creation date when
+// they never moved, is older than before.
+func (mr *stockMovementRepo) GetDeadStock(ctx context.Context, before time.Time) ([]models.DeadStockItem, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var rows []models.DeadStockItem
+	err := mr.pdb.WithContext(ctx).Table("product AS p").
+		Joins("LEFT JOIN stock_movement AS m ON m.product_id = p.product_id").
+		Select("p.product_id, p.product_name, p.product_reference, p.product_category_id, p.supplier_id, p.quantity, "+
+			"CAST(p.price AS BIGINT) * p.quantity AS value, MAX(m.created_at) AS last_movement_at").
+		Where("p.quantity > 0").
+		Group("p.product_id").
+		Having("COALESCE(MAX(m.created_at), p.date_created) < ?", before).
+		Order("value desc").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+// GetConsumptionPerProduct returns the value issued over the period for every product,
+// including products without issues, ordered by consumption value.
+func (mr *stockMovementRepo) GetConsumptionPerProduct(ctx context.Context, from, to time.Time) ([]models.ProductConsumptionRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var rows []models.ProductConsumptionRow
+	err := mr.pdb.WithContext(ctx).Table("product AS p").
+		Joins("LEFT JOIN stock_movement AS m ON m.product_id = p.product_id AND m.movement_type = ? AND m.created_at >= ? AND m.created_at < CAST(? AS date) + 1",
+			models.MovementIssue, from.Format("2006-01-02"), to.Format("2006-01-02")).
+		Select("p.product_id, p.product_name, p.product_reference, COALESCE(SUM(-m.quantity * CAST(p.price AS BIGINT)), 0) AS consumption_value").
+		Group("p.product_id").
+		Order("consumption_value desc, p.product_name").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (mr *stockMovementRepo) GetDailyIssues(ctx context.Context, from time.Time, productIDs []uuid.UUID) ([]models.DailyIssueRow, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	q := mr.pdb.WithContext(ctx).Model(&models.StockMovement{}).
+		Select("product_id, CAST(created_at AS date) AS day, SUM(-quantity) AS quantity").
+		Where("movement_type = ? AND created_at >= ?", models.MovementIssue, from.Format("2006-01-02"))
+	if len(productIDs) > 0 {
+		q = q.Where("product_id IN (?)", productIDs)
+	}
+
+	var rows []models.DailyIssueRow
+	if err := q.Group("product_id, CAST(created_at AS date)").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -80,8 +80,19 @@ func (sr *supplierRepo) CreateSupplier(ctx context.Context, req models.SupplierC
 		SupplierID:   uuid.New(),
 		SupplierName: req.SupplierName,
 		Status:       models.SupplierStatus(req.Status),
+		LeadTimeDays: req.LeadTimeDays,
+		Version:      1,
 	}
-	if err := sr.pdb.WithContext(ctx).Create(&supplier).Error; err != nil {
+	err := sr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&supplier).Error; err != nil {
+			return err
+		}
+		if err := writeAuditLog(ctx, tx, models.AuditEntitySupplier, supplier.SupplierID, models.AuditCreate, nil, supplier); err != nil {
+			return err
+		}
+		return writeOutboxEvent(ctx, tx, models.OutboxSupplierCreated, supplier.SupplierID, nil, supplier)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return &supplier, nil
@@ -0,0 +1,107 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UserRepo interface {
+	GetUser(ctx context.Context, id uuid.UUID) (*models.User, error)
+	GetUserByUsername(ctx context.Context, username string) (*models.User, error)
+	CreateUser(ctx context.Context, user models.User) (*models.User, error)
+	CountUsers(ctx context.Context) (int64, error)
+	GetUserList(ctx context.Context, req models.UserSearchReq) ([]models.User, int, error)
+}
+
+type userRepo struct {
+	pdb *gorm.DB
+}
+
+func NewUserRepo(db *gorm.DB) UserRepo {
+	return &userRepo{
+		pdb: db,
+	}
+}
+
+func (ur *userRepo) GetUser(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := ur.pdb.WithContext(ctx).First(&user, "user_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *userRepo) GetUserByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var user models.User
+	if err := ur.pdb.WithContext(ctx).First(&user, "username = ?", username).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *userRepo) CreateUser(ctx context.Context, user models.User) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if err := ur.pdb.WithContext(ctx).Create(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (ur *userRepo) CountUsers(ctx context.Context) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var count int64
+	if err := ur.pdb.WithContext(ctx).Model(&models.User{}).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (ur *userRepo) GetUserList(ctx context.Context, req models.UserSearchReq) ([]models.User, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := ur.pdb.WithContext(ctx).Model(&models.User{})
+	if req.Username != "" {
+		q = q.Where("username ILIKE ?", "%"+req.Username+"%")
+	}
+	if req.Status != "" {
+		q = q.Where("status = ?", req.Status)
+	}
+
+	var totalCount int64
+	if err := q.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	if err := q.Order("username").Limit(req.Limit).Offset(req.Offset).Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+
+	nextOffset := req.Offset + req.Limit
+	if nextOffset >= int(totalCount) {
+		nextOffset = 0
+	}
+	return users, nextOffset, nil
+}
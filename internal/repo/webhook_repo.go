@@ -0,0 +1,244 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"stock-management/internal/models"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type WebhookRepo interface {
+	GetWebhookList(ctx context.Context) ([]models.Webhook, error)
+	GetWebhook(ctx context.Context, id uuid.UUID) (*models.Webhook, error)
+	GetWebhooksByIds(ctx context.Context, ids []uuid.UUID) ([]models.Webhook, error)
+	GetEnabledWebhooks(ctx context.Context) ([]models.Webhook, error)
+	CreateWebhook(ctx context.Context, webhook models.Webhook) error
+	UpdateWebhook(ctx context.Context, webhook models.Webhook) (bool, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) (bool, error)
+	EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error
+	ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error)
+	FinishDelivery(ctx context.Context, delivery models.WebhookDelivery) error
+	GetDeliveryList(ctx context.Context, req models.WebhookDeliverySearchReq) ([]models.WebhookDelivery, int, error)
+	RedeliverDeliveries(ctx context.Context, ids []uuid.UUID) (int64, error)
+	DeleteDelivered(ctx context.Context, before time.Time) (int64, error)
+}
+
+type webhookRepo struct {
+	pdb *gorm.DB
+}
+
+func NewWebhookRepo(db *gorm.DB) WebhookRepo {
+	return &webhookRepo{
+		pdb: db,
+	}
+}
+
+func (wr *webhookRepo) GetWebhookList(ctx context.Context) ([]models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	webhooks := []models.Webhook{}
+	if err := wr.pdb.WithContext(ctx).Order("created_at").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (wr *webhookRepo) GetWebhook(ctx context.Context, id uuid.UUID) (*models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var webhook models.Webhook
+	if err := wr.pdb.WithContext(ctx).First(&webhook, "webhook_id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+func (wr *webhookRepo) GetWebhooksByIds(ctx context.Context, ids []uuid.UUID) ([]models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if len(ids) == 0 {
+		return []models.Webhook{}, nil
+	}
+	var webhooks []models.Webhook
+	if err := wr.pdb.WithContext(ctx).Where("webhook_id IN (?)", ids).Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (wr *webhookRepo) GetEnabledWebhooks(ctx context.Context) ([]models.Webhook, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var webhooks []models.Webhook
+	if err := wr.pdb.WithContext(ctx).Where("enabled").Find(&webhooks).Error; err != nil {
+		return nil, err
+	}
+	return webhooks, nil
+}
+
+func (wr *webhookRepo) CreateWebhook(ctx context.Context, webhook models.Webhook) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return wr.pdb.WithContext(ctx).Create(&webhook).Error
+}
+
+// UpdateWebhook replaces a webhook, keeping its secret when webhook.Secret is empty. It returns
+// false when there is no such webhook.
+func (wr *webhookRepo) UpdateWebhook(ctx context.Context, webhook models.Webhook) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	columns := []interface{}{"event_types", "enabled", "updated_at"}
+	if webhook.Secret != "" {
+		columns = append(columns, "secret")
+	}
+	rs := wr.pdb.WithContext(ctx).Model(&models.Webhook{}).
+		Where("webhook_id = ?", webhook.WebhookID).
+		Select("url", columns...).
+		Updates(&webhook)
+	if rs.Error != nil {
+		return false, rs.Error
+	}
+	return rs.RowsAffected > 0, nil
+}
+
+// DeleteWebhook deletes a webhook with its delivery log.
+func (wr *webhookRepo) DeleteWebhook(ctx context.Context, id uuid.UUID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	deleted := false
+	err := wr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		rs := tx.Where("webhook_id = ?", id).Delete(&models.Webhook{})
+		if rs.Error != nil || rs.RowsAffected == 0 {
+			return rs.Error
+		}
+		deleted = true
+		return tx.Where("webhook_id = ?", id).Delete(&models.WebhookDelivery{}).Error
+	})
+	return deleted, err
+}
+
+// EnqueueDeliveries adds deliveries to send. A delivery of an event the webhook already has is
+// skipped, so an event published again is not sent twice.
+func (wr *webhookRepo) EnqueueDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return wr.pdb.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "webhook_id"}, {Name: "event_id"}},
+		DoNothing: true,
+	}).Create(&deliveries).Error
+}
+
+// ClaimDeliveries returns up to limit pending deliveries that are due, oldest first, and moves
+// their next attempt lease ahead so that no other instance sends them meanwhile. Deliveries of
+// disabled webhooks wait until the webhook is enabled again.
+func (wr *webhookRepo) ClaimDeliveries(ctx context.Context, limit int, lease time.Duration) ([]models.WebhookDelivery, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var deliveries []models.WebhookDelivery
+	err := wr.pdb.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		enabled := tx.Model(&models.Webhook{}).Select("webhook_id").Where("enabled")
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ? AND webhook_id IN (?)", models.DeliveryPending, now, enabled).
+			Order("next_attempt_at").Limit(limit).Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(deliveries))
+		for _, delivery := range deliveries {
+			ids = append(ids, delivery.DeliveryID)
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("delivery_id IN (?)", ids).
+			Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+// FinishDelivery stores the outcome of an attempt.
+func (wr *webhookRepo) FinishDelivery(ctx context.Context, delivery models.WebhookDelivery) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	return wr.pdb.WithContext(ctx).Model(&models.WebhookDelivery{}).Where("delivery_id = ?", delivery.DeliveryID).
+		Select("status", "attempts", "next_attempt_at", "last_attempt_at", "last_status_code", "last_error", "delivered_at").
+		Updates(&delivery).Error
+}
+
+func (wr *webhookRepo) GetDeliveryList(ctx context.Context, req models.WebhookDeliverySearchReq) ([]models.WebhookDelivery, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	q := wr.pdb.WithContext(ctx).Model(&models.WebhookDelivery{})
+	if req.WebhookUUID != nil {
+		q = q.Where("webhook_id = ?", *req.WebhookUUID)
+	}
+	if req.Status != "" {
+		q = q.Where("status = ?", req.Status)
+	}
+
+	var totalCount int64
+	if err := q.Session(&gorm.Session{}).Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	deliveries := []models.WebhookDelivery{}
+	if err := q.Order("created_at desc").Offset(req.Offset).Limit(req.Limit).Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	nextOffset := req.Offset + req.Limit
+	if nextOffset >= int(totalCount) {
+		nextOffset = 0
+	}
+	return deliveries, nextOffset, nil
+}
+
+// RedeliverDeliveries queues the dead deliveries among ids again with their attempts reset, and
+// returns how many were queued.
+func (wr *webhookRepo) RedeliverDeliveries(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	rs := wr.pdb.WithContext(ctx).Model(&models.WebhookDelivery{}).
+		Where("delivery_id IN (?) AND status = ?", ids, models.DeliveryDead).
+		Updates(map[string]interface{}{
+			"status":          models.DeliveryPending,
+			"attempts":        0,
+			"next_attempt_at": time.Now().UTC(),
+		})
+	return rs.RowsAffected, rs.Error
+}
+
+// DeleteDelivered deletes the delivered deliveries created before before. Pending and dead
+// deliveries are kept.
+func (wr *webhookRepo) DeleteDelivered(ctx context.Context, before time.Time) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	rs := wr.pdb.WithContext(ctx).Where("status = ? AND created_at < ?", models.DeliveryDelivered, before).
+		Delete(&models.WebhookDelivery{})
+	return rs.RowsAffected, rs.Error
+}
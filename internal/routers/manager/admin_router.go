@@ -3,6 +3,8 @@ package manager
 import (
 	"stock-management/global"
 	controller "stock-management/internal/controllers"
+	"stock-management/internal/middlewares"
+	"stock-management/internal/models"
 	rs "stock-management/pkgs/response"
 
 	"github.com/gin-gonic/gin"
@@ -10,35 +12,149 @@ import (
 
 type AdminRouter struct{}
 
-func (r *AdminRouter) InitAdminRouter(router *gin.RouterGroup) {
+func (r *AdminRouter) InitAdminRouter(publicRouter *gin.RouterGroup) {
 
-	router.GET("ping", Pong)
+	publicRouter.GET("ping", Pong)
+	authRouter := publicRouter.Group("auth")
+	{
+		authRouter.POST("login", controller.Auth.Login)
+		authRouter.POST("refresh", controller.Auth.Refresh)
+		authRouter.POST("logout", controller.Auth.Logout)
+	}
+
+	router := publicRouter.Group("")
+	router.Use(middlewares.AuthMiddleware())
+
+	router.GET("auth/me", controller.Auth.Me)
+	userRouter := router.Group("user")
+	userRouter.Use(middlewares.RequirePermission(models.PermUserManage))
+	{
+		userRouter.POST("list", controller.Role.GetUserList)
+		userRouter.POST("create", controller.Auth.CreateUser)
+		userRouter.POST("assign-roles", controller.Role.AssignUserRoles)
+	}
+
+	roleRouter := router.Group("role")
+	roleRouter.Use(middlewares.RequirePermission(models.PermUserManage))
+	{
+		roleRouter.POST("list", controller.Role.GetRoleList)
+		roleRouter.GET("permissions", controller.Role.GetPermissionList)
+		roleRouter.POST("create", controller.Role.CreateRole)
+		roleRouter.PUT("update", controller.Role.UpdateRole)
+		roleRouter.POST("delete", controller.Role.DeleteRole)
+	}
+
+	auditRouter := router.Group("audit")
+	auditRouter.Use(middlewares.RequirePermission(models.PermAuditRead))
+	{
+		auditRouter.POST("list", controller.Audit.GetAuditList)
+	}
+
+	apiKeyRouter := router.Group("api-key")
+	{
+		apiKeyRouter.POST("list", controller.Auth.GetApiKeyList)
+		apiKeyRouter.POST("create", controller.Auth.CreateApiKey)
+		apiKeyRouter.POST("revoke", controller.Auth.RevokeApiKey)
+	}
+
+	idempotent := middlewares.Idempotency()
+	productRead := middlewares.RequirePermission(models.PermProductRead)
+	productWrite := middlewares.RequirePermission(models.PermProductWrite)
+	productTools := middlewares.RequirePermission(models.PermProductTools)
 	productRouter := router.Group("product")
 	{
-		productRouter.POST("list", controller.Product.GetProductList)
-		productRouter.POST("detail", controller.Product.GetProduct)
-		productRouter.POST("create", controller.Product.CreateProduct)
-		productRouter.PUT("update", controller.Product.UpdateProduct)
-		productRouter.POST("export", controller.Product.ExportProductsToPDF)
-		productRouter.POST("distance", controller.Product.GetProductDistance)
+		productRouter.POST("list", productRead, controller.Product.GetProductList)
+		productRouter.POST("detail", productRead, controller.Product.GetProduct)
+		productRouter.GET("lookup", productTools, controller.Product.LookupProduct)
+		productRouter.POST("create", productWrite, idempotent, controller.Product.CreateProduct)
+		productRouter.PUT("update", productWrite, controller.Product.UpdateProduct)
+		productRouter.PATCH("update", productWrite, controller.Product.PatchProduct)
+		productRouter.POST("export", productTools, controller.Product.ExportProductsToPDF)
+		productRouter.POST("labels", productTools, controller.Product.PrintLabels)
+		productRouter.POST("distance", productTools, controller.Product.GetProductDistance)
+	}
+
+	savedSearchRouter := router.Group("saved-search")
+	{
+		savedSearchRouter.POST("list", productRead, controller.Report.GetSavedSearchList)
+		savedSearchRouter.POST("create", productTools, controller.Report.CreateSavedSearch)
+		savedSearchRouter.PUT("update", productTools, controller.Report.UpdateSavedSearch)
+		savedSearchRouter.POST("delete", productTools, controller.Report.DeleteSavedSearch)
+	}
+
+	reportScheduleRouter := router.Group("report-schedule")
+	reportScheduleRouter.Use(middlewares.RequirePermission(models.PermReportManage))
+	{
+		reportScheduleRouter.POST("list", controller.Report.GetScheduleList)
+		reportScheduleRouter.POST("create", controller.Report.CreateSchedule)
+		reportScheduleRouter.PUT("update", controller.Report.UpdateSchedule)
+		reportScheduleRouter.POST("delete", controller.Report.DeleteSchedule)
+		reportScheduleRouter.POST("run", controller.Report.RunSchedule)
+		reportScheduleRouter.POST("runs", controller.Report.GetRunList)
+	}
+
+	eventRead := middlewares.RequirePermission(models.PermEventRead)
+	eventWrite := middlewares.RequirePermission(models.PermEventWrite)
+	eventRouter := router.Group("event")
+	{
+		eventRouter.POST("list", eventRead, controller.Event.GetEventList)
+		eventRouter.POST("group/list", eventRead, controller.Event.GetGroupList)
+		eventRouter.POST("group/create", eventWrite, controller.Event.CreateGroup)
+		eventRouter.POST("replay", eventWrite, controller.Event.Replay)
+	}
+
+	webhookRouter := router.Group("webhook")
+	webhookRouter.Use(middlewares.RequirePermission(models.PermWebhookManage))
+	{
+		webhookRouter.POST("list", controller.Webhook.GetWebhookList)
+		webhookRouter.POST("create", controller.Webhook.CreateWebhook)
+		webhookRouter.PUT("update", controller.Webhook.UpdateWebhook)
+		webhookRouter.POST("delete", controller.Webhook.DeleteWebhook)
+		webhookRouter.POST("delivery/list", controller.Webhook.GetDeliveryList)
+		webhookRouter.POST("dead-letter/list", controller.Webhook.GetDeadLetterList)
+		webhookRouter.POST("dead-letter/redeliver", controller.Webhook.Redeliver)
 	}
 
 	productCategoryRouter := router.Group("product-category")
 	{
-		productCategoryRouter.POST("list", controller.ProductCategory.GetProductCategoryList)
-		productCategoryRouter.POST("create", controller.ProductCategory.CreateProductCategory)
+		productCategoryRouter.POST("list", middlewares.RequirePermission(models.PermCategoryRead), controller.ProductCategory.GetProductCategoryList)
+		productCategoryRouter.POST("create", middlewares.RequirePermission(models.PermCategoryWrite), idempotent, controller.ProductCategory.CreateProductCategory)
 	}
 
 	supplierRouter := router.Group("supplier")
 	{
-		supplierRouter.POST("list", controller.Supplier.SearchSupplierList)
-		supplierRouter.POST("create", controller.Supplier.CreateSupplier)
+		supplierRouter.POST("list", middlewares.RequirePermission(models.PermSupplierRead), controller.Supplier.SearchSupplierList)
+		supplierRouter.POST("create", middlewares.RequirePermission(models.PermSupplierWrite), idempotent, controller.Supplier.CreateSupplier)
+	}
+
+	stockMovementRouter := router.Group("stock-movement")
+	{
+		stockMovementRouter.POST("list", middlewares.RequirePermission(models.PermStockRead), controller.StockMovement.GetMovementList)
+		stockMovementRouter.POST("create", middlewares.RequirePermission(models.PermStockWrite), idempotent, controller.StockMovement.CreateMovement)
+	}
+
+	planningRouter := router.Group("planning")
+	{
+		planningRouter.GET("suggestions", middlewares.RequirePermission(models.PermPlanningRead), controller.Planning.GetSuggestions)
+		planningRouter.POST("convert", middlewares.RequirePermission(models.PermPlanningWrite), idempotent, controller.Planning.ConvertToPurchaseOrders)
+	}
+
+	purchaseOrderRouter := router.Group("purchase-order")
+	{
+		purchaseOrderRouter.POST("list", middlewares.RequirePermission(models.PermPlanningRead), controller.Planning.GetPurchaseOrderList)
 	}
 
+	statisticsRead := middlewares.RequirePermission(models.PermStatisticsRead)
 	statisticsRouter := router.Group("statistics")
 	{
-		statisticsRouter.GET("products-per-category", controller.Statistics.GetProductPerCategory)
-		statisticsRouter.GET("products-per-supplier", controller.Statistics.GetProductPerSupplier)
+		statisticsRouter.GET("products-per-category", statisticsRead, controller.Statistics.GetProductPerCategory)
+		statisticsRouter.GET("products-per-supplier", statisticsRead, controller.Statistics.GetProductPerSupplier)
+		statisticsRouter.GET("timeseries", statisticsRead, controller.Statistics.GetTimeseries)
+		statisticsRouter.GET("turnover", statisticsRead, controller.Statistics.GetTurnover)
+		statisticsRouter.GET("days-of-cover", statisticsRead, controller.Statistics.GetDaysOfCover)
+		statisticsRouter.GET("dead-stock", statisticsRead, controller.Statistics.GetDeadStock)
+		statisticsRouter.GET("cache", statisticsRead, controller.Statistics.GetCacheStats)
+		statisticsRouter.POST("abc-analysis", middlewares.RequirePermission(models.PermStatisticsWrite), controller.Statistics.RunAbcAnalysis)
 	}
 }
 
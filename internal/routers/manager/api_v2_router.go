@@ -0,0 +1,50 @@
+package manager
+
+import (
+	controller "stock-management/internal/controllers"
+	"stock-management/internal/middlewares"
+	"stock-management/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ApiV2Router struct{}
+
+// InitApiV2Router registers the resource routes of /api/v2. Errors, including authentication
+// and permission failures, are problem details.
+func (r *ApiV2Router) InitApiV2Router(v2Router *gin.RouterGroup) {
+	router := v2Router.Group("")
+	router.Use(middlewares.ProblemDetails(), middlewares.AuthMiddleware())
+
+	idempotent := middlewares.Idempotency()
+	productRead := middlewares.RequirePermission(models.PermProductRead)
+	productWrite := middlewares.RequirePermission(models.PermProductWrite)
+	productRouter := router.Group("products")
+	{
+		productRouter.GET("", productRead, controller.ProductV2.ListProducts)
+		productRouter.GET("lookup", middlewares.RequirePermission(models.PermProductTools), controller.ProductV2.LookupProduct)
+		productRouter.GET(":id", productRead, controller.ProductV2.GetProduct)
+		productRouter.POST("", productWrite, idempotent, controller.ProductV2.CreateProduct)
+		productRouter.PUT(":id", productWrite, controller.ProductV2.UpdateProduct)
+		productRouter.PATCH(":id", productWrite, controller.ProductV2.PatchProduct)
+		productRouter.DELETE(":id", productWrite, controller.ProductV2.DeleteProduct)
+	}
+
+	categoryRouter := router.Group("categories")
+	{
+		categoryRouter.GET("", middlewares.RequirePermission(models.PermCategoryRead), controller.ProductCategoryV2.ListProductCategories)
+		categoryRouter.POST("", middlewares.RequirePermission(models.PermCategoryWrite), idempotent, controller.ProductCategoryV2.CreateProductCategory)
+	}
+
+	supplierRouter := router.Group("suppliers")
+	{
+		supplierRouter.GET("", middlewares.RequirePermission(models.PermSupplierRead), controller.SupplierV2.ListSuppliers)
+		supplierRouter.POST("", middlewares.RequirePermission(models.PermSupplierWrite), idempotent, controller.SupplierV2.CreateSupplier)
+	}
+
+	stockMovementRouter := router.Group("stock-movements")
+	{
+		stockMovementRouter.GET("", middlewares.RequirePermission(models.PermStockRead), controller.StockMovementV2.ListMovements)
+		stockMovementRouter.POST("", middlewares.RequirePermission(models.PermStockWrite), idempotent, controller.StockMovementV2.CreateMovement)
+	}
+}
@@ -2,4 +2,5 @@ package manager
 
 type ManageRouterGroup struct {
 	AdminRouter
+	ApiV2Router
 }
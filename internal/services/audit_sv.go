@@ -0,0 +1,36 @@
+package services
+
+import (
+	"context"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+)
+
+type AuditService interface {
+	GetAuditList(ctx context.Context, req models.AuditSearchReq) (*models.SearchRp, error)
+}
+
+type auditService struct {
+	auditRepo repo.AuditRepo
+}
+
+func newAuditService(auditRepo repo.AuditRepo) AuditService {
+	return &auditService{
+		auditRepo: auditRepo,
+	}
+}
+
+func (as *auditService) GetAuditList(ctx context.Context, req models.AuditSearchReq) (*models.SearchRp, error) {
+	logs, offset, err := as.auditRepo.GetAuditList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.SearchRp{
+		Data: logs,
+		Pagination: models.Pagination{
+			Offset: offset,
+			Limit:  req.Limit,
+		},
+	}
+	return result, nil
+}
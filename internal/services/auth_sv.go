@@ -0,0 +1,307 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"stock-management/global"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid username or password")
+	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrInvalidApiKey      = errors.New("invalid api key")
+)
+
+const (
+	jwtIssuer    string = "stock-management"
+	apiKeyPrefix string = "smk_"
+)
+
+type AuthService interface {
+	Login(ctx context.Context, req models.LoginReq) (*models.TokenRp, error)
+	Refresh(ctx context.Context, req models.RefreshTokenReq) (*models.TokenRp, error)
+	Logout(ctx context.Context, req models.RefreshTokenReq) error
+	ParseAccessToken(ctx context.Context, token string) (*models.AuthUser, error)
+	AuthenticateApiKey(ctx context.Context, key string) (*models.AuthUser, error)
+	CreateUser(ctx context.Context, req models.UserCreateReq) (*models.User, error)
+	EnsureAdminUser(ctx context.Context) error
+	CreateApiKey(ctx context.Context, userID uuid.UUID, req models.ApiKeyCreateReq) (*models.ApiKeyCreateRp, error)
+	GetApiKeyList(ctx context.Context, userID uuid.UUID) ([]models.ApiKey, error)
+	RevokeApiKey(ctx context.Context, userID, id uuid.UUID) error
+}
+
+type authService struct {
+	userRepo repo.UserRepo
+	authRepo repo.AuthRepo
+}
+
+func newAuthService(userRepo repo.UserRepo, authRepo repo.AuthRepo) AuthService {
+	return &authService{
+		userRepo: userRepo,
+		authRepo: authRepo,
+	}
+}
+
+type accessClaims struct {
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+func (as *authService) Login(ctx context.Context, req models.LoginReq) (*models.TokenRp, error) {
+	user, err := as.userRepo.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status != models.UserActive {
+		return nil, ErrInvalidCredentials
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	return as.issueTokens(ctx, user)
+}
+
+// Refresh exchanges a refresh token for a new token pair. The old refresh token is revoked,
+// so each refresh token can be used only once.
+func (as *authService) Refresh(ctx context.Context, req models.RefreshTokenReq) (*models.TokenRp, error) {
+	token, err := as.authRepo.GetRefreshTokenByHash(ctx, hashSecret(req.RefreshToken))
+	if err != nil {
+		return nil, err
+	}
+	if token == nil || token.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidToken
+	}
+
+	revoked, err := as.authRepo.RevokeRefreshToken(ctx, token.TokenID)
+	if err != nil {
+		return nil, err
+	}
+	if !revoked {
+		return nil, ErrInvalidToken
+	}
+
+	user, err := as.userRepo.GetUser(ctx, token.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status != models.UserActive {
+		return nil, ErrInvalidToken
+	}
+	return as.issueTokens(ctx, user)
+}
+
+func (as *authService) Logout(ctx context.Context, req models.RefreshTokenReq) error {
+	token, err := as.authRepo.GetRefreshTokenByHash(ctx, hashSecret(req.RefreshToken))
+	if err != nil {
+		return err
+	}
+	if token == nil {
+		return ErrInvalidToken
+	}
+	_, err = as.authRepo.RevokeRefreshToken(ctx, token.TokenID)
+	return err
+}
+
+func (as *authService) ParseAccessToken(ctx context.Context, token string) (*models.AuthUser, error) {
+	claims := &accessClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(global.Config.Auth.JwtSecret), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithIssuer(jwtIssuer), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	return &models.AuthUser{
+		UserID:     userID,
+		Username:   claims.Username,
+		AuthMethod: models.AuthMethodJWT,
+	}, nil
+}
+
+func (as *authService) AuthenticateApiKey(ctx context.Context, key string) (*models.AuthUser, error) {
+	apiKey, err := as.authRepo.GetApiKeyByHash(ctx, hashSecret(key))
+	if err != nil {
+		return nil, err
+	}
+	if apiKey == nil || apiKey.RevokedAt != nil || (apiKey.ExpiresAt != nil && time.Now().After(*apiKey.ExpiresAt)) {
+		return nil, ErrInvalidApiKey
+	}
+
+	user, err := as.userRepo.GetUser(ctx, apiKey.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil || user.Status != models.UserActive {
+		return nil, ErrInvalidApiKey
+	}
+
+	// Only record usage once a minute to avoid a write on every request.
+	if apiKey.LastUsedAt == nil || time.Since(*apiKey.LastUsedAt) > time.Minute {
+		if err := as.authRepo.TouchApiKey(ctx, apiKey.ApiKeyID); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.AuthUser{
+		UserID:     user.UserID,
+		Username:   user.Username,
+		AuthMethod: models.AuthMethodApiKey,
+	}, nil
+}
+
+func (as *authService) CreateUser(ctx context.Context, req models.UserCreateReq) (*models.User, error) {
+	exists, err := as.userRepo.GetUserByUsername(ctx, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if exists != nil {
+		return nil, errors.New("username already exists")
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	return as.userRepo.CreateUser(ctx, models.User{
+		UserID:       uuid.New(),
+		Username:     req.Username,
+		PasswordHash: string(hash),
+		Status:       models.UserActive,
+		CreatedAt:    time.Now().UTC(),
+	})
+}
+
+// EnsureAdminUser creates the configured admin user when there are no users yet,
+// so a fresh install can log in.
+func (as *authService) EnsureAdminUser(ctx context.Context) error {
+	auth := global.Config.Auth
+	if auth.AdminUsername == "" || auth.AdminPassword == "" {
+		return nil
+	}
+
+	count, err := as.userRepo.CountUsers(ctx)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	_, err = as.CreateUser(ctx, models.UserCreateReq{
+		Username: auth.AdminUsername,
+		Password: auth.AdminPassword,
+	})
+	return err
+}
+
+func (as *authService) CreateApiKey(ctx context.Context, userID uuid.UUID, req models.ApiKeyCreateReq) (*models.ApiKeyCreateRp, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	key := apiKeyPrefix + secret
+
+	apiKey := models.ApiKey{
+		ApiKeyID:  uuid.New(),
+		UserID:    userID,
+		Name:      req.Name,
+		Prefix:    key[:len(apiKeyPrefix)+8],
+		KeyHash:   hashSecret(key),
+		CreatedAt: time.Now().UTC(),
+	}
+	if req.ExpiresInDays > 0 {
+		expiresAt := apiKey.CreatedAt.AddDate(0, 0, req.ExpiresInDays)
+		apiKey.ExpiresAt = &expiresAt
+	}
+	if err := as.authRepo.CreateApiKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+	return &models.ApiKeyCreateRp{
+		ApiKey: apiKey,
+		Key:    key,
+	}, nil
+}
+
+func (as *authService) GetApiKeyList(ctx context.Context, userID uuid.UUID) ([]models.ApiKey, error) {
+	return as.authRepo.GetApiKeyList(ctx, userID)
+}
+
+func (as *authService) RevokeApiKey(ctx context.Context, userID, id uuid.UUID) error {
+	revoked, err := as.authRepo.RevokeApiKey(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !revoked {
+		return ErrInvalidApiKey
+	}
+	return nil
+}
+
+func (as *authService) issueTokens(ctx context.Context, user *models.User) (*models.TokenRp, error) {
+	auth := global.Config.Auth
+	now := time.Now()
+
+	claims := accessClaims{
+		Username: user.Username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    jwtIssuer,
+			Subject:   user.UserID.String(),
+			ID:        uuid.NewString(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(auth.AccessTokenTTL) * time.Second)),
+		},
+	}
+	accessToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(auth.JwtSecret))
+	if err != nil {
+		return nil, err
+	}
+
+	refreshToken, err := generateSecret()
+	if err != nil {
+		return nil, err
+	}
+	err = as.authRepo.CreateRefreshToken(ctx, models.RefreshToken{
+		TokenID:   uuid.New(),
+		UserID:    user.UserID,
+		TokenHash: hashSecret(refreshToken),
+		ExpiresAt: now.Add(time.Duration(auth.RefreshTokenTTL) * time.Second).UTC(),
+		CreatedAt: now.UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TokenRp{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    auth.AccessTokenTTL,
+	}, nil
+}
+
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,143 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+)
+
+type EventService interface {
+	GetEvents(ctx context.Context, req models.EventSearchReq) (*models.EventListRp, error)
+	GetGroups(ctx context.Context) ([]models.EventGroup, error)
+	CreateGroup(ctx context.Context, req models.EventGroupCreateReq) error
+	Replay(ctx context.Context, req models.EventReplayReq) error
+}
+
+type eventService struct {
+	eventRepo repo.EventRepo
+}
+
+func newEventService(eventRepo repo.EventRepo) EventService {
+	return &eventService{
+		eventRepo: eventRepo,
+	}
+}
+
+func (es *eventService) GetEvents(ctx context.Context, req models.EventSearchReq) (*models.EventListRp, error) {
+	return es.eventRepo.GetEvents(ctx, req)
+}
+
+func (es *eventService) GetGroups(ctx context.Context) ([]models.EventGroup, error) {
+	return es.eventRepo.GetGroups(ctx)
+}
+
+func (es *eventService) CreateGroup(ctx context.Context, req models.EventGroupCreateReq) error {
+	return es.eventRepo.CreateGroup(ctx, req.Name, req.From)
+}
+
+// Replay moves a consumer group back to req.From. Events the group already delivered are
+// delivered again to its consumers.
+func (es *eventService) Replay(ctx context.Context, req models.EventReplayReq) error {
+	return es.eventRepo.SetGroupPosition(ctx, req.Group, req.From)
+}
+
+// domainEventTypes are the events published for the updates of an entity.
+type domainEventTypes struct {
+	entityType      string
+	created         models.DomainEventType
+	updated         models.DomainEventType
+	deleted         models.DomainEventType
+	quantityChanged models.DomainEventType
+	statusChanged   models.DomainEventType
+}
+
+var (
+	productEvents = domainEventTypes{
+		entityType:      "product",
+		created:         models.EventProductCreated,
+		updated:         models.EventProductUpdated,
+		deleted:         models.EventProductDeleted,
+		quantityChanged: models.EventProductQuantityChanged,
+		statusChanged:   models.EventProductStatusChanged,
+	}
+	movementEvents = domainEventTypes{
+		entityType: "stock_movement",
+		created:    models.EventStockMovementCreated,
+	}
+	supplierEvents = domainEventTypes{
+		entityType:    "supplier",
+		created:       models.EventSupplierCreated,
+		updated:       models.EventSupplierUpdated,
+		statusChanged: models.EventSupplierStatusChanged,
+	}
+	categoryEvents = domainEventTypes{
+		entityType:    "category",
+		created:       models.EventCategoryCreated,
+		updated:       models.EventCategoryUpdated,
+		statusChanged: models.EventCategoryStatusChanged,
+	}
+)
+
+// domainEvents returns the events published for an outbox event: the write itself, followed by
+// a quantity and a status change when an update changed them.
+func domainEvents(event models.OutboxEvent) ([]models.DomainEvent, error) {
+	var types domainEventTypes
+	var eventType models.DomainEventType
+	switch event.EventType {
+	case models.OutboxProductCreated:
+		types, eventType = productEvents, productEvents.created
+	case models.OutboxProductUpdated:
+		types, eventType = productEvents, productEvents.updated
+	case models.OutboxProductDeleted:
+		types, eventType = productEvents, productEvents.deleted
+	case models.OutboxMovementCreated:
+		types, eventType = movementEvents, movementEvents.created
+	case models.OutboxSupplierCreated:
+		types, eventType = supplierEvents, supplierEvents.created
+	case models.OutboxCategoryCreated:
+		types, eventType = categoryEvents, categoryEvents.created
+	default:
+		return nil, nil
+	}
+
+	var events []models.DomainEvent
+	add := func(eventType models.DomainEventType, data models.DomainEventData) {
+		events = append(events, models.DomainEvent{
+			EventID:    fmt.Sprintf("%d-%d", event.EventID, len(events)),
+			Type:       eventType,
+			Version:    models.DomainEventVersion,
+			EntityType: types.entityType,
+			EntityID:   event.EntityID,
+			OccurredAt: event.CreatedAt,
+			Data:       data,
+		})
+	}
+	add(eventType, models.DomainEventData{Before: event.Change.Before, After: event.Change.After})
+	if eventType != types.updated {
+		return events, nil
+	}
+
+	var before, after struct {
+		Status   string `json:"status"`
+		Quantity int    `json:"quantity"`
+	}
+	if err := event.Change.Decode(&before, &after); err != nil {
+		return nil, err
+	}
+	if types.quantityChanged != "" && before.Quantity != after.Quantity {
+		delta := after.Quantity - before.Quantity
+		add(types.quantityChanged, models.DomainEventData{
+			Before: json.RawMessage(fmt.Sprint(before.Quantity)),
+			After:  json.RawMessage(fmt.Sprint(after.Quantity)),
+			Delta:  &delta,
+		})
+	}
+	if types.statusChanged != "" && before.Status != after.Status {
+		statusBefore, _ := json.Marshal(before.Status)
+		statusAfter, _ := json.Marshal(after.Status)
+		add(types.statusChanged, models.DomainEventData{Before: statusBefore, After: statusAfter})
+	}
+	return events, nil
+}
@@ -0,0 +1,79 @@
+package services
+
+import (
+	"context"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type InventorySnapshotService interface {
+	TakeDailySnapshot(ctx context.Context) (int64, error)
+	GetTimeseries(ctx context.Context, req models.TimeseriesReq) (*models.TimeseriesRp, error)
+}
+
+type inventorySnapshotService struct {
+	inventorySnapshotRepo repo.InventorySnapshotRepo
+	productRepo           repo.ProductRepo
+	productCategoryRepo   repo.ProductCategoryRepo
+	supplierRepo          repo.SupplierRepo
+}
+
+func newInventorySnapshotService(inventorySnapshotRepo repo.InventorySnapshotRepo, productRepo repo.ProductRepo, productCategoryRepo repo.ProductCategoryRepo, supplierRepo repo.SupplierRepo) InventorySnapshotService {
+	return &inventorySnapshotService{
+		inventorySnapshotRepo: inventorySnapshotRepo,
+		productRepo:           productRepo,
+		productCategoryRepo:   productCategoryRepo,
+		supplierRepo:          supplierRepo,
+	}
+}
+
+func (is *inventorySnapshotService) TakeDailySnapshot(ctx context.Context) (int64, error) {
+	return is.inventorySnapshotRepo.CreateSnapshot(ctx, time.Now().UTC())
+}
+
+func (is *inventorySnapshotService) GetTimeseries(ctx context.Context, req models.TimeseriesReq) (*models.TimeseriesRp, error) {
+	rows, err := is.inventorySnapshotRepo.GetTimeseries(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	series := []models.TimeseriesSeries{}
+	indexes := make(map[uuid.UUID]int)
+	for _, row := range rows {
+		idx, ok := indexes[row.GroupID]
+		if !ok {
+			idx = len(series)
+			indexes[row.GroupID] = idx
+			series = append(series, models.TimeseriesSeries{
+				ID:     row.GroupID.String(),
+				Points: []models.TimeseriesPoint{},
+			})
+		}
+		series[idx].Points = append(series[idx].Points, models.TimeseriesPoint{
+			Date:     row.Bucket.Format("2006-01-02"),
+			Quantity: row.Quantity,
+			Value:    row.Value,
+		})
+	}
+
+	ids := make([]uuid.UUID, 0, len(indexes))
+	for id := range indexes {
+		ids = append(ids, id)
+	}
+	names, err := getGroupNames(ctx, is.productRepo, is.productCategoryRepo, is.supplierRepo, req.GroupBy, ids)
+	if err != nil {
+		return nil, err
+	}
+	for i := range series {
+		series[i].Name = names[series[i].ID]
+	}
+
+	return &models.TimeseriesRp{
+		Bucket:  req.Bucket,
+		GroupBy: req.GroupBy,
+		Series:  series,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"stock-management/global"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"stock-management/pkgs/setting"
+	"strings"
+
+	"github.com/boombuler/barcode/qr"
+	"github.com/google/uuid"
+	"github.com/jung-kurt/gofpdf"
+	"github.com/jung-kurt/gofpdf/contrib/barcode"
+)
+
+var (
+	ErrInvalidLabelSize  = errors.New("invalid label size")
+	ErrLabelProduct      = errors.New("invalid product")
+	ErrLabelBarcodeEmpty = errors.New("product has no barcode")
+)
+
+type LabelService interface {
+	RenderProductLabels(ctx context.Context, req models.ProductLabelReq) ([]byte, error)
+}
+
+type labelService struct {
+	productRepo repo.ProductRepo
+}
+
+func newLabelService(productRepo repo.ProductRepo) LabelService {
+	return &labelService{
+		productRepo: productRepo,
+	}
+}
+
+// RenderProductLabels renders a PDF sheet with req.Copies labels per product, in the order of
+// req.ProductIDs, laid out in a grid of the configured label size.
+func (ls *labelService) RenderProductLabels(ctx context.Context, req models.ProductLabelReq) ([]byte, error) {
+	cfg := global.Config.Label
+	sizeName := req.Size
+	if sizeName == "" {
+		sizeName = cfg.DefaultSize
+	}
+	size, ok := cfg.Sizes[strings.ToLower(sizeName)]
+	if !ok || size.Width <= 0 || size.Height <= 0 {
+		return nil, ErrInvalidLabelSize
+	}
+
+	products, err := ls.productRepo.GetProductsByIds(ctx, req.ProductUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	productMap := make(map[uuid.UUID]models.Product, len(products))
+	for _, product := range products {
+		productMap[product.ProductID] = product
+	}
+
+	pageSize := cfg.PageSize
+	if pageSize == "" {
+		pageSize = "A4"
+	}
+	pdf := gofpdf.New("P", "mm", pageSize, "")
+	pdf.SetAutoPageBreak(false, 0)
+	pageW, pageH := pdf.GetPageSize()
+	cols := int((pageW - 2*cfg.Margin) / size.Width)
+	rows := int((pageH - 2*cfg.Margin) / size.Height)
+	if cols < 1 || rows < 1 {
+		return nil, ErrInvalidLabelSize
+	}
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	i := 0
+	for _, id := range req.ProductUUIDs {
+		product, ok := productMap[id]
+		if !ok {
+			return nil, ErrLabelProduct
+		}
+		for n := 0; n < req.Copies; n++ {
+			if i%(cols*rows) == 0 {
+				pdf.AddPage()
+			}
+			x := cfg.Margin + float64(i%cols)*size.Width
+			y := cfg.Margin + float64((i/cols)%rows)*size.Height
+			if err := drawLabel(pdf, tr, product, x, y, size, req.Symbology); err != nil {
+				return nil, err
+			}
+			i++
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLabel draws one label: name and price on the left above a linear barcode, and a QR code
+// of the product reference on the right.
+func drawLabel(pdf *gofpdf.Fpdf, tr func(string) string, product models.Product, x, y float64, size setting.LabelSize, symbology models.LabelSymbology) error {
+	var code, text string
+	switch {
+	case symbology == models.LabelSymbologyEAN13 || (symbology == models.LabelSymbologyAuto && product.Barcode != ""):
+		if product.Barcode == "" {
+			return fmt.Errorf("%w: %s", ErrLabelBarcodeEmpty, product.ProductReference)
+		}
+		text = product.Barcode
+		// UPC-A is printed in its EAN-13 form, which scanners read as the same code.
+		if len(text) == 12 {
+			text = "0" + text
+		}
+		code = barcode.RegisterEAN(pdf, text)
+	default:
+		text = product.ProductReference
+		code = barcode.RegisterCode128(pdf, text)
+	}
+
+	w, h := size.Width, size.Height
+	pad := h * 0.06
+	fontSize := min(10, h/4)
+	// points to mm, with some line spacing
+	lineH := fontSize * 0.3528 * 1.2
+
+	pdf.SetDrawColor(200, 200, 200)
+	pdf.Rect(x, y, w, h, "D")
+
+	qrSide := h - 2*pad
+	qrCode := barcode.RegisterQR(pdf, product.ProductReference, qr.M, qr.Auto)
+	barcode.Barcode(pdf, qrCode, x+w-pad-qrSide, y+pad, qrSide, qrSide, false)
+
+	textW := w - qrSide - 3*pad
+	pdf.SetFont("Arial", "B", fontSize)
+	pdf.SetXY(x+pad, y+pad)
+	pdf.CellFormat(textW, lineH, fitText(pdf, tr(product.ProductName), textW), "", 0, "L", false, 0, "")
+	pdf.SetFont("Arial", "", fontSize)
+	pdf.SetXY(x+pad, y+pad+lineH)
+	pdf.CellFormat(textW, lineH, fmt.Sprintf("Price: %d", product.Price), "", 0, "L", false, 0, "")
+
+	textLineH := lineH * 0.8
+	barcodeY := y + pad + 2*lineH + pad/2
+	barcodeH := y + h - pad - textLineH - barcodeY
+	barcode.Barcode(pdf, code, x+pad, barcodeY, textW, barcodeH, false)
+	pdf.SetFont("Arial", "", fontSize*0.8)
+	pdf.SetXY(x+pad, y+h-pad-textLineH)
+	pdf.CellFormat(textW, textLineH, fitText(pdf, tr(text), textW), "", 0, "C", false, 0, "")
+
+	return pdf.Error()
+}
+
+// fitText shortens text with "..." until it fits in width with the current font.
+func fitText(pdf *gofpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
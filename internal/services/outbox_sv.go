@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"time"
+)
+
+// outboxBatchSize is the number of events published per transaction of the relay.
+const outboxBatchSize = 100
+
+type OutboxService interface {
+	Relay(ctx context.Context) (int, error)
+	DeletePublished(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type outboxService struct {
+	outboxRepo  repo.OutboxRepo
+	eventRepo   repo.EventRepo
+	webhookRepo repo.WebhookRepo
+	maxAttempts int
+}
+
+func newOutboxService(outboxRepo repo.OutboxRepo, eventRepo repo.EventRepo, webhookRepo repo.WebhookRepo, maxAttempts int) OutboxService {
+	return &outboxService{
+		outboxRepo:  outboxRepo,
+		eventRepo:   eventRepo,
+		webhookRepo: webhookRepo,
+		maxAttempts: maxAttempts,
+	}
+}
+
+// Relay publishes the pending events in batches until none is left or one fails, and returns
+// the number published. An event is handed to publish at least once; publish makes it take
+// effect exactly once.
+func (ob *outboxService) Relay(ctx context.Context) (int, error) {
+	total := 0
+	for {
+		published, err := ob.outboxRepo.RelayEvents(ctx, outboxBatchSize, ob.maxAttempts, ob.publish)
+		total += published
+		if err != nil || published < outboxBatchSize {
+			return total, err
+		}
+	}
+}
+
+func (ob *outboxService) DeletePublished(ctx context.Context, retention time.Duration) (int64, error) {
+	return ob.outboxRepo.DeletePublished(ctx, time.Now().UTC().Add(-retention))
+}
+
+// publish applies an event to the product counters, appends its domain events to the event
+// stream and queues them for the webhooks subscribing to them. Each step is skipped when an
+// earlier attempt completed it.
+func (ob *outboxService) publish(ctx context.Context, event models.OutboxEvent) error {
+	increments, err := productCounterIncrements(event)
+	if err != nil {
+		return err
+	}
+	if len(increments) > 0 {
+		if err := ob.outboxRepo.IncrementOnce(ctx, event.EventID, increments); err != nil {
+			return err
+		}
+	}
+
+	events, err := domainEvents(event)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+	if err := ob.eventRepo.AppendOnce(ctx, event.EventID, events); err != nil {
+		return err
+	}
+
+	webhooks, err := ob.webhookRepo.GetEnabledWebhooks(ctx)
+	if err != nil {
+		return err
+	}
+	return ob.webhookRepo.EnqueueDeliveries(ctx, webhookDeliveries(webhooks, events))
+}
+
+// productCounterIncrements returns the changes of the product counters per supplier, per
+// category and in total caused by a product event.
+func productCounterIncrements(event models.OutboxEvent) (map[string]int64, error) {
+	switch event.EventType {
+	case models.OutboxProductCreated, models.OutboxProductUpdated, models.OutboxProductDeleted:
+	default:
+		return nil, nil
+	}
+
+	var before, after models.Product
+	if err := event.Change.Decode(&before, &after); err != nil {
+		return nil, err
+	}
+
+	increments := make(map[string]int64)
+	count := func(product models.Product, n int64) {
+		increments[fmt.Sprintf(models.SupplierProductsKey, product.SupplierID)] += n
+		increments[fmt.Sprintf(models.CategoryProductsKey, product.ProductCategoryID)] += n
+		increments[models.TotalProductsKey] += n
+	}
+	switch event.EventType {
+	case models.OutboxProductCreated:
+		count(after, 1)
+	case models.OutboxProductDeleted:
+		count(before, -1)
+	case models.OutboxProductUpdated:
+		count(before, -1)
+		count(after, 1)
+	}
+
+	for key, increment := range increments {
+		if increment == 0 {
+			delete(increments, key)
+		}
+	}
+	return increments, nil
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"stock-management/pkgs/utils"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type PlanningService interface {
+	GetSuggestions(ctx context.Context, req models.PlanningSuggestionReq) (*models.PlanningSuggestionRp, error)
+	ConvertToPurchaseOrders(ctx context.Context, req models.PlanningConvertReq) ([]models.PurchaseOrder, error)
+	GetPurchaseOrderList(ctx context.Context, req models.PurchaseOrderSearchReq) (*models.SearchRp, error)
+}
+
+type planningService struct {
+	productRepo       repo.ProductRepo
+	stockMovementRepo repo.StockMovementRepo
+	purchaseOrderRepo repo.PurchaseOrderRepo
+}
+
+func newPlanningService(productRepo repo.ProductRepo, stockMovementRepo repo.StockMovementRepo, purchaseOrderRepo repo.PurchaseOrderRepo) PlanningService {
+	return &planningService{
+		productRepo:       productRepo,
+		stockMovementRepo: stockMovementRepo,
+		purchaseOrderRepo: purchaseOrderRepo,
+	}
+}
+
+// GetSuggestions forecasts the daily demand of every product from its daily issues and suggests
+// ordering enough to cover the supplier lead time, the review period and the safety stock,
+// minus the stock on hand and already on order.
+func (ps *planningService) GetSuggestions(ctx context.Context, req models.PlanningSuggestionReq) (*models.PlanningSuggestionRp, error) {
+	products, _, _, err := ps.productRepo.GetProductList(ctx, models.ProductSearchReq{SupplierUUIDs: req.SupplierUUIDs})
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	from := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1-req.HistoryDays)
+	// Without a supplier filter the issues of every product are read, rather than passing the
+	// whole catalog as bind parameters; issues of products not listed are skipped below.
+	var productIDs []uuid.UUID
+	if len(req.SupplierUUIDs) > 0 {
+		productIDs = make([]uuid.UUID, 0, len(products))
+		for _, product := range products {
+			productIDs = append(productIDs, product.ProductID)
+		}
+	}
+
+	rows, err := ps.stockMovementRepo.GetDailyIssues(ctx, from, productIDs)
+	if err != nil {
+		return nil, err
+	}
+	series := make(map[uuid.UUID][]float64, len(products))
+	for _, row := range rows {
+		rowDay := time.Date(row.Day.Year(), row.Day.Month(), row.Day.Day(), 0, 0, 0, 0, time.UTC)
+		day := int(rowDay.Sub(from).Hours() / 24)
+		if day < 0 || day >= req.HistoryDays {
+			continue
+		}
+		if series[row.ProductID] == nil {
+			series[row.ProductID] = make([]float64, req.HistoryDays)
+		}
+		series[row.ProductID][day] += float64(row.Quantity)
+	}
+
+	onOrder, err := ps.purchaseOrderRepo.GetOnOrderQuantities(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.PlanningSuggestionRp{
+		Method:      req.Method,
+		HistoryDays: req.HistoryDays,
+		Data:        []models.PlanningSuggestion{},
+	}
+	for _, product := range products {
+		history, ok := series[product.ProductID]
+		if !ok {
+			continue
+		}
+
+		var daily float64
+		if req.Method == models.ForecastExponentialSmoothing {
+			daily = utils.ExponentialSmoothing(history, req.Alpha)
+		} else {
+			daily = utils.MovingAverage(history, req.Window)
+		}
+
+		leadTimeDemand := daily * float64(product.Supplier.LeadTimeDays+req.ReviewDays)
+		safetyStock := daily * float64(req.SafetyDays)
+		suggested := int(math.Ceil(leadTimeDemand + safetyStock - float64(product.Quantity) - float64(onOrder[product.ProductID])))
+		if suggested <= 0 {
+			continue
+		}
+
+		result.Data = append(result.Data, models.PlanningSuggestion{
+			ProductID:           product.ProductID,
+			ProductName:         product.ProductName,
+			ProductReference:    product.ProductReference,
+			SupplierID:          product.SupplierID,
+			SupplierName:        product.Supplier.SupplierName,
+			LeadTimeDays:        product.Supplier.LeadTimeDays,
+			OnHand:              product.Quantity,
+			OnOrder:             onOrder[product.ProductID],
+			ForecastDailyDemand: decimal.NewFromFloat(daily).Round(2),
+			DemandOverLeadTime:  decimal.NewFromFloat(leadTimeDemand).Round(2),
+			SafetyStock:         decimal.NewFromFloat(safetyStock).Round(2),
+			SuggestedQuantity:   suggested,
+			UnitPrice:           product.Price,
+		})
+	}
+
+	sort.Slice(result.Data, func(i, j int) bool {
+		if result.Data[i].SupplierName == result.Data[j].SupplierName {
+			return result.Data[i].ProductName < result.Data[j].ProductName
+		}
+		return result.Data[i].SupplierName < result.Data[j].SupplierName
+	})
+	return result, nil
+}
+
+// ConvertToPurchaseOrders creates one draft purchase order per supplier of the given products.
+func (ps *planningService) ConvertToPurchaseOrders(ctx context.Context, req models.PlanningConvertReq) ([]models.PurchaseOrder, error) {
+	quantities := make(map[uuid.UUID]int, len(req.Items))
+	ids := make([]uuid.UUID, 0, len(req.Items))
+	for _, item := range req.Items {
+		id := uuid.MustParse(item.ProductID)
+		if _, ok := quantities[id]; !ok {
+			ids = append(ids, id)
+		}
+		quantities[id] += item.Quantity
+	}
+
+	products, err := ps.productRepo.GetProductsByIds(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	if len(products) != len(ids) {
+		return nil, errors.New("invalid product")
+	}
+
+	now := time.Now().UTC()
+	orders := []models.PurchaseOrder{}
+	indexes := make(map[uuid.UUID]int)
+	for _, product := range products {
+		idx, ok := indexes[product.SupplierID]
+		if !ok {
+			idx = len(orders)
+			indexes[product.SupplierID] = idx
+			orders = append(orders, models.PurchaseOrder{
+				PurchaseOrderID: uuid.New(),
+				SupplierID:      product.SupplierID,
+				Status:          models.PurchaseOrderDraft,
+				Note:            req.Note,
+				CreatedAt:       now,
+				UpdatedAt:       now,
+			})
+		}
+		orders[idx].Lines = append(orders[idx].Lines, models.PurchaseOrderLine{
+			LineID:          uuid.New(),
+			PurchaseOrderID: orders[idx].PurchaseOrderID,
+			ProductID:       product.ProductID,
+			Quantity:        quantities[product.ProductID],
+			UnitPrice:       product.Price,
+		})
+	}
+
+	if err := ps.purchaseOrderRepo.CreatePurchaseOrders(ctx, orders); err != nil {
+		return nil, fmt.Errorf("create purchase orders: %w", err)
+	}
+	return orders, nil
+}
+
+func (ps *planningService) GetPurchaseOrderList(ctx context.Context, req models.PurchaseOrderSearchReq) (*models.SearchRp, error) {
+	rs, offset, err := ps.purchaseOrderRepo.GetPurchaseOrderList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.SearchRp{
+		Data: rs,
+		Pagination: models.Pagination{
+			Offset: offset,
+			Limit:  req.Limit,
+		},
+	}
+	return result, nil
+}
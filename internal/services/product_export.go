@@ -0,0 +1,77 @@
+package services
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"stock-management/internal/models"
+	"strconv"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+// renderProducts renders the product list export in the given format.
+func renderProducts(products []models.Product, format models.ReportFormat) ([]byte, error) {
+	if format == models.ReportFormatCSV {
+		return renderProductsCSV(products)
+	}
+	return renderProductsPDF(products)
+}
+
+func renderProductsPDF(products []models.Product) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+	pdf.SetFont("Arial", "B", 12)
+	pdf.Cell(40, 10, "Product List")
+	pdf.Ln(10)
+	pdf.SetFont("Arial", "B", 10)
+	pdf.Cell(30, 10, "Product Name")
+	pdf.Cell(20, 10, "Price")
+	pdf.Cell(20, 10, "Quantity")
+	pdf.Cell(30, 10, "Stock Location")
+	pdf.Cell(30, 10, "Supplier")
+	pdf.Cell(30, 10, "Category")
+	pdf.Ln(10)
+
+	pdf.SetFont("Arial", "", 10)
+	for _, product := range products {
+		pdf.Cell(30, 10, product.ProductName)
+		pdf.Cell(20, 10, fmt.Sprintf("%d", product.Price))
+		pdf.Cell(20, 10, fmt.Sprintf("%d", product.Quantity))
+		pdf.Cell(30, 10, product.StockLocation)
+		pdf.Cell(30, 10, product.Supplier.SupplierName)
+		pdf.Cell(30, 10, product.ProductCategory.ProductCategoryName)
+		pdf.Ln(10)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func renderProductsCSV(products []models.Product) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	_ = w.Write([]string{"product_reference", "product_name", "barcode", "status", "price", "quantity", "stock_location", "supplier", "category", "date_created"})
+	for _, product := range products {
+		_ = w.Write([]string{
+			product.ProductReference,
+			product.ProductName,
+			product.Barcode,
+			string(product.Status),
+			strconv.Itoa(product.Price),
+			strconv.Itoa(product.Quantity),
+			product.StockLocation,
+			product.Supplier.SupplierName,
+			product.ProductCategory.ProductCategoryName,
+			product.DateCreated.Format("2006-01-02"),
+		})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -3,9 +3,13 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
+	"stock-management/global"
 	"stock-management/internal/models"
 	"stock-management/internal/repo"
 	"stock-management/pkgs/utils"
+	"strconv"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
@@ -14,56 +18,272 @@ import (
 type ProductService interface {
 	GetProductList(ctx context.Context, req models.ProductSearchReq) (*models.ProductSearchRp, error)
 	GetProduct(ctx context.Context, id uuid.UUID) (*models.Product, error)
+	ExportProducts(ctx context.Context, req models.ProductSearchReq, format models.ReportFormat) ([]byte, error)
 	CreateProduct(ctx context.Context, product models.ProductCreateReq) (models.Product, error)
 	UpdateProduct(ctx context.Context, product models.ProductUpdateReq) (models.Product, error)
-	GetProductPerCategory(ctx context.Context) (map[string]decimal.Decimal, error)
-	GetProductPerSupplier(ctx context.Context) (map[string]decimal.Decimal, error)
+	PatchProduct(ctx context.Context, req models.ProductPatchReq) (models.Product, error)
+	GetProductByCode(ctx context.Context, code string) (*models.Product, error)
+	DeleteProduct(ctx context.Context, id uuid.UUID, version int) error
+	GetProductPerCategory(ctx context.Context, metric models.StatisticsMetric) (*models.StatisticsRp, error)
+	GetProductPerSupplier(ctx context.Context, metric models.StatisticsMetric) (*models.StatisticsRp, error)
 	GetProductDistance(ctx context.Context, id uuid.UUID, ip string) (*models.ProductDistanceRp, error)
+	GetCacheStats() models.CacheStatsRp
 }
 
 type productService struct {
-	productRepo repo.ProductRepo
+	productRepo         repo.ProductRepo
+	productCategoryRepo repo.ProductCategoryRepo
+	supplierRepo        repo.SupplierRepo
 }
 
-func newProductService(productRepo repo.ProductRepo) ProductService {
+func newProductService(productRepo repo.ProductRepo, productCategoryRepo repo.ProductCategoryRepo, supplierRepo repo.SupplierRepo) ProductService {
 	return &productService{
-		productRepo: productRepo,
+		productRepo:         productRepo,
+		productCategoryRepo: productCategoryRepo,
+		supplierRepo:        supplierRepo,
 	}
 }
 
 func (ps *productService) GetProductList(ctx context.Context, req models.ProductSearchReq) (*models.ProductSearchRp, error) {
-	rs, offset, err := ps.productRepo.GetProductList(ctx, req)
+	products, total, hasMore, err := ps.productRepo.GetProductList(ctx, req)
 	if err != nil {
 		return nil, err
 	}
 	result := &models.ProductSearchRp{
-		Data: rs,
+		Data: products,
 		Pagination: models.Pagination{
-			Offset: offset,
-			Limit:  req.Limit,
+			Limit: req.Limit,
 		},
+		Total:   total,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		if req.After == nil {
+			result.Offset = req.Offset + req.Limit
+		}
+		if !req.OrderByRelevance() {
+			result.NextCursor = req.NextCursor(products[len(products)-1])
+		}
+	}
+
+	if req.Facets {
+		// width_bucket needs ascending bounds.
+		bounds := append([]int(nil), global.Config.Product.PriceBuckets...)
+		sort.Ints(bounds)
+		rows, err := ps.productRepo.GetProductFacets(ctx, req, bounds)
+		if err != nil {
+			return nil, err
+		}
+		result.Facets = buildProductFacets(rows, bounds)
 	}
 	return result, nil
 }
 
+// buildProductFacets groups the facet rows, most frequent values first. Every price bucket is
+// listed, empty ones with a zero count; prices below the first bound are left out.
+func buildProductFacets(rows []models.FacetRow, bounds []int) *models.ProductFacets {
+	facets := &models.ProductFacets{
+		Status:        []models.FacetCount{},
+		Category:      []models.FacetCount{},
+		Supplier:      []models.FacetCount{},
+		StockLocation: []models.FacetCount{},
+		Price:         make([]models.PriceBucketCount, len(bounds)),
+	}
+	for i, from := range bounds {
+		facets.Price[i].From = from
+		if i+1 < len(bounds) {
+			to := bounds[i+1] - 1
+			facets.Price[i].To = &to
+		}
+	}
+
+	for _, row := range rows {
+		count := models.FacetCount{Value: row.Value, Label: row.Label, Count: row.Count}
+		switch row.Facet {
+		case models.FacetStatus:
+			facets.Status = append(facets.Status, count)
+		case models.FacetCategory:
+			facets.Category = append(facets.Category, count)
+		case models.FacetSupplier:
+			facets.Supplier = append(facets.Supplier, count)
+		case models.FacetStockLocation:
+			facets.StockLocation = append(facets.StockLocation, count)
+		case models.FacetPrice:
+			// Bucket i holds bounds[i-1] <= price < bounds[i], 0 is below the first bound.
+			bucket, err := strconv.Atoi(row.Value)
+			if err == nil && bucket > 0 && bucket <= len(bounds) {
+				facets.Price[bucket-1].Count += row.Count
+			}
+		}
+	}
+
+	for _, counts := range [][]models.FacetCount{facets.Status, facets.Category, facets.Supplier, facets.StockLocation} {
+		sort.Slice(counts, func(i, j int) bool {
+			if counts[i].Count != counts[j].Count {
+				return counts[i].Count > counts[j].Count
+			}
+			return counts[i].Value < counts[j].Value
+		})
+	}
+	return facets
+}
+
+// ExportProducts renders all the products matching req, ignoring its paging, as a PDF or CSV
+// file.
+func (ps *productService) ExportProducts(ctx context.Context, req models.ProductSearchReq, format models.ReportFormat) ([]byte, error) {
+	req.Pagination = models.Pagination{}
+	req.After = nil
+	products, _, _, err := ps.productRepo.GetProductList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return renderProducts(products, format)
+}
+
 func (ps *productService) GetProduct(ctx context.Context, id uuid.UUID) (*models.Product, error) {
 	return ps.productRepo.GetProduct(ctx, id)
 }
 
 func (ps *productService) CreateProduct(ctx context.Context, product models.ProductCreateReq) (models.Product, error) {
+	if product.ProductReference == "" {
+		seq, err := ps.productRepo.NextReferenceSequence(ctx)
+		if err != nil {
+			return models.Product{}, err
+		}
+		cfg := global.Config.Product.Reference
+		product.ProductReference = utils.FormatReference(cfg.Prefix, cfg.DateFormat, cfg.Padding, cfg.CheckDigit, seq, time.Now())
+	}
 	return ps.productRepo.CreateProduct(ctx, product)
 }
 
+func (ps *productService) DeleteProduct(ctx context.Context, id uuid.UUID, version int) error {
+	_, err := ps.productRepo.DeleteProduct(ctx, id, version)
+	return err
+}
+
+func (ps *productService) GetProductByCode(ctx context.Context, code string) (*models.Product, error) {
+	return ps.productRepo.GetProductByCode(ctx, code)
+}
+
 func (ps *productService) UpdateProduct(ctx context.Context, product models.ProductUpdateReq) (models.Product, error) {
 	return ps.productRepo.UpdateProduct(ctx, product)
 }
 
-func (ps *productService) GetProductPerCategory(ctx context.Context) (map[string]decimal.Decimal, error) {
-	return ps.productRepo.GetProductPercentagePerKey(ctx, models.CategoryProductsScanKey)
+func (ps *productService) PatchProduct(ctx context.Context, req models.ProductPatchReq) (models.Product, error) {
+	return ps.productRepo.PatchProduct(ctx, req)
 }
 
-func (ps *productService) GetProductPerSupplier(ctx context.Context) (map[string]decimal.Decimal, error) {
-	return ps.productRepo.GetProductPercentagePerKey(ctx, models.SupplierProductsScanKey)
+func (ps *productService) GetProductPerCategory(ctx context.Context, metric models.StatisticsMetric) (*models.StatisticsRp, error) {
+	var values map[string]int64
+	var err error
+	if metric == models.StatisticsMetricCount {
+		values, err = ps.productRepo.GetProductCountPerKey(ctx, models.CategoryProductsScanKey)
+	} else {
+		values, err = ps.productRepo.GetProductSumPerGroup(ctx, models.StatisticsGroupByCategory, metric)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := getGroupNames(ctx, ps.productRepo, ps.productCategoryRepo, ps.supplierRepo, models.StatisticsGroupByCategory, getUUIDsFromKeys(values))
+	if err != nil {
+		return nil, err
+	}
+	return buildStatistics(metric, values, names), nil
+}
+
+func (ps *productService) GetProductPerSupplier(ctx context.Context, metric models.StatisticsMetric) (*models.StatisticsRp, error) {
+	var values map[string]int64
+	var err error
+	if metric == models.StatisticsMetricCount {
+		values, err = ps.productRepo.GetProductCountPerKey(ctx, models.SupplierProductsScanKey)
+	} else {
+		values, err = ps.productRepo.GetProductSumPerGroup(ctx, models.StatisticsGroupBySupplier, metric)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names, err := getGroupNames(ctx, ps.productRepo, ps.productCategoryRepo, ps.supplierRepo, models.StatisticsGroupBySupplier, getUUIDsFromKeys(values))
+	if err != nil {
+		return nil, err
+	}
+	return buildStatistics(metric, values, names), nil
+}
+
+func getUUIDsFromKeys(values map[string]int64) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(values))
+	for key := range values {
+		id, err := uuid.Parse(key)
+		if err == nil {
+			ids = append(ids, id)
+		}
+	}
+	return ids
+}
+
+func buildStatistics(metric models.StatisticsMetric, values map[string]int64, names map[string]string) *models.StatisticsRp {
+	var total int64
+	for _, value := range values {
+		total += value
+	}
+
+	result := &models.StatisticsRp{
+		Metric: metric,
+		Total:  decimal.NewFromInt(total),
+		Data:   make([]models.StatisticsItem, 0, len(values)),
+	}
+	for id, value := range values {
+		item := models.StatisticsItem{
+			ID:         id,
+			Name:       names[id],
+			Value:      decimal.NewFromInt(value),
+			Percentage: decimal.Zero,
+		}
+		if total != 0 {
+			item.Percentage = decimal.NewFromInt(value).Mul(decimal.NewFromInt(100)).Div(decimal.NewFromInt(total)).Round(2)
+		}
+		result.Data = append(result.Data, item)
+	}
+	sort.Slice(result.Data, func(i, j int) bool {
+		if result.Data[i].Value.Equal(result.Data[j].Value) {
+			return result.Data[i].Name < result.Data[j].Name
+		}
+		return result.Data[i].Value.GreaterThan(result.Data[j].Value)
+	})
+	return result
+}
+
+// getGroupNames resolves product, category or supplier names for statistics keyed by ID.
+func getGroupNames(ctx context.Context, productRepo repo.ProductRepo, productCategoryRepo repo.ProductCategoryRepo, supplierRepo repo.SupplierRepo, groupBy models.StatisticsGroupBy, ids []uuid.UUID) (map[string]string, error) {
+	names := make(map[string]string, len(ids))
+	switch groupBy {
+	case models.StatisticsGroupByProduct:
+		products, err := productRepo.GetProductsByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, product := range products {
+			names[product.ProductID.String()] = product.ProductName
+		}
+	case models.StatisticsGroupByCategory:
+		categories, err := productCategoryRepo.GetCategoriesByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, category := range categories {
+			names[category.ProductCategoryID.String()] = category.ProductCategoryName
+		}
+	case models.StatisticsGroupBySupplier:
+		suppliers, err := supplierRepo.GetSuppliersByIds(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		for _, supplier := range suppliers {
+			names[supplier.SupplierID.String()] = supplier.SupplierName
+		}
+	}
+	return names, nil
 }
 
 func (ps *productService) GetProductDistance(ctx context.Context, id uuid.UUID, ip string) (*models.ProductDistanceRp, error) {
@@ -100,3 +320,7 @@ func (ps *productService) GetProductDistance(ctx context.Context, id uuid.UUID,
 	}
 	return &rs, nil
 }
+
+func (ps *productService) GetCacheStats() models.CacheStatsRp {
+	return ps.productRepo.GetCacheStats()
+}
@@ -0,0 +1,277 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"stock-management/global"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleExists   = errors.New("role already exists")
+	ErrBuiltInRole  = errors.New("built-in admin role cannot be changed")
+	ErrRoleInUse    = errors.New("role is assigned to users")
+	ErrUserNotFound = errors.New("user not found")
+	ErrLastAdmin    = errors.New("the last admin cannot lose the admin role")
+)
+
+type RbacService interface {
+	LoadPermissions(ctx context.Context, user *models.AuthUser) error
+	EnsureDefaultRoles(ctx context.Context) error
+	GetRoleList(ctx context.Context) ([]models.Role, error)
+	CreateRole(ctx context.Context, req models.RoleCreateReq) (*models.Role, error)
+	UpdateRole(ctx context.Context, req models.RoleUpdateReq) (*models.Role, error)
+	DeleteRole(ctx context.Context, id uuid.UUID) error
+	GetUserList(ctx context.Context, req models.UserSearchReq) (*models.SearchRp, error)
+	AssignUserRoles(ctx context.Context, req models.UserAssignRolesReq) (*models.UserWithRoles, error)
+}
+
+type rbacService struct {
+	roleRepo repo.RoleRepo
+	userRepo repo.UserRepo
+}
+
+func newRbacService(roleRepo repo.RoleRepo, userRepo repo.UserRepo) RbacService {
+	return &rbacService{
+		roleRepo: roleRepo,
+		userRepo: userRepo,
+	}
+}
+
+// LoadPermissions fills the roles and permissions of an authenticated user. They are read on
+// every request so that role changes apply immediately, without waiting for tokens to expire.
+func (rb *rbacService) LoadPermissions(ctx context.Context, user *models.AuthUser) error {
+	userRoles, err := rb.roleRepo.GetUserRoles(ctx, []uuid.UUID{user.UserID})
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[string]bool)
+	user.Roles = []string{}
+	user.Permissions = []string{}
+	for _, role := range userRoles[user.UserID] {
+		user.Roles = append(user.Roles, role.Name)
+		for _, p := range role.Permissions {
+			if !seen[p] {
+				seen[p] = true
+				user.Permissions = append(user.Permissions, p)
+			}
+		}
+	}
+	return nil
+}
+
+// EnsureDefaultRoles creates the built-in roles that do not exist yet and, while nobody holds
+// the admin role, grants it to the configured admin user.
+func (rb *rbacService) EnsureDefaultRoles(ctx context.Context) error {
+	var adminRole *models.Role
+	for name, permissions := range models.DefaultRoles {
+		role, err := rb.roleRepo.GetRoleByName(ctx, name)
+		if err != nil {
+			return err
+		}
+		if role == nil {
+			role, err = rb.roleRepo.CreateRole(ctx, models.Role{
+				RoleID:      uuid.New(),
+				Name:        name,
+				BuiltIn:     true,
+				Permissions: permissions,
+				CreatedAt:   time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if name == models.RoleAdmin {
+			// Keep the admin role in sync with permissions added since it was created.
+			if len(role.Permissions) != len(models.AllPermissions) {
+				role.Permissions = models.AllPermissions
+				if role, err = rb.roleRepo.UpdateRole(ctx, *role); err != nil {
+					return err
+				}
+			}
+			adminRole = role
+		}
+	}
+
+	count, err := rb.roleRepo.CountUsersWithRole(ctx, adminRole.RoleID)
+	if err != nil {
+		return err
+	}
+	if count > 0 || global.Config.Auth.AdminUsername == "" {
+		return nil
+	}
+	admin, err := rb.userRepo.GetUserByUsername(ctx, global.Config.Auth.AdminUsername)
+	if err != nil || admin == nil {
+		return err
+	}
+	_, err = rb.roleRepo.SetUserRoles(ctx, admin.UserID, []uuid.UUID{adminRole.RoleID}, uuid.Nil)
+	return err
+}
+
+func (rb *rbacService) GetRoleList(ctx context.Context) ([]models.Role, error) {
+	return rb.roleRepo.GetRoleList(ctx)
+}
+
+func (rb *rbacService) CreateRole(ctx context.Context, req models.RoleCreateReq) (*models.Role, error) {
+	exists, err := rb.roleRepo.GetRoleByName(ctx, req.Name)
+	if err != nil {
+		return nil, err
+	}
+	if exists != nil {
+		return nil, ErrRoleExists
+	}
+	return rb.roleRepo.CreateRole(ctx, models.Role{
+		RoleID:      uuid.New(),
+		Name:        req.Name,
+		Description: req.Description,
+		Permissions: uniquePermissions(req.Permissions),
+		CreatedAt:   time.Now().UTC(),
+	})
+}
+
+func (rb *rbacService) UpdateRole(ctx context.Context, req models.RoleUpdateReq) (*models.Role, error) {
+	role, err := rb.roleRepo.GetRole(ctx, uuid.MustParse(req.RoleID))
+	if err != nil {
+		return nil, err
+	}
+	if role == nil {
+		return nil, ErrRoleNotFound
+	}
+	// The admin role always keeps every permission so that roles can't lock everyone out.
+	if role.Name == models.RoleAdmin {
+		return nil, ErrBuiltInRole
+	}
+
+	role.Description = req.Description
+	role.Permissions = uniquePermissions(req.Permissions)
+	return rb.roleRepo.UpdateRole(ctx, *role)
+}
+
+func (rb *rbacService) DeleteRole(ctx context.Context, id uuid.UUID) error {
+	role, err := rb.roleRepo.GetRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if role == nil {
+		return ErrRoleNotFound
+	}
+	if role.Name == models.RoleAdmin {
+		return ErrBuiltInRole
+	}
+	count, err := rb.roleRepo.CountUsersWithRole(ctx, id)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrRoleInUse
+	}
+	return rb.roleRepo.DeleteRole(ctx, id)
+}
+
+func (rb *rbacService) GetUserList(ctx context.Context, req models.UserSearchReq) (*models.SearchRp, error) {
+	users, nextOffset, err := rb.userRepo.GetUserList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		userIDs = append(userIDs, user.UserID)
+	}
+	userRoles, err := rb.roleRepo.GetUserRoles(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.UserWithRoles, 0, len(users))
+	for _, user := range users {
+		result = append(result, models.UserWithRoles{
+			User:  user,
+			Roles: roleNames(userRoles[user.UserID]),
+		})
+	}
+	return &models.SearchRp{
+		Data: result,
+		Pagination: models.Pagination{
+			Offset: nextOffset,
+			Limit:  req.Limit,
+		},
+	}, nil
+}
+
+func (rb *rbacService) AssignUserRoles(ctx context.Context, req models.UserAssignRolesReq) (*models.UserWithRoles, error) {
+	user, err := rb.userRepo.GetUser(ctx, uuid.MustParse(req.UserID))
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, ErrUserNotFound
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(req.RoleIDs))
+	roles := make([]models.Role, 0, len(req.RoleIDs))
+	seen := make(map[uuid.UUID]bool)
+	for _, id := range req.RoleIDs {
+		roleID := uuid.MustParse(id)
+		if seen[roleID] {
+			continue
+		}
+		seen[roleID] = true
+		role, err := rb.roleRepo.GetRole(ctx, roleID)
+		if err != nil {
+			return nil, err
+		}
+		if role == nil {
+			return nil, ErrRoleNotFound
+		}
+		roleIDs = append(roleIDs, roleID)
+		roles = append(roles, *role)
+	}
+	// The user may not lose the admin role when nobody else has it: nobody could manage roles
+	// until a restart.
+	adminRole, err := rb.roleRepo.GetRoleByName(ctx, models.RoleAdmin)
+	if err != nil {
+		return nil, err
+	}
+	keepRoleID := uuid.Nil
+	if adminRole != nil {
+		keepRoleID = adminRole.RoleID
+	}
+	replaced, err := rb.roleRepo.SetUserRoles(ctx, user.UserID, roleIDs, keepRoleID)
+	if err != nil {
+		return nil, err
+	}
+	if !replaced {
+		return nil, ErrLastAdmin
+	}
+	return &models.UserWithRoles{
+		User:  *user,
+		Roles: roleNames(roles),
+	}, nil
+}
+
+func uniquePermissions(permissions []string) []string {
+	seen := make(map[string]bool)
+	result := make([]string, 0, len(permissions))
+	for _, p := range permissions {
+		if !seen[p] {
+			seen[p] = true
+			result = append(result, p)
+		}
+	}
+	return result
+}
+
+func roleNames(roles []models.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
@@ -0,0 +1,463 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"stock-management/global"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"stock-management/pkgs/response"
+	"stock-management/pkgs/utils"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+)
+
+// reportLockTTL is how long an instance holds the run of a schedule, so that a schedule fires
+// once when several instances are running.
+const reportLockTTL = 10 * time.Minute
+
+type ReportService interface {
+	CreateSavedSearch(ctx context.Context, userID uuid.UUID, req models.SavedSearchCreateReq) (*models.SavedSearch, error)
+	UpdateSavedSearch(ctx context.Context, userID uuid.UUID, req models.SavedSearchUpdateReq) (*models.SavedSearch, error)
+	DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error
+	GetSavedSearchList(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error)
+	CreateSchedule(ctx context.Context, userID uuid.UUID, req models.ReportScheduleCreateReq) (*models.ReportSchedule, error)
+	UpdateSchedule(ctx context.Context, userID uuid.UUID, req models.ReportScheduleUpdateReq) (*models.ReportSchedule, error)
+	DeleteSchedule(ctx context.Context, userID, id uuid.UUID) error
+	GetScheduleList(ctx context.Context, userID uuid.UUID) ([]models.ReportSchedule, error)
+	GetRunList(ctx context.Context, userID uuid.UUID, req models.ReportRunSearchReq) (*models.SearchRp, error)
+	RunSchedule(ctx context.Context, userID, id uuid.UUID) (*models.ReportRun, error)
+	StartSchedules(ctx context.Context, c *cron.Cron) error
+	SyncSchedules(ctx context.Context) error
+}
+
+type reportService struct {
+	reportRepo  repo.ReportRepo
+	productRepo repo.ProductRepo
+	rdb         *redis.Client
+
+	mu      sync.Mutex
+	cron    *cron.Cron
+	entries map[uuid.UUID]scheduleEntry
+}
+
+// scheduleEntry is the cron entry of a schedule and the expression it was added with.
+type scheduleEntry struct {
+	id   cron.EntryID
+	cron string
+}
+
+func newReportService(reportRepo repo.ReportRepo, productRepo repo.ProductRepo, rdb *redis.Client) ReportService {
+	return &reportService{
+		reportRepo:  reportRepo,
+		productRepo: productRepo,
+		rdb:         rdb,
+		entries:     make(map[uuid.UUID]scheduleEntry),
+	}
+}
+
+func (rp *reportService) CreateSavedSearch(ctx context.Context, userID uuid.UUID, req models.SavedSearchCreateReq) (*models.SavedSearch, error) {
+	now := time.Now().UTC()
+	search := models.SavedSearch{
+		SavedSearchID: uuid.New(),
+		UserID:        userID,
+		Name:          req.Name,
+		Search:        req.Search,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := rp.reportRepo.CreateSavedSearch(ctx, search); err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (rp *reportService) UpdateSavedSearch(ctx context.Context, userID uuid.UUID, req models.SavedSearchUpdateReq) (*models.SavedSearch, error) {
+	updated, err := rp.reportRepo.UpdateSavedSearch(ctx, models.SavedSearch{
+		SavedSearchID: req.SavedSearchUUID,
+		UserID:        userID,
+		Name:          req.Name,
+		Search:        req.Search,
+		UpdatedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, models.ErrSavedSearchNotFound
+	}
+	return rp.reportRepo.GetSavedSearch(ctx, req.SavedSearchUUID)
+}
+
+// DeleteSavedSearch deletes a saved search of the user along with the schedules running it.
+func (rp *reportService) DeleteSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	scheduleIDs, deleted, err := rp.reportRepo.DeleteSavedSearch(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return models.ErrSavedSearchNotFound
+	}
+	for _, scheduleID := range scheduleIDs {
+		rp.unschedule(scheduleID)
+	}
+	return nil
+}
+
+func (rp *reportService) GetSavedSearchList(ctx context.Context, userID uuid.UUID) ([]models.SavedSearch, error) {
+	return rp.reportRepo.GetSavedSearchList(ctx, userID)
+}
+
+func (rp *reportService) CreateSchedule(ctx context.Context, userID uuid.UUID, req models.ReportScheduleCreateReq) (*models.ReportSchedule, error) {
+	if err := rp.checkSavedSearch(ctx, userID, req.SavedSearchUUID); err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	schedule := models.ReportSchedule{
+		ScheduleID:    uuid.New(),
+		UserID:        userID,
+		SavedSearchID: req.SavedSearchUUID,
+		Name:          req.Name,
+		Cron:          req.Cron,
+		Format:        req.Format,
+		Delivery:      req.Delivery,
+		Recipients:    req.Recipients,
+		Enabled:       req.Enabled,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	if err := rp.reportRepo.CreateSchedule(ctx, schedule); err != nil {
+		return nil, err
+	}
+	rp.schedule(schedule)
+	return &schedule, nil
+}
+
+func (rp *reportService) UpdateSchedule(ctx context.Context, userID uuid.UUID, req models.ReportScheduleUpdateReq) (*models.ReportSchedule, error) {
+	if err := rp.checkSavedSearch(ctx, userID, req.SavedSearchUUID); err != nil {
+		return nil, err
+	}
+
+	updated, err := rp.reportRepo.UpdateSchedule(ctx, models.ReportSchedule{
+		ScheduleID:    req.ScheduleUUID,
+		UserID:        userID,
+		SavedSearchID: req.SavedSearchUUID,
+		Name:          req.Name,
+		Cron:          req.Cron,
+		Format:        req.Format,
+		Delivery:      req.Delivery,
+		Recipients:    req.Recipients,
+		Enabled:       req.Enabled,
+		UpdatedAt:     time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, models.ErrScheduleNotFound
+	}
+
+	schedule, err := rp.reportRepo.GetSchedule(ctx, req.ScheduleUUID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return nil, models.ErrScheduleNotFound
+	}
+	rp.schedule(*schedule)
+	return schedule, nil
+}
+
+func (rp *reportService) DeleteSchedule(ctx context.Context, userID, id uuid.UUID) error {
+	deleted, err := rp.reportRepo.DeleteSchedule(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return models.ErrScheduleNotFound
+	}
+	rp.unschedule(id)
+	return nil
+}
+
+func (rp *reportService) GetScheduleList(ctx context.Context, userID uuid.UUID) ([]models.ReportSchedule, error) {
+	return rp.reportRepo.GetScheduleList(ctx, userID)
+}
+
+// GetRunList lists the run history of a schedule of the user, newest first.
+func (rp *reportService) GetRunList(ctx context.Context, userID uuid.UUID, req models.ReportRunSearchReq) (*models.SearchRp, error) {
+	if _, err := rp.getSchedule(ctx, userID, req.ScheduleUUID); err != nil {
+		return nil, err
+	}
+	runs, nextOffset, err := rp.reportRepo.GetRunList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SearchRp{
+		Data: runs,
+		Pagination: models.Pagination{
+			Limit:  req.Limit,
+			Offset: nextOffset,
+		},
+	}, nil
+}
+
+// RunSchedule runs a schedule of the user now, even when it is disabled, and returns the run.
+func (rp *reportService) RunSchedule(ctx context.Context, userID, id uuid.UUID) (*models.ReportRun, error) {
+	schedule, err := rp.getSchedule(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	return rp.run(ctx, *schedule)
+}
+
+// StartSchedules adds the enabled schedules to c. Schedules created or changed afterwards are
+// added, moved or removed as they are saved on this instance, and by SyncSchedules when saved
+// on another.
+func (rp *reportService) StartSchedules(ctx context.Context, c *cron.Cron) error {
+	rp.mu.Lock()
+	rp.cron = c
+	rp.mu.Unlock()
+
+	return rp.SyncSchedules(ctx)
+}
+
+// SyncSchedules makes the cron entries match the enabled schedules: new schedules are added,
+// schedules whose expression changed are moved, and deleted or disabled ones are removed.
+func (rp *reportService) SyncSchedules(ctx context.Context) error {
+	// Only entries added before the read may be removed: one added meanwhile by a request to
+	// this instance is missing from the read.
+	rp.mu.Lock()
+	known := make([]uuid.UUID, 0, len(rp.entries))
+	for id := range rp.entries {
+		known = append(known, id)
+	}
+	rp.mu.Unlock()
+
+	schedules, err := rp.reportRepo.GetEnabledSchedules(ctx)
+	if err != nil {
+		return err
+	}
+	enabled := make(map[uuid.UUID]bool, len(schedules))
+	for _, schedule := range schedules {
+		enabled[schedule.ScheduleID] = true
+		rp.schedule(schedule)
+	}
+	for _, id := range known {
+		if !enabled[id] {
+			rp.unschedule(id)
+		}
+	}
+	return nil
+}
+
+func (rp *reportService) checkSavedSearch(ctx context.Context, userID, id uuid.UUID) error {
+	search, err := rp.reportRepo.GetSavedSearch(ctx, id)
+	if err != nil {
+		return err
+	}
+	if search == nil || search.UserID != userID {
+		return models.ErrSavedSearchNotFound
+	}
+	return nil
+}
+
+func (rp *reportService) getSchedule(ctx context.Context, userID, id uuid.UUID) (*models.ReportSchedule, error) {
+	schedule, err := rp.reportRepo.GetSchedule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil || schedule.UserID != userID {
+		return nil, models.ErrScheduleNotFound
+	}
+	return schedule, nil
+}
+
+// schedule replaces the cron entry of the schedule, or removes it when the schedule is disabled.
+// An entry already added with the same expression is kept.
+func (rp *reportService) schedule(schedule models.ReportSchedule) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if rp.cron == nil {
+		return
+	}
+	if entry, ok := rp.entries[schedule.ScheduleID]; ok {
+		if schedule.Enabled && entry.cron == schedule.Cron {
+			return
+		}
+		rp.cron.Remove(entry.id)
+		delete(rp.entries, schedule.ScheduleID)
+	}
+	if !schedule.Enabled {
+		return
+	}
+
+	spec, err := models.ReportCronParser.Parse(schedule.Cron)
+	if err != nil {
+		global.Logger.Error("Schedule report error", zap.String("schedule_id", schedule.ScheduleID.String()), zap.Error(err))
+		return
+	}
+	scheduleID, expr := schedule.ScheduleID, schedule.Cron
+	job := cron.NewChain(cron.SkipIfStillRunning(cron.DiscardLogger)).Then(cron.FuncJob(func() {
+		rp.runScheduled(scheduleID, expr)
+	}))
+	rp.entries[scheduleID] = scheduleEntry{id: rp.cron.Schedule(spec, job), cron: expr}
+}
+
+func (rp *reportService) unschedule(id uuid.UUID) {
+	rp.mu.Lock()
+	defer rp.mu.Unlock()
+
+	if entry, ok := rp.entries[id]; ok && rp.cron != nil {
+		rp.cron.Remove(entry.id)
+	}
+	delete(rp.entries, id)
+}
+
+// runScheduled runs a schedule from its cron entry added with expr. The schedule is read again
+// in case another instance changed it: when its expression is no longer expr, the entry is
+// moved and this run is skipped. A Redis lock on the minute makes only one instance run it.
+func (rp *reportService) runScheduled(id uuid.UUID, expr string) {
+	ctx := context.Background()
+	schedule, err := rp.reportRepo.GetSchedule(ctx, id)
+	if err != nil {
+		global.Logger.Error("Get report schedule error", zap.String("schedule_id", id.String()), zap.Error(err))
+		return
+	}
+	if schedule == nil || !schedule.Enabled {
+		rp.unschedule(id)
+		return
+	}
+	if schedule.Cron != expr {
+		rp.schedule(*schedule)
+		return
+	}
+
+	lockKey := fmt.Sprintf("report:lock:%s:%d", id, time.Now().Truncate(time.Minute).Unix())
+	locked, err := rp.rdb.SetNX(ctx, lockKey, 1, reportLockTTL).Result()
+	if err != nil {
+		// Better twice than never.
+		global.Logger.Warn("Lock report schedule error", zap.String("schedule_id", id.String()), zap.Error(err))
+	} else if !locked {
+		return
+	}
+
+	run, err := rp.run(ctx, *schedule)
+	if err != nil {
+		global.Logger.Error("Run report error", zap.String("schedule_id", id.String()), zap.Error(err))
+		return
+	}
+	global.Logger.Info("Run report finished", zap.String("schedule_id", id.String()),
+		zap.String("status", string(run.Status)), zap.Int("products", run.Products))
+}
+
+// run exports the saved search of the schedule, delivers the file and records the run. A
+// failed export or delivery is recorded in the run, not returned.
+func (rp *reportService) run(ctx context.Context, schedule models.ReportSchedule) (*models.ReportRun, error) {
+	run := models.ReportRun{
+		RunID:      uuid.New(),
+		ScheduleID: schedule.ScheduleID,
+		Status:     models.ReportRunRunning,
+		StartedAt:  time.Now().UTC(),
+	}
+	if err := rp.reportRepo.CreateRun(ctx, run); err != nil {
+		return nil, err
+	}
+
+	products, data, err := rp.export(ctx, schedule)
+	if err == nil {
+		run.Products = products
+		run.Output, err = rp.deliver(schedule, data, products, run.StartedAt)
+	}
+	run.Status = models.ReportRunSuccess
+	if err != nil {
+		run.Status = models.ReportRunFailed
+		run.Error = err.Error()
+	}
+	finishedAt := time.Now().UTC()
+	run.FinishedAt = &finishedAt
+
+	// Recorded even when the request that started the run was canceled.
+	if err := rp.reportRepo.FinishRun(context.Background(), run); err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (rp *reportService) export(ctx context.Context, schedule models.ReportSchedule) (int, []byte, error) {
+	saved, err := rp.reportRepo.GetSavedSearch(ctx, schedule.SavedSearchID)
+	if err != nil {
+		return 0, nil, err
+	}
+	if saved == nil {
+		return 0, nil, models.ErrSavedSearchNotFound
+	}
+
+	search := saved.Search
+	search.Pagination = models.Pagination{}
+	search.Cursor = ""
+	if code := search.Validate(); code != response.OkCode {
+		return 0, nil, fmt.Errorf("invalid saved search: code %d", code)
+	}
+	products, _, _, err := rp.productRepo.GetProductList(ctx, search)
+	if err != nil {
+		return 0, nil, err
+	}
+	data, err := renderProducts(products, schedule.Format)
+	if err != nil {
+		return 0, nil, err
+	}
+	return len(products), data, nil
+}
+
+// deliver writes the export to the report directory or emails it, and returns the written
+// path or the recipients.
+func (rp *reportService) deliver(schedule models.ReportSchedule, data []byte, products int, startedAt time.Time) (string, error) {
+	cfg := global.Config.Report
+	fileName := fmt.Sprintf("%s_%s.%s", reportFileName(schedule.Name), startedAt.Format("20060102_150405"), schedule.Format)
+
+	switch schedule.Delivery {
+	case models.ReportDeliveryDirectory:
+		if err := os.MkdirAll(cfg.Directory, 0o755); err != nil {
+			return "", err
+		}
+		path := filepath.Join(cfg.Directory, fileName)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			return "", err
+		}
+		return path, nil
+	case models.ReportDeliveryEmail:
+		body := fmt.Sprintf("%s: %d products, generated at %s.\n", schedule.Name, products, startedAt.Format(time.RFC1123))
+		attachment := utils.MailAttachment{
+			FileName:    fileName,
+			ContentType: schedule.Format.ContentType(),
+			Data:        data,
+		}
+		if err := utils.SendMail(cfg.Smtp, schedule.Recipients, schedule.Name, body, attachment); err != nil {
+			return "", err
+		}
+		return strings.Join(schedule.Recipients, ", "), nil
+	}
+	return "", fmt.Errorf("invalid delivery %s", schedule.Delivery)
+}
+
+// reportFileName keeps the letters and digits of a schedule name, other characters become "_".
+func reportFileName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			return r
+		}
+		return '_'
+	}, name)
+}
@@ -3,27 +3,78 @@ package services
 import (
 	"stock-management/global"
 	"stock-management/internal/repo"
+	"time"
 )
 
 var Service *service
 
 type service struct {
-	CategoryService ProductCategoryService
-	ProductService  ProductService
-	SupplierService SupplierService
+	CategoryService          ProductCategoryService
+	ProductService           ProductService
+	SupplierService          SupplierService
+	InventorySnapshotService InventorySnapshotService
+	StockMovementService     StockMovementService
+	StockAnalyticsService    StockAnalyticsService
+	PlanningService          PlanningService
+	AuthService              AuthService
+	RbacService              RbacService
+	AuditService             AuditService
+	LabelService             LabelService
+	ReportService            ReportService
+	OutboxService            OutboxService
+	EventService             EventService
+	WebhookService           WebhookService
 }
 
 func InitService() {
 	productCategoryRepo := repo.NewCategoryRepo(global.Pdb)
 	supplierRepo := repo.NewSupplierRepo(global.Pdb)
-	productRepo := repo.NewProductRepo(global.Pdb, global.Rdb, productCategoryRepo, supplierRepo)
-	productServices := newProductService(productRepo)
+	productCache := repo.NewProductCache(global.Rdb,
+		time.Duration(global.Config.Cache.ProductTTL)*time.Second,
+		time.Duration(global.Config.Cache.ProductListTTL)*time.Second)
+	productRepo := repo.NewProductRepo(global.Pdb, global.Rdb, productCache, productCategoryRepo, supplierRepo)
+	inventorySnapshotRepo := repo.NewInventorySnapshotRepo(global.Pdb)
+	stockMovementRepo := repo.NewStockMovementRepo(global.Pdb, productCache)
+	purchaseOrderRepo := repo.NewPurchaseOrderRepo(global.Pdb)
+	userRepo := repo.NewUserRepo(global.Pdb)
+	authRepo := repo.NewAuthRepo(global.Pdb)
+	roleRepo := repo.NewRoleRepo(global.Pdb)
+	auditRepo := repo.NewAuditRepo(global.Pdb)
+	reportRepo := repo.NewReportRepo(global.Pdb)
+	outboxRepo := repo.NewOutboxRepo(global.Pdb, global.Rdb)
+	eventRepo := repo.NewEventRepo(global.Rdb, global.Config.Event.Stream, global.Config.Event.MaxLen)
+	webhookRepo := repo.NewWebhookRepo(global.Pdb)
+	productServices := newProductService(productRepo, productCategoryRepo, supplierRepo)
 	categoryServices := newProductCategoryService(productCategoryRepo)
 	supplierService := newSupplierService(supplierRepo)
+	inventorySnapshotService := newInventorySnapshotService(inventorySnapshotRepo, productRepo, productCategoryRepo, supplierRepo)
+	stockMovementService := newStockMovementService(stockMovementRepo)
+	stockAnalyticsService := newStockAnalyticsService(stockMovementRepo, inventorySnapshotRepo, productRepo, productCategoryRepo, supplierRepo)
+	planningService := newPlanningService(productRepo, stockMovementRepo, purchaseOrderRepo)
+	authService := newAuthService(userRepo, authRepo)
+	rbacService := newRbacService(roleRepo, userRepo)
+	auditService := newAuditService(auditRepo)
+	labelService := newLabelService(productRepo)
+	reportService := newReportService(reportRepo, productRepo, global.Rdb)
+	outboxService := newOutboxService(outboxRepo, eventRepo, webhookRepo, global.Config.Job.OutboxMaxAttempts)
+	eventService := newEventService(eventRepo)
+	webhookService := newWebhookService(webhookRepo, global.Config.Webhook)
 
 	Service = &service{
-		CategoryService: categoryServices,
-		ProductService:  productServices,
-		SupplierService: supplierService,
+		CategoryService:          categoryServices,
+		ProductService:           productServices,
+		SupplierService:          supplierService,
+		InventorySnapshotService: inventorySnapshotService,
+		StockMovementService:     stockMovementService,
+		StockAnalyticsService:    stockAnalyticsService,
+		PlanningService:          planningService,
+		AuthService:              authService,
+		RbacService:              rbacService,
+		AuditService:             auditService,
+		LabelService:             labelService,
+		ReportService:            reportService,
+		OutboxService:            outboxService,
+		EventService:             eventService,
+		WebhookService:           webhookService,
 	}
 }
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type StockAnalyticsService interface {
+	GetTurnover(ctx context.Context, req models.StockAnalyticsReq) (*models.TurnoverRp, error)
+	GetDaysOfCover(ctx context.Context, req models.StockAnalyticsReq) (*models.DaysOfCoverRp, error)
+	GetDeadStock(ctx context.Context, req models.StockAnalyticsReq) (*models.DeadStockRp, error)
+	RunAbcAnalysis(ctx context.Context, req models.AbcAnalysisReq) (*models.AbcAnalysisRp, error)
+}
+
+type stockAnalyticsService struct {
+	stockMovementRepo     repo.StockMovementRepo
+	inventorySnapshotRepo repo.InventorySnapshotRepo
+	productRepo           repo.ProductRepo
+	productCategoryRepo   repo.ProductCategoryRepo
+	supplierRepo          repo.SupplierRepo
+}
+
+func newStockAnalyticsService(stockMovementRepo repo.StockMovementRepo, inventorySnapshotRepo repo.InventorySnapshotRepo, productRepo repo.ProductRepo, productCategoryRepo repo.ProductCategoryRepo, supplierRepo repo.SupplierRepo) StockAnalyticsService {
+	return &stockAnalyticsService{
+		stockMovementRepo:     stockMovementRepo,
+		inventorySnapshotRepo: inventorySnapshotRepo,
+		productRepo:           productRepo,
+		productCategoryRepo:   productCategoryRepo,
+		supplierRepo:          supplierRepo,
+	}
+}
+
+// GetTurnover divides the value issued over the period by the average inventory value of the
+// daily snapshots. Groups without snapshots in the period fall back to their current stock value.
+func (as *stockAnalyticsService) GetTurnover(ctx context.Context, req models.StockAnalyticsReq) (*models.TurnoverRp, error) {
+	issued, err := as.stockMovementRepo.GetIssuedPerGroup(ctx, req.GroupBy, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	averages, err := as.inventorySnapshotRepo.GetAverageInventoryPerGroup(ctx, req.GroupBy, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+	current, err := as.productRepo.GetProductSumPerGroup(ctx, req.GroupBy, models.StatisticsMetricValue)
+	if err != nil {
+		return nil, err
+	}
+
+	items := make(map[uuid.UUID]*models.TurnoverItem)
+	getItem := func(id uuid.UUID) *models.TurnoverItem {
+		item, ok := items[id]
+		if !ok {
+			item = &models.TurnoverItem{ID: id.String()}
+			items[id] = item
+		}
+		return item
+	}
+	for _, row := range issued {
+		item := getItem(row.GroupID)
+		item.IssuedQuantity = row.Quantity
+		item.IssuedValue = row.Value.IntPart()
+	}
+	for _, row := range averages {
+		getItem(row.GroupID).AverageInventoryValue = row.Value.Round(2)
+	}
+	for key, value := range current {
+		id, err := uuid.Parse(key)
+		if err != nil {
+			continue
+		}
+		item := getItem(id)
+		if item.AverageInventoryValue.IsZero() {
+			item.AverageInventoryValue = decimal.NewFromInt(value)
+		}
+	}
+
+	periodDays := decimal.NewFromInt(int64(req.To.Sub(req.From).Hours()/24) + 1)
+	ids := make([]uuid.UUID, 0, len(items))
+	for id, item := range items {
+		ids = append(ids, id)
+		if !item.AverageInventoryValue.IsZero() {
+			item.TurnoverRatio = decimal.NewFromInt(item.IssuedValue).Div(item.AverageInventoryValue).Round(2)
+		}
+		if !item.TurnoverRatio.IsZero() {
+			item.AverageDaysToSellStock = periodDays.Div(item.TurnoverRatio).Round(2)
+		}
+	}
+
+	names, err := getGroupNames(ctx, as.productRepo, as.productCategoryRepo, as.supplierRepo, req.GroupBy, ids)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.TurnoverRp{
+		DateFrom: req.DateFrom,
+		DateTo:   req.DateTo,
+		GroupBy:  req.GroupBy,
+		Data:     make([]models.TurnoverItem, 0, len(items)),
+	}
+	for _, item := range items {
+		item.Name = names[item.ID]
+		result.Data = append(result.Data, *item)
+	}
+	sort.Slice(result.Data, func(i, j int) bool {
+		return result.Data[i].TurnoverRatio.GreaterThan(result.Data[j].TurnoverRatio)
+	})
+	return result, nil
+}
+
+// GetDaysOfCover estimates how long the stock on hand lasts at the average daily issue rate
+// of the last req.Days days. Products without issues have no days of cover.
+func (as *stockAnalyticsService) GetDaysOfCover(ctx context.Context, req models.StockAnalyticsReq) (*models.DaysOfCoverRp, error) {
+	rows, err := as.stockMovementRepo.GetProductIssues(ctx, time.Now().AddDate(0, 0, -req.Days))
+	if err != nil {
+		return nil, err
+	}
+
+	days := decimal.NewFromInt(int64(req.Days))
+	result := &models.DaysOfCoverRp{
+		Days: req.Days,
+		Data: make([]models.DaysOfCoverItem, 0, len(rows)),
+	}
+	for _, row := range rows {
+		item := models.DaysOfCoverItem{
+			ProductID:         row.ProductID.String(),
+			ProductName:       row.ProductName,
+			ProductReference:  row.ProductReference,
+			Quantity:          row.Quantity,
+			IssuedQuantity:    row.IssuedQuantity,
+			AverageDailyIssue: decimal.Zero,
+		}
+		if row.IssuedQuantity > 0 && req.Days > 0 {
+			avg := decimal.NewFromInt(row.IssuedQuantity).Div(days)
+			cover := decimal.NewFromInt(int64(row.Quantity)).Div(avg).Round(1)
+			item.AverageDailyIssue = avg.Round(2)
+			item.DaysOfCover = &cover
+		}
+		result.Data = append(result.Data, item)
+	}
+	sort.SliceStable(result.Data, func(i, j int) bool {
+		a, b := result.Data[i].DaysOfCover, result.Data[j].DaysOfCover
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.LessThan(*b)
+	})
+	return result, nil
+}
+
+func (as *stockAnalyticsService) GetDeadStock(ctx context.Context, req models.StockAnalyticsReq) (*models.DeadStockRp, error) {
+	items, err := as.stockMovementRepo.GetDeadStock(ctx, time.Now().AddDate(0, 0, -req.Days))
+	if err != nil {
+		return nil, err
+	}
+
+	perCategory := make(map[uuid.UUID]*models.DeadStockGroup)
+	perSupplier := make(map[uuid.UUID]*models.DeadStockGroup)
+	addTo := func(groups map[uuid.UUID]*models.DeadStockGroup, id uuid.UUID, item models.DeadStockItem) {
+		group, ok := groups[id]
+		if !ok {
+			group = &models.DeadStockGroup{ID: id.String()}
+			groups[id] = group
+		}
+		group.Products++
+		group.Quantity += int64(item.Quantity)
+		group.Value += item.Value
+	}
+	for _, item := range items {
+		addTo(perCategory, item.ProductCategoryID, item)
+		addTo(perSupplier, item.SupplierID, item)
+	}
+
+	categories, err := as.buildDeadStockGroups(ctx, models.StatisticsGroupByCategory, perCategory)
+	if err != nil {
+		return nil, err
+	}
+	suppliers, err := as.buildDeadStockGroups(ctx, models.StatisticsGroupBySupplier, perSupplier)
+	if err != nil {
+		return nil, err
+	}
+	if items == nil {
+		items = []models.DeadStockItem{}
+	}
+	return &models.DeadStockRp{
+		Days:        req.Days,
+		Data:        items,
+		PerCategory: categories,
+		PerSupplier: suppliers,
+	}, nil
+}
+
+func (as *stockAnalyticsService) buildDeadStockGroups(ctx context.Context, groupBy models.StatisticsGroupBy, groups map[uuid.UUID]*models.DeadStockGroup) ([]models.DeadStockGroup, error) {
+	ids := make([]uuid.UUID, 0, len(groups))
+	for id := range groups {
+		ids = append(ids, id)
+	}
+	names, err := getGroupNames(ctx, as.productRepo, as.productCategoryRepo, as.supplierRepo, groupBy, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.DeadStockGroup, 0, len(groups))
+	for _, group := range groups {
+		group.Name = names[group.ID]
+		result = append(result, *group)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Value > result[j].Value
+	})
+	return result, nil
+}
+
+// RunAbcAnalysis ranks products by consumption value and stores their class. A product is in
+// class A while the cumulative share before it is below ThresholdA, in B below ThresholdB, else C,
+// so the top product is always A. Without any consumption every product is C.
+func (as *stockAnalyticsService) RunAbcAnalysis(ctx context.Context, req models.AbcAnalysisReq) (*models.AbcAnalysisRp, error) {
+	rows, err := as.stockMovementRepo.GetConsumptionPerProduct(ctx, req.From, req.To)
+	if err != nil {
+		return nil, err
+	}
+
+	var total int64
+	for _, row := range rows {
+		total += row.ConsumptionValue
+	}
+
+	hundred := decimal.NewFromInt(100)
+	thresholdA := decimal.NewFromFloat(req.ThresholdA)
+	thresholdB := decimal.NewFromFloat(req.ThresholdB)
+	classes := map[models.AbcClass][]uuid.UUID{}
+	summaries := map[models.AbcClass]*models.AbcClassSummary{
+		models.AbcClassA: {Class: models.AbcClassA},
+		models.AbcClassB: {Class: models.AbcClassB},
+		models.AbcClassC: {Class: models.AbcClassC},
+	}
+
+	result := &models.AbcAnalysisRp{
+		DateFrom:   req.DateFrom,
+		DateTo:     req.DateTo,
+		ThresholdA: req.ThresholdA,
+		ThresholdB: req.ThresholdB,
+		TotalValue: total,
+		Data:       make([]models.AbcItem, 0, len(rows)),
+	}
+	cumulative := decimal.Zero
+	for _, row := range rows {
+		percentage := decimal.Zero
+		if total > 0 {
+			percentage = decimal.NewFromInt(row.ConsumptionValue).Mul(hundred).Div(decimal.NewFromInt(total))
+		}
+
+		class := models.AbcClassC
+		if row.ConsumptionValue > 0 {
+			if cumulative.LessThan(thresholdA) {
+				class = models.AbcClassA
+			} else if cumulative.LessThan(thresholdB) {
+				class = models.AbcClassB
+			}
+		}
+		cumulative = cumulative.Add(percentage)
+
+		classes[class] = append(classes[class], row.ProductID)
+		summary := summaries[class]
+		summary.Products++
+		summary.ConsumptionValue += row.ConsumptionValue
+		summary.Percentage = summary.Percentage.Add(percentage)
+
+		result.Data = append(result.Data, models.AbcItem{
+			ProductID:            row.ProductID.String(),
+			ProductName:          row.ProductName,
+			ProductReference:     row.ProductReference,
+			ConsumptionValue:     row.ConsumptionValue,
+			Percentage:           percentage.Round(2),
+			CumulativePercentage: cumulative.Round(2),
+			Class:                class,
+		})
+	}
+
+	if err := as.productRepo.UpdateAbcClasses(ctx, classes); err != nil {
+		return nil, err
+	}
+
+	for _, class := range []models.AbcClass{models.AbcClassA, models.AbcClassB, models.AbcClassC} {
+		summary := summaries[class]
+		summary.Percentage = summary.Percentage.Round(2)
+		result.Summary = append(result.Summary, *summary)
+	}
+	return result, nil
+}
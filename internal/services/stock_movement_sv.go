@@ -0,0 +1,41 @@
+package services
+
+import (
+	"context"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+)
+
+type StockMovementService interface {
+	CreateMovement(ctx context.Context, req models.StockMovementCreateReq) (*models.StockMovement, error)
+	GetMovementList(ctx context.Context, req models.StockMovementSearchReq) (*models.SearchRp, error)
+}
+
+type stockMovementService struct {
+	stockMovementRepo repo.StockMovementRepo
+}
+
+func newStockMovementService(stockMovementRepo repo.StockMovementRepo) StockMovementService {
+	return &stockMovementService{
+		stockMovementRepo: stockMovementRepo,
+	}
+}
+
+func (ms *stockMovementService) CreateMovement(ctx context.Context, req models.StockMovementCreateReq) (*models.StockMovement, error) {
+	return ms.stockMovementRepo.CreateMovement(ctx, req)
+}
+
+func (ms *stockMovementService) GetMovementList(ctx context.Context, req models.StockMovementSearchReq) (*models.SearchRp, error) {
+	rs, offset, err := ms.stockMovementRepo.GetMovementList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	result := &models.SearchRp{
+		Data: rs,
+		Pagination: models.Pagination{
+			Offset: offset,
+			Limit:  req.Limit,
+		},
+	}
+	return result, nil
+}
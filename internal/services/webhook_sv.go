@@ -0,0 +1,356 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"stock-management/global"
+	"stock-management/internal/models"
+	"stock-management/internal/repo"
+	"stock-management/pkgs/setting"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+const (
+	// webhookBatchSize is the number of deliveries claimed at once, sent by webhookWorkers
+	// requests in parallel.
+	webhookBatchSize = 32
+	webhookWorkers   = 8
+	// webhookErrorLength bounds the response body kept as the error of a failed attempt.
+	webhookErrorLength = 512
+)
+
+// Headers of a webhook request. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the webhook secret, prefixed with "sha256=".
+const (
+	webhookEventHeader     = "X-Webhook-Event"
+	webhookDeliveryHeader  = "X-Webhook-Delivery"
+	webhookTimestampHeader = "X-Webhook-Timestamp"
+	webhookSignatureHeader = "X-Webhook-Signature"
+)
+
+type WebhookService interface {
+	GetWebhookList(ctx context.Context) ([]models.Webhook, error)
+	CreateWebhook(ctx context.Context, req models.WebhookCreateReq) (*models.Webhook, error)
+	UpdateWebhook(ctx context.Context, req models.WebhookUpdateReq) (*models.Webhook, error)
+	DeleteWebhook(ctx context.Context, id uuid.UUID) error
+	GetDeliveryList(ctx context.Context, req models.WebhookDeliverySearchReq) (*models.SearchRp, error)
+	Redeliver(ctx context.Context, req models.WebhookRedeliverReq) (*models.WebhookRedeliverRp, error)
+	Dispatch(ctx context.Context) (int, error)
+	DeleteDelivered(ctx context.Context, retention time.Duration) (int64, error)
+}
+
+type webhookService struct {
+	webhookRepo repo.WebhookRepo
+	cfg         setting.WebhookSetting
+	client      *http.Client
+}
+
+func newWebhookService(webhookRepo repo.WebhookRepo, cfg setting.WebhookSetting) WebhookService {
+	return &webhookService{
+		webhookRepo: webhookRepo,
+		cfg:         cfg,
+		client: &http.Client{
+			Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second,
+			// A redirect is a failed attempt: the signed request is not sent anywhere else.
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (ws *webhookService) GetWebhookList(ctx context.Context) ([]models.Webhook, error) {
+	return ws.webhookRepo.GetWebhookList(ctx)
+}
+
+func (ws *webhookService) CreateWebhook(ctx context.Context, req models.WebhookCreateReq) (*models.Webhook, error) {
+	now := time.Now().UTC()
+	webhook := models.Webhook{
+		WebhookID:  uuid.New(),
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Enabled:    req.Enabled,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if err := ws.webhookRepo.CreateWebhook(ctx, webhook); err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// UpdateWebhook replaces a webhook. Deliveries already queued are sent to the new URL, even for
+// event types it no longer subscribes to.
+func (ws *webhookService) UpdateWebhook(ctx context.Context, req models.WebhookUpdateReq) (*models.Webhook, error) {
+	updated, err := ws.webhookRepo.UpdateWebhook(ctx, models.Webhook{
+		WebhookID:  req.WebhookUUID,
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+		Enabled:    req.Enabled,
+		UpdatedAt:  time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return nil, models.ErrWebhookNotFound
+	}
+
+	webhook, err := ws.webhookRepo.GetWebhook(ctx, req.WebhookUUID)
+	if err != nil {
+		return nil, err
+	}
+	if webhook == nil {
+		return nil, models.ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+func (ws *webhookService) DeleteWebhook(ctx context.Context, id uuid.UUID) error {
+	deleted, err := ws.webhookRepo.DeleteWebhook(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return models.ErrWebhookNotFound
+	}
+	return nil
+}
+
+// GetDeliveryList lists deliveries, newest first.
+func (ws *webhookService) GetDeliveryList(ctx context.Context, req models.WebhookDeliverySearchReq) (*models.SearchRp, error) {
+	deliveries, nextOffset, err := ws.webhookRepo.GetDeliveryList(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return &models.SearchRp{
+		Data: deliveries,
+		Pagination: models.Pagination{
+			Limit:  req.Limit,
+			Offset: nextOffset,
+		},
+	}, nil
+}
+
+// Redeliver queues dead deliveries again. Deliveries that are not dead are left as they are.
+func (ws *webhookService) Redeliver(ctx context.Context, req models.WebhookRedeliverReq) (*models.WebhookRedeliverRp, error) {
+	count, err := ws.webhookRepo.RedeliverDeliveries(ctx, req.DeliveryUUIDs)
+	if err != nil {
+		return nil, err
+	}
+	return &models.WebhookRedeliverRp{Redelivered: count}, nil
+}
+
+// Dispatch sends the due deliveries in batches until none is left, and returns the number
+// delivered. A failed attempt is retried with exponential backoff until the delivery is dead.
+func (ws *webhookService) Dispatch(ctx context.Context) (int, error) {
+	// Claimed deliveries are not sent by other instances for as long as a batch can take.
+	timeout := time.Duration(ws.cfg.TimeoutSeconds) * time.Second
+	lease := timeout*(webhookBatchSize/webhookWorkers) + time.Minute
+
+	total := 0
+	for {
+		deliveries, err := ws.webhookRepo.ClaimDeliveries(ctx, webhookBatchSize, lease)
+		if err != nil {
+			return total, err
+		}
+		delivered, err := ws.send(ctx, deliveries)
+		total += delivered
+		if err != nil || len(deliveries) < webhookBatchSize {
+			return total, err
+		}
+	}
+}
+
+func (ws *webhookService) DeleteDelivered(ctx context.Context, retention time.Duration) (int64, error) {
+	return ws.webhookRepo.DeleteDelivered(ctx, time.Now().UTC().Add(-retention))
+}
+
+// send attempts claimed deliveries in parallel and records their outcome.
+func (ws *webhookService) send(ctx context.Context, deliveries []models.WebhookDelivery) (int, error) {
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+
+	webhookIDs := make([]uuid.UUID, 0, len(deliveries))
+	seen := make(map[uuid.UUID]bool)
+	for _, delivery := range deliveries {
+		if !seen[delivery.WebhookID] {
+			seen[delivery.WebhookID] = true
+			webhookIDs = append(webhookIDs, delivery.WebhookID)
+		}
+	}
+	webhooks, err := ws.webhookRepo.GetWebhooksByIds(ctx, webhookIDs)
+	if err != nil {
+		return 0, err
+	}
+	webhookMap := make(map[uuid.UUID]models.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		webhookMap[webhook.WebhookID] = webhook
+	}
+
+	var (
+		mu        sync.Mutex
+		wg        sync.WaitGroup
+		delivered int
+		errs      []error
+	)
+	workers := make(chan struct{}, webhookWorkers)
+	for _, delivery := range deliveries {
+		// Deleted along with its deliveries meanwhile.
+		webhook, ok := webhookMap[delivery.WebhookID]
+		if !ok {
+			continue
+		}
+
+		wg.Add(1)
+		workers <- struct{}{}
+		go func(delivery models.WebhookDelivery) {
+			defer func() {
+				<-workers
+				wg.Done()
+			}()
+
+			delivery = ws.attempt(ctx, webhook, delivery)
+			// Recorded even when the dispatch is canceled, or the delivery is sent again.
+			err := ws.webhookRepo.FinishDelivery(context.WithoutCancel(ctx), delivery)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("finish webhook delivery %s: %w", delivery.DeliveryID, err))
+				return
+			}
+			if delivery.Status == models.DeliveryDelivered {
+				delivered++
+			} else if delivery.Status == models.DeliveryDead {
+				global.Logger.Warn("Webhook delivery dead", zap.String("delivery_id", delivery.DeliveryID.String()),
+					zap.String("webhook_id", webhook.WebhookID.String()), zap.String("error", delivery.LastError))
+			}
+		}(delivery)
+	}
+	wg.Wait()
+	return delivered, errors.Join(errs...)
+}
+
+// attempt posts a delivery to its webhook and returns the delivery updated with the outcome.
+func (ws *webhookService) attempt(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) models.WebhookDelivery {
+	now := time.Now().UTC()
+	statusCode, err := ws.post(ctx, webhook, delivery)
+
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+	delivery.LastStatusCode = statusCode
+	if err == nil {
+		delivery.Status = models.DeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+		return delivery
+	}
+
+	delivery.LastError = err.Error()
+	if len(delivery.LastError) > webhookErrorLength {
+		delivery.LastError = delivery.LastError[:webhookErrorLength]
+	}
+	// Postgres text takes neither invalid UTF-8 nor NUL.
+	delivery.LastError = strings.ReplaceAll(strings.ToValidUTF8(delivery.LastError, ""), "\x00", "")
+	if delivery.Attempts >= ws.cfg.MaxAttempts {
+		delivery.Status = models.DeliveryDead
+		return delivery
+	}
+	delivery.NextAttemptAt = now.Add(ws.retryDelay(delivery.Attempts))
+	return delivery
+}
+
+// post sends the signed event of a delivery. Only a 2xx response is a success; the status code
+// is 0 when no response was received.
+func (ws *webhookService) post(ctx context.Context, webhook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		return 0, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhookEventHeader, string(delivery.Event.Type))
+	req.Header.Set(webhookDeliveryHeader, delivery.DeliveryID.String())
+	req.Header.Set(webhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(webhookSignatureHeader, signWebhook(webhook.Secret, timestamp, body))
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, webhookErrorLength))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(data)))
+	}
+	return resp.StatusCode, nil
+}
+
+// retryDelay is the wait after the attempts-th failed attempt: RetryBaseSeconds doubled per
+// earlier failure, capped at RetryMaxSeconds, plus up to 10% jitter so that deliveries failing
+// together are not all retried together.
+func (ws *webhookService) retryDelay(attempts int) time.Duration {
+	delay := time.Duration(ws.cfg.RetryMaxSeconds) * time.Second
+	base := time.Duration(ws.cfg.RetryBaseSeconds) * time.Second
+	// Compared before shifting, so that a late retry doesn't overflow past the cap.
+	if n := max(attempts-1, 0); n < 63 && base <= delay>>n {
+		delay = base << n
+	}
+	return delay + time.Duration(rand.Int63n(int64(delay)/10+1))
+}
+
+// signWebhook returns the signature header of a request body sent at timestamp.
+func signWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookDeliveries returns the deliveries of events to the webhooks subscribing to them.
+func webhookDeliveries(webhooks []models.Webhook, events []models.DomainEvent) []models.WebhookDelivery {
+	now := time.Now().UTC()
+	var deliveries []models.WebhookDelivery
+	for _, event := range events {
+		for _, webhook := range webhooks {
+			if !webhook.Subscribes(event.Type) {
+				continue
+			}
+			deliveries = append(deliveries, models.WebhookDelivery{
+				DeliveryID:    uuid.New(),
+				WebhookID:     webhook.WebhookID,
+				EventID:       event.EventID,
+				EventType:     string(event.Type),
+				Event:         event,
+				Status:        models.DeliveryPending,
+				NextAttemptAt: now,
+				CreatedAt:     now,
+			})
+		}
+	}
+	return deliveries
+}
@@ -0,0 +1,83 @@
+package services
+
+import (
+	"stock-management/pkgs/setting"
+	"testing"
+	"time"
+)
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":1,"type":"product.updated"}`)
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		want      string
+	}{
+		{
+			"event",
+			"whsec-0123456789abcdef", 1700000000, body,
+			"sha256=a084d608ecda59476fc0f1747c0acb7cf00d5176c0447384a68cbfacb52407f1",
+		},
+		{
+			"empty body",
+			"whsec-0123456789abcdef", 1700000000, nil,
+			"sha256=b225047a70491323cb35ad04c6121e8c0ca18b344d6d975fc714cd1da1aa3b34",
+		},
+		{
+			"timestamp is signed",
+			"whsec-0123456789abcdef", 1700000001, body,
+			"sha256=c31f2393bff91f9d4569f05ccb80a561fd345b8a19ec21791d36113c7ca17ec9",
+		},
+		{
+			"secret is the key",
+			"another-secret-value", 1700000000, body,
+			"sha256=c8328f77e625922b7890dcbb340244c85ce25e2227c4110c7e387c78be66ffba",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := signWebhook(tt.secret, tt.timestamp, tt.body); got != tt.want {
+				t.Errorf("signWebhook(%q, %d, %q) = %s, want %s", tt.secret, tt.timestamp, tt.body, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	ws := &webhookService{cfg: setting.WebhookSetting{RetryBaseSeconds: 30, RetryMaxSeconds: 3600}}
+	tests := []struct {
+		name     string
+		cfg      *setting.WebhookSetting
+		attempts int
+		want     time.Duration
+	}{
+		{"first retry waits the base", nil, 1, 30 * time.Second},
+		{"second retry doubles", nil, 2, time.Minute},
+		{"third retry", nil, 3, 2 * time.Minute},
+		{"last retry under the cap", nil, 7, 32 * time.Minute},
+		{"capped", nil, 8, time.Hour},
+		{"capped at 30 attempts", nil, 30, time.Hour},
+		{"capped past 30 attempts", nil, 31, time.Hour},
+		{"capped past 63 attempts", nil, 64, time.Hour},
+		{"capped far past the cap", nil, 1000, time.Hour},
+		{"no attempts yet", nil, 0, 30 * time.Second},
+		{"base equal to the cap", &setting.WebhookSetting{RetryBaseSeconds: 60, RetryMaxSeconds: 60}, 3, time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ws := ws
+			if tt.cfg != nil {
+				ws = &webhookService{cfg: *tt.cfg}
+			}
+			// Jitter adds up to 10%, so sample a few delays.
+			for i := 0; i < 20; i++ {
+				got := ws.retryDelay(tt.attempts)
+				if got < tt.want || got > tt.want+tt.want/10 {
+					t.Fatalf("retryDelay(%d) = %v, want between %v and %v", tt.attempts, got, tt.want, tt.want+tt.want/10)
+				}
+			}
+		})
+	}
+}
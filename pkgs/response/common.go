@@ -3,33 +3,136 @@ package response
 type RespCode uint32
 
 const (
-	OkCode        RespCode = 200
-	ErrInternal   RespCode = 500
-	ErrBadRequest RespCode = 400
+	OkCode          RespCode = 200
+	ErrInternal     RespCode = 500
+	ErrBadRequest   RespCode = 400
+	ErrUnauthorized RespCode = 401
+	ErrForbidden    RespCode = 403
+	// ErrVersionConflict is returned when an update was based on a stale version of the entity.
+	ErrVersionConflict RespCode = 409
 
-	Err                     RespCode = 3000
-	ErrInvalidName          RespCode = 3001
-	ErrInvalidCategory      RespCode = 3002
-	ErrInvalidSupplier      RespCode = 3003
-	ErrInvalidProduct       RespCode = 3004
-	ErrInvalidStatus        RespCode = 3005
-	ErrInvalidStockLocation RespCode = 3006
-	ErrInvalidReference     RespCode = 3007
-	ErrInvalidDate          RespCode = 2008
+	Err                      RespCode = 3000
+	ErrInvalidName           RespCode = 3001
+	ErrInvalidCategory       RespCode = 3002
+	ErrInvalidSupplier       RespCode = 3003
+	ErrInvalidProduct        RespCode = 3004
+	ErrInvalidStatus         RespCode = 3005
+	ErrInvalidStockLocation  RespCode = 3006
+	ErrInvalidReference      RespCode = 3007
+	ErrInvalidMetric         RespCode = 3008
+	ErrInvalidBucket         RespCode = 3009
+	ErrInvalidGroupBy        RespCode = 3010
+	ErrInvalidMovementType   RespCode = 3011
+	ErrInvalidQuantity       RespCode = 3012
+	ErrInvalidDays           RespCode = 3013
+	ErrInvalidAbcClass       RespCode = 3014
+	ErrInvalidThreshold      RespCode = 3015
+	ErrInvalidForecastMethod RespCode = 3016
+	ErrInvalidForecastParams RespCode = 3017
+	ErrInvalidLeadTime       RespCode = 3018
+	ErrInvalidCredentials    RespCode = 3019
+	ErrInvalidToken          RespCode = 3020
+	ErrInvalidPassword       RespCode = 3021
+	ErrInvalidApiKey         RespCode = 3022
+	ErrInvalidRole           RespCode = 3023
+	ErrInvalidPermission     RespCode = 3024
+	ErrInvalidUser           RespCode = 3025
+	ErrInvalidEntity         RespCode = 3026
+	ErrInvalidVersion        RespCode = 3027
+	ErrInvalidPrice          RespCode = 3028
+	ErrInvalidIdempotencyKey RespCode = 3029
+	ErrIdempotencyInProgress RespCode = 3030
+	ErrIdempotencyKeyReused  RespCode = 3031
+	ErrDuplicateReference    RespCode = 3032
+	ErrInvalidBarcode        RespCode = 3033
+	ErrDuplicateBarcode      RespCode = 3034
+	ErrInvalidLabelSize      RespCode = 3035
+	ErrInvalidSymbology      RespCode = 3036
+	ErrTooLong               RespCode = 3037
+	ErrInvalidSort           RespCode = 3038
+	ErrInvalidCursor         RespCode = 3039
+	ErrInvalidSavedSearch    RespCode = 3040
+	ErrDuplicateName         RespCode = 3041
+	ErrInvalidCron           RespCode = 3042
+	ErrInvalidReportFormat   RespCode = 3043
+	ErrInvalidDelivery       RespCode = 3044
+	ErrInvalidRecipient      RespCode = 3045
+	ErrInvalidSchedule       RespCode = 3046
+	ErrInvalidFilter         RespCode = 3047
+	ErrInvalidEventPosition  RespCode = 3048
+	ErrInvalidEventType      RespCode = 3049
+	ErrInvalidEventGroup     RespCode = 3050
+	ErrInvalidWebhook        RespCode = 3051
+	ErrInvalidWebhookUrl     RespCode = 3052
+	ErrInvalidWebhookSecret  RespCode = 3053
+	ErrInvalidDeliveryID     RespCode = 3054
+	ErrInvalidLimit          RespCode = 3055
+	ErrInvalidDate           RespCode = 2008
 )
 
 var msg = map[RespCode]string{
-	OkCode:        "success",
-	ErrInternal:   "Internal server error",
-	ErrBadRequest: "Bad qequest",
+	OkCode:             "success",
+	ErrInternal:        "Internal server error",
+	ErrBadRequest:      "Bad request",
+	ErrUnauthorized:    "Unauthorized",
+	ErrForbidden:       "Forbidden",
+	ErrVersionConflict: "Modified by another request, reload and retry",
 
-	Err:                     "Error",
-	ErrInvalidDate:          "Date is invalid",
-	ErrInvalidName:          "Name is invalid",
-	ErrInvalidCategory:      "Category is invalid",
-	ErrInvalidSupplier:      "Supplier is invalid",
-	ErrInvalidProduct:       "Product is invalid",
-	ErrInvalidStatus:        "Status is invalid",
-	ErrInvalidStockLocation: "Stock Location is invalid",
-	ErrInvalidReference:     "Reference  is invalid",
+	Err:                      "Error",
+	ErrInvalidDate:           "Date is invalid",
+	ErrInvalidName:           "Name is invalid",
+	ErrInvalidCategory:       "Category is invalid",
+	ErrInvalidSupplier:       "Supplier is invalid",
+	ErrInvalidProduct:        "Product is invalid",
+	ErrInvalidStatus:         "Status is invalid",
+	ErrInvalidStockLocation:  "Stock location is invalid",
+	ErrInvalidReference:      "Reference is invalid",
+	ErrInvalidMetric:         "Metric is invalid",
+	ErrInvalidBucket:         "Bucket is invalid",
+	ErrInvalidGroupBy:        "Group by is invalid",
+	ErrInvalidMovementType:   "Movement type is invalid",
+	ErrInvalidQuantity:       "Quantity is invalid",
+	ErrInvalidDays:           "Days is invalid",
+	ErrInvalidAbcClass:       "ABC class is invalid",
+	ErrInvalidThreshold:      "Threshold is invalid",
+	ErrInvalidForecastMethod: "Forecast method is invalid",
+	ErrInvalidForecastParams: "Forecast parameter is invalid",
+	ErrInvalidLeadTime:       "Lead time is invalid",
+	ErrInvalidCredentials:    "Username or password is invalid",
+	ErrInvalidToken:          "Token is invalid or expired",
+	ErrInvalidPassword:       "Password must have at least 8 characters",
+	ErrInvalidApiKey:         "API key is invalid",
+	ErrInvalidRole:           "Role is invalid",
+	ErrInvalidPermission:     "Permission is invalid",
+	ErrInvalidUser:           "User is invalid",
+	ErrInvalidEntity:         "Entity is invalid",
+	ErrInvalidVersion:        "Version is invalid",
+	ErrInvalidPrice:          "Price is invalid",
+	ErrInvalidIdempotencyKey: "Idempotency key is invalid",
+	ErrIdempotencyInProgress: "A request with this idempotency key is still in progress",
+	ErrIdempotencyKeyReused:  "Idempotency key was already used with a different request",
+	ErrDuplicateReference:    "Reference already exists",
+	ErrInvalidBarcode:        "Barcode is invalid",
+	ErrDuplicateBarcode:      "Barcode already exists",
+	ErrInvalidLabelSize:      "Label size is invalid",
+	ErrInvalidSymbology:      "Symbology is invalid",
+	ErrTooLong:               "Value is too long",
+	ErrInvalidSort:           "Sort is invalid",
+	ErrInvalidCursor:         "Cursor is invalid",
+	ErrInvalidSavedSearch:    "Saved search is invalid",
+	ErrDuplicateName:         "Name already exists",
+	ErrInvalidCron:           "Cron expression is invalid",
+	ErrInvalidReportFormat:   "Report format is invalid",
+	ErrInvalidDelivery:       "Delivery is invalid",
+	ErrInvalidRecipient:      "Recipient email is invalid",
+	ErrInvalidSchedule:       "Report schedule is invalid",
+	ErrInvalidFilter:         "Filter expression is invalid",
+	ErrInvalidEventPosition:  "Event stream position is invalid",
+	ErrInvalidEventType:      "Event type is invalid",
+	ErrInvalidEventGroup:     "Event consumer group is invalid",
+	ErrInvalidWebhook:        "Webhook is invalid",
+	ErrInvalidWebhookUrl:     "Webhook URL must be an absolute http or https URL",
+	ErrInvalidWebhookSecret:  "Webhook secret must be at least 16 characters",
+	ErrInvalidDeliveryID:     "Webhook delivery is invalid",
+	ErrInvalidLimit:          "Limit is invalid",
 }
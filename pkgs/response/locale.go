@@ -0,0 +1,102 @@
+package response
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/text/language"
+	"gopkg.in/yaml.v3"
+)
+
+const localeKey = "Locale"
+
+var (
+	defaultLocale  = "en"
+	catalogs       = map[string]map[RespCode]string{}
+	catalogLocales = []string{"en"}
+	localeMatcher  = language.NewMatcher([]language.Tag{language.English})
+)
+
+// LoadCatalogs loads the message catalogs of dir, one <locale>.yaml file per locale mapping
+// response codes to messages. Requests get the catalog that best matches their
+// Accept-Language header and defaultLang otherwise. Codes missing from a catalog fall back to
+// the built-in English messages. It must be called before serving.
+func LoadCatalogs(dir, defaultLang string) error {
+	files, err := filepath.Glob(filepath.Join(dir, "*.yaml"))
+	if err != nil {
+		return err
+	}
+
+	loaded := make(map[string]map[RespCode]string, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		var catalog map[RespCode]string
+		if err := yaml.Unmarshal(data, &catalog); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		locale := strings.TrimSuffix(filepath.Base(file), filepath.Ext(file))
+		if _, err := language.Parse(locale); err != nil {
+			return fmt.Errorf("%s: %w", file, err)
+		}
+		loaded[locale] = catalog
+	}
+
+	if defaultLang == "" {
+		defaultLang = "en"
+	}
+	// The matcher falls back to its first tag.
+	locales := []string{defaultLang}
+	tags := []language.Tag{language.Make(defaultLang)}
+	for locale := range loaded {
+		if locale != defaultLang {
+			locales = append(locales, locale)
+			tags = append(tags, language.Make(locale))
+		}
+	}
+	if _, ok := loaded["en"]; !ok && defaultLang != "en" {
+		// The built-in messages are English.
+		locales = append(locales, "en")
+		tags = append(tags, language.English)
+	}
+
+	defaultLocale = defaultLang
+	catalogs = loaded
+	catalogLocales = locales
+	localeMatcher = language.NewMatcher(tags)
+	return nil
+}
+
+// Locale returns the locale of the request, picked from its Accept-Language header.
+func Locale(c *gin.Context) string {
+	if locale := c.GetString(localeKey); locale != "" {
+		return locale
+	}
+
+	locale := defaultLocale
+	if header := c.GetHeader("Accept-Language"); header != "" {
+		tags, _, _ := language.ParseAcceptLanguage(header)
+		if len(tags) > 0 {
+			_, index, confidence := localeMatcher.Match(tags...)
+			if confidence != language.No {
+				locale = catalogLocales[index]
+			}
+		}
+	}
+	c.Set(localeKey, locale)
+	c.Header("Content-Language", locale)
+	return locale
+}
+
+// message returns the message of code in the locale of the request.
+func message(c *gin.Context, code RespCode) string {
+	if text, ok := catalogs[Locale(c)][code]; ok {
+		return text
+	}
+	return msg[code]
+}
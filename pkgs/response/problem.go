@@ -0,0 +1,70 @@
+package response
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	problemDetailsKey  = "ProblemDetails"
+	ProblemContentType = "application/problem+json"
+)
+
+// ProblemDetails is an RFC 7807 error body, extended with the response code and trace ID.
+type ProblemDetails struct {
+	Type     string   `json:"type"`
+	Title    string   `json:"title"`
+	Status   int      `json:"status"`
+	Detail   string   `json:"detail,omitempty"`
+	Instance string   `json:"instance,omitempty"`
+	Code     RespCode `json:"code"`
+	TraceID  string   `json:"trace_id,omitempty"`
+
+	Errors FieldErrors `json:"errors,omitempty"`
+}
+
+// EnableProblemDetails makes the abort helpers of this package (UnauthorizedResponse,
+// ForbiddenResponse, FailResponseWithStatus...) answer with problem details for this request.
+func EnableProblemDetails(c *gin.Context) {
+	c.Set(problemDetailsKey, true)
+}
+
+// ProblemResponse aborts the request with a problem details body. detail defaults to the
+// message of code.
+func ProblemResponse(c *gin.Context, status int, code RespCode, detail string) {
+	if detail == "" {
+		detail = message(c, code)
+	}
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(status, newProblem(c, status, code, detail))
+}
+
+func newProblem(c *gin.Context, status int, code RespCode, detail string) ProblemDetails {
+	return ProblemDetails{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Instance: c.Request.URL.Path,
+		Code:     code,
+		TraceID:  c.GetString("TraceID"),
+	}
+}
+
+func abortWithCode(c *gin.Context, status int, code RespCode) {
+	if ProblemDetailsEnabled(c) {
+		ProblemResponse(c, status, code, "")
+		return
+	}
+	c.AbortWithStatusJSON(status, ResponseData{
+		Code:    code,
+		Message: message(c, code),
+		Data:    nil,
+	})
+}
+
+// ProblemDetailsEnabled reports whether EnableProblemDetails was called for this request.
+func ProblemDetailsEnabled(c *gin.Context) bool {
+	return c.GetBool(problemDetailsKey)
+}
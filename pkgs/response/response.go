@@ -10,12 +10,14 @@ type ResponseData struct {
 	Code    RespCode    `json:"code"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data"`
+	// Errors lists every invalid field when the request failed validation.
+	Errors FieldErrors `json:"errors,omitempty"`
 }
 
 func NewResponse(c *gin.Context, code RespCode, data interface{}) {
 	c.JSON(http.StatusOK, ResponseData{
 		Code:    code,
-		Message: msg[code],
+		Message: message(c, code),
 		Data:    data,
 	})
 }
@@ -23,21 +25,37 @@ func NewResponse(c *gin.Context, code RespCode, data interface{}) {
 func SuccessResponse(c *gin.Context, data interface{}) {
 	c.JSON(http.StatusOK, ResponseData{
 		Code:    OkCode,
-		Message: msg[OkCode],
+		Message: message(c, OkCode),
 		Data:    data,
 	})
 }
 func FailResponseWithCode(c *gin.Context, code RespCode) {
 	c.JSON(http.StatusOK, ResponseData{
 		Code:    code,
-		Message: msg[code],
+		Message: message(c, code),
 		Data:    nil,
 	})
 }
+func UnauthorizedResponse(c *gin.Context, code RespCode) {
+	abortWithCode(c, http.StatusUnauthorized, code)
+}
+
+func ForbiddenResponse(c *gin.Context) {
+	abortWithCode(c, http.StatusForbidden, ErrForbidden)
+}
+
+func FailResponseWithStatus(c *gin.Context, status int, code RespCode) {
+	abortWithCode(c, status, code)
+}
+
+func PreconditionFailedResponse(c *gin.Context, code RespCode) {
+	abortWithCode(c, http.StatusPreconditionFailed, code)
+}
+
 func FailResponseWithMessage(c *gin.Context, msgs string) {
 	c.JSON(http.StatusOK, ResponseData{
 		Code:    Err,
-		Message: msg[Err] + " " + msgs,
+		Message: message(c, Err) + " " + msgs,
 		Data:    nil,
 	})
 }
@@ -0,0 +1,71 @@
+package response
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// FieldError is a validation failure of one request field.
+type FieldError struct {
+	Field   string   `json:"field"`
+	Code    RespCode `json:"code"`
+	Message string   `json:"message"`
+}
+
+// FieldErrors collects every invalid field of a request, in field order.
+type FieldErrors []FieldError
+
+// Add appends a failure of field with the message of code. The message is translated to the
+// locale of the request when it is sent.
+func (e *FieldErrors) Add(field string, code RespCode) {
+	*e = append(*e, FieldError{Field: field, Code: code, Message: msg[code]})
+}
+
+// Code returns the code of the first failure, OkCode when there is none.
+func (e FieldErrors) Code() RespCode {
+	if len(e) == 0 {
+		return OkCode
+	}
+	return e[0].Code
+}
+
+func (e FieldErrors) Error() string {
+	parts := make([]string, len(e))
+	for i, fieldErr := range e {
+		parts[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ValidationFailResponse responds with the code and message of the first failure and the
+// list of all of them in errors.
+func ValidationFailResponse(c *gin.Context, errs FieldErrors) {
+	code := errs.Code()
+	c.JSON(http.StatusOK, ResponseData{
+		Code:    code,
+		Message: message(c, code),
+		Data:    nil,
+		Errors:  errs.localize(c),
+	})
+}
+
+// ValidationProblemResponse is ValidationFailResponse for problem details: 422 with the
+// failures in errors.
+func ValidationProblemResponse(c *gin.Context, errs FieldErrors) {
+	code := errs.Code()
+	problem := newProblem(c, http.StatusUnprocessableEntity, code, message(c, code))
+	problem.Errors = errs.localize(c)
+	c.Header("Content-Type", ProblemContentType)
+	c.AbortWithStatusJSON(http.StatusUnprocessableEntity, problem)
+}
+
+func (e FieldErrors) localize(c *gin.Context) FieldErrors {
+	localized := make(FieldErrors, len(e))
+	for i, fieldErr := range e {
+		fieldErr.Message = message(c, fieldErr.Code)
+		localized[i] = fieldErr
+	}
+	return localized
+}
@@ -6,6 +6,14 @@ type SettingConfig struct {
 	Logger     LoggerSetting     `mapstructure:"logger"`
 	Server     ServerSetting     `mapstructure:"server"`
 	Cache      RedisSetting      `mapstructure:"redis"`
+	Job        JobSetting        `mapstructure:"job"`
+	Auth       AuthSetting       `mapstructure:"auth"`
+	Product    ProductSetting    `mapstructure:"product"`
+	Label      LabelSetting      `mapstructure:"label"`
+	Locale     LocaleSetting     `mapstructure:"locale"`
+	Report     ReportSetting     `mapstructure:"report"`
+	Event      EventSetting      `mapstructure:"event"`
+	Webhook    WebhookSetting    `mapstructure:"webhook"`
 }
 
 type RedisSetting struct {
@@ -14,6 +22,11 @@ type RedisSetting struct {
 	Password string `mapstructure:"password"`
 	DBname   int    `mapstructure:"dbname"`
 	PoolSize int    `mapstructure:"poolSize"`
+	// seconds a stored Idempotency-Key response is replayed
+	IdempotencyTTL int `mapstructure:"idempotencyTTL"`
+	// seconds a product and a product list page are cached, 0 disables the cache
+	ProductTTL     int `mapstructure:"productTTL"`
+	ProductListTTL int `mapstructure:"productListTTL"`
 }
 
 type MySqlSetting struct {
@@ -51,3 +64,92 @@ type ServerSetting struct {
 	Port int    `mapstructure:"port"`
 	Mode string `mapstructure:"mode"`
 }
+
+type JobSetting struct {
+	InventorySnapshotCron string `mapstructure:"inventorySnapshotCron"`
+	// how often the outbox relay looks for unpublished events, e.g. "@every 1s"
+	OutboxRelayCron string `mapstructure:"outboxRelayCron"`
+	// days published outbox events are kept
+	OutboxRetentionDays int `mapstructure:"outboxRetentionDays"`
+	// attempts after which an outbox event is marked failed and skipped, 0 retries forever
+	OutboxMaxAttempts int `mapstructure:"outboxMaxAttempts"`
+	// how often due webhook deliveries are sent, e.g. "@every 1s"
+	WebhookDispatchCron string `mapstructure:"webhookDispatchCron"`
+	// how often report schedules saved on other instances are picked up, e.g. "@every 1m"
+	ReportScheduleSyncCron string `mapstructure:"reportScheduleSyncCron"`
+}
+
+type AuthSetting struct {
+	JwtSecret       string `mapstructure:"jwtSecret"`
+	AccessTokenTTL  int    `mapstructure:"accessTokenTTL"`
+	RefreshTokenTTL int    `mapstructure:"refreshTokenTTL"`
+	AdminUsername   string `mapstructure:"adminUsername"`
+	AdminPassword   string `mapstructure:"adminPassword"`
+}
+
+type ProductSetting struct {
+	Reference ReferenceSetting `mapstructure:"reference"`
+	// lower bounds of the price facet buckets, ascending
+	PriceBuckets []int `mapstructure:"priceBuckets"`
+}
+
+type ReferenceSetting struct {
+	Prefix     string `mapstructure:"prefix"`
+	DateFormat string `mapstructure:"dateFormat"`
+	Padding    int    `mapstructure:"padding"`
+	CheckDigit bool   `mapstructure:"checkDigit"`
+}
+
+type LabelSetting struct {
+	PageSize    string               `mapstructure:"pageSize"`
+	Margin      float64              `mapstructure:"margin"`
+	DefaultSize string               `mapstructure:"defaultSize"`
+	Sizes       map[string]LabelSize `mapstructure:"sizes"`
+}
+
+// LabelSize is the size of one label in mm.
+type LabelSize struct {
+	Width  float64 `mapstructure:"width"`
+	Height float64 `mapstructure:"height"`
+}
+
+// LocaleSetting is where the message catalogs are and the locale used when Accept-Language
+// matches none of them.
+type LocaleSetting struct {
+	Path    string `mapstructure:"path"`
+	Default string `mapstructure:"default"`
+}
+
+// ReportSetting is where scheduled reports are delivered: files are written to Directory and
+// emails are sent through Smtp.
+type ReportSetting struct {
+	Directory string      `mapstructure:"directory"`
+	Smtp      SmtpSetting `mapstructure:"smtp"`
+}
+
+type SmtpSetting struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+}
+
+// EventSetting is the Redis stream domain events are published to. The stream is trimmed to
+// about MaxLen events, 0 keeps them all.
+type EventSetting struct {
+	Stream string `mapstructure:"stream"`
+	MaxLen int64  `mapstructure:"maxLen"`
+}
+
+// WebhookSetting is how webhook deliveries are sent and retried. Retry n waits
+// RetryBaseSeconds * 2^(n-1), at most RetryMaxSeconds; after MaxAttempts attempts a delivery
+// is dead.
+type WebhookSetting struct {
+	TimeoutSeconds   int `mapstructure:"timeoutSeconds"`
+	MaxAttempts      int `mapstructure:"maxAttempts"`
+	RetryBaseSeconds int `mapstructure:"retryBaseSeconds"`
+	RetryMaxSeconds  int `mapstructure:"retryMaxSeconds"`
+	// days delivered deliveries are kept in the log
+	RetentionDays int `mapstructure:"retentionDays"`
+}
@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// GS1CheckDigit returns the GS1 mod-10 check digit of a digit string: digits are weighted
+// 3 and 1 alternately from the right. It is used by EAN-8, EAN-13 and UPC-A.
+func GS1CheckDigit(digits string) int {
+	sum := 0
+	for i := len(digits) - 1; i >= 0; i-- {
+		d := int(digits[i] - '0')
+		if (len(digits)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// IsValidBarcode reports whether code is an EAN-8, UPC-A (12 digits) or EAN-13 code
+// with a correct check digit.
+func IsValidBarcode(code string) bool {
+	if len(code) != 8 && len(code) != 12 && len(code) != 13 {
+		return false
+	}
+	for _, r := range code {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return GS1CheckDigit(code[:len(code)-1]) == int(code[len(code)-1]-'0')
+}
+
+// BarcodeVariants returns the codes equal to code as GTINs: a UPC-A code is the EAN-13
+// code with a leading zero.
+func BarcodeVariants(code string) []string {
+	switch {
+	case len(code) == 12:
+		return []string{code, "0" + code}
+	case len(code) == 13 && strings.HasPrefix(code, "0"):
+		return []string{code, code[1:]}
+	}
+	return []string{code}
+}
+
+// FormatReference builds a reference "{prefix}-{date}-{sequence}" with the sequence
+// zero-padded to padding digits and, if checkDigit is set, followed by its GS1 check digit.
+// An empty prefix or date format leaves that part out.
+func FormatReference(prefix, dateFormat string, padding int, checkDigit bool, sequence int64, now time.Time) string {
+	seq := fmt.Sprintf("%0*d", padding, sequence)
+	if checkDigit {
+		seq += fmt.Sprint(GS1CheckDigit(seq))
+	}
+
+	parts := make([]string, 0, 3)
+	if prefix != "" {
+		parts = append(parts, prefix)
+	}
+	if dateFormat != "" {
+		parts = append(parts, now.Format(dateFormat))
+	}
+	parts = append(parts, seq)
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,110 @@
+package utils
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestGS1CheckDigit(t *testing.T) {
+	tests := []struct {
+		name   string
+		digits string
+		want   int
+	}{
+		{"EAN-13", "400638133393", 1},
+		{"EAN-13 Poland", "590123412345", 7},
+		{"EAN-13 zero check digit", "978030640615", 7},
+		{"UPC-A", "03600029145", 2},
+		{"UPC-A zeros", "01234567890", 5},
+		{"EAN-8", "9638507", 4},
+		{"UPC-A as EAN-13", "003600029145", 2},
+		{"single digit", "1", 7},
+		{"all zeros", "000000000000", 0},
+		{"empty", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GS1CheckDigit(tt.digits); got != tt.want {
+				t.Errorf("GS1CheckDigit(%q) = %d, want %d", tt.digits, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsValidBarcode(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{"EAN-13", "4006381333931", true},
+		{"EAN-13 Poland", "5901234123457", true},
+		{"EAN-13 wrong check digit", "4006381333932", false},
+		{"EAN-13 swapped digits", "4006381339331", false},
+		{"UPC-A", "036000291452", true},
+		{"UPC-A zeros", "012345678905", true},
+		{"UPC-A wrong check digit", "036000291453", false},
+		{"UPC-A with leading zero", "0036000291452", true},
+		{"EAN-8", "96385074", true},
+		{"EAN-8 wrong check digit", "96385075", false},
+		{"too short", "1234567", false},
+		{"GTIN-14", "10036000291459", false},
+		{"letters", "40063813339A1", false},
+		{"spaces", " 4006381333931", false},
+		{"empty", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsValidBarcode(tt.code); got != tt.want {
+				t.Errorf("IsValidBarcode(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBarcodeVariants(t *testing.T) {
+	tests := []struct {
+		code string
+		want []string
+	}{
+		{"036000291452", []string{"036000291452", "0036000291452"}},
+		{"0036000291452", []string{"0036000291452", "036000291452"}},
+		{"4006381333931", []string{"4006381333931"}},
+		{"96385074", []string{"96385074"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			if got := BarcodeVariants(tt.code); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("BarcodeVariants(%q) = %v, want %v", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormatReference(t *testing.T) {
+	now := time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name       string
+		prefix     string
+		dateFormat string
+		padding    int
+		checkDigit bool
+		sequence   int64
+		want       string
+	}{
+		{"full", "PRD", "20060102", 6, false, 42, "PRD-20240309-000042"},
+		{"check digit", "PRD", "", 6, true, 43, "PRD-0000437"},
+		{"no prefix", "", "2006", 4, false, 7, "2024-0007"},
+		{"sequence longer than padding", "PO", "", 2, false, 12345, "PO-12345"},
+		{"sequence only", "", "", 0, false, 1, "1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FormatReference(tt.prefix, tt.dateFormat, tt.padding, tt.checkDigit, tt.sequence, now)
+			if got != tt.want {
+				t.Errorf("FormatReference() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
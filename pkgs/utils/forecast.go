@@ -0,0 +1,32 @@
+package utils
+
+// MovingAverage returns the mean of the last window values of the series,
+// or of the whole series when it is shorter than the window.
+func MovingAverage(series []float64, window int) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+	if window <= 0 || window > len(series) {
+		window = len(series)
+	}
+
+	var sum float64
+	for _, v := range series[len(series)-window:] {
+		sum += v
+	}
+	return sum / float64(window)
+}
+
+// ExponentialSmoothing returns the simple exponential smoothing level of the series,
+// which is the forecast for every following period. alpha must be in (0, 1].
+func ExponentialSmoothing(series []float64, alpha float64) float64 {
+	if len(series) == 0 {
+		return 0
+	}
+
+	level := series[0]
+	for _, v := range series[1:] {
+		level = alpha*v + (1-alpha)*level
+	}
+	return level
+}
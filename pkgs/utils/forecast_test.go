@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMovingAverage(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []float64
+		window int
+		want   float64
+	}{
+		{"last window values", []float64{10, 2, 4, 6}, 3, 4},
+		{"window of one", []float64{1, 2, 9}, 1, 9},
+		{"window equal to series", []float64{1, 2, 3}, 3, 2},
+		{"window longer than series", []float64{1, 2, 3}, 7, 2},
+		{"zero window uses whole series", []float64{2, 4}, 0, 3},
+		{"negative window uses whole series", []float64{2, 4}, -1, 3},
+		{"fractional mean", []float64{1, 2}, 2, 1.5},
+		{"zeros", []float64{0, 0, 0}, 2, 0},
+		{"empty series", nil, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := MovingAverage(tt.series, tt.window); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("MovingAverage(%v, %d) = %v, want %v", tt.series, tt.window, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExponentialSmoothing(t *testing.T) {
+	tests := []struct {
+		name   string
+		series []float64
+		alpha  float64
+		want   float64
+	}{
+		{"single value", []float64{5}, 0.3, 5},
+		{"alpha one follows last value", []float64{1, 8, 3}, 1, 3},
+		{"constant series", []float64{4, 4, 4, 4}, 0.3, 4},
+		{"two values", []float64{10, 20}, 0.5, 15},
+		{"recent values weigh more", []float64{10, 20, 30}, 0.5, 22.5},
+		{"default alpha", []float64{0, 10, 10}, 0.3, 5.1},
+		{"empty series", nil, 0.3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExponentialSmoothing(tt.series, tt.alpha); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("ExponentialSmoothing(%v, %v) = %v, want %v", tt.series, tt.alpha, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"stock-management/pkgs/setting"
+	"strings"
+)
+
+// MailAttachment is a file attached to a mail.
+type MailAttachment struct {
+	FileName    string
+	ContentType string
+	Data        []byte
+}
+
+// SendMail sends a plain text mail with attachments through the SMTP server of cfg. The server
+// is authenticated with PLAIN when a username is set.
+func SendMail(cfg setting.SmtpSetting, to []string, subject, body string, attachments ...MailAttachment) error {
+	from, err := mail.ParseAddress(cfg.From)
+	if err != nil {
+		return fmt.Errorf("invalid smtp from address: %w", err)
+	}
+
+	var msg bytes.Buffer
+	w := multipart.NewWriter(&msg)
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/mixed; boundary=%s\r\n\r\n", w.Boundary())
+
+	part, err := w.CreatePart(textproto.MIMEHeader{"Content-Type": {"text/plain; charset=utf-8"}})
+	if err != nil {
+		return err
+	}
+	if _, err := part.Write([]byte(body)); err != nil {
+		return err
+	}
+
+	for _, attachment := range attachments {
+		part, err := w.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {mime.FormatMediaType(attachment.ContentType, map[string]string{"name": attachment.FileName})},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": attachment.FileName})},
+			"Content-Transfer-Encoding": {"base64"},
+		})
+		if err != nil {
+			return err
+		}
+		encoded := base64.StdEncoding.EncodeToString(attachment.Data)
+		for len(encoded) > 76 {
+			if _, err := part.Write([]byte(encoded[:76] + "\r\n")); err != nil {
+				return err
+			}
+			encoded = encoded[76:]
+		}
+		if _, err := part.Write([]byte(encoded + "\r\n")); err != nil {
+			return err
+		}
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	addr := fmt.Sprintf("%s:%d", cfg.Host, cfg.Port)
+	return smtp.SendMail(addr, auth, from.Address, to, msg.Bytes())
+}